@@ -34,10 +34,10 @@ import {
 import {
   Switch
 } from "@/components/ui/switch"
-import { useState } from "react"
+import { useEffect, useState } from "react"
 import api from "@/lib/api"
 import { useParams } from "react-router"
-import { Alert } from "@/models/alert"
+import { Alert, NotificationChannel } from "@/models/alert"
 
 interface AlertFromProps {
   onFinished: () => void;
@@ -49,6 +49,7 @@ export function AlertFrom(props: AlertFromProps) {
   const { id } = useParams<{ id: string }>();
 
   const [isPending, setIsPending] = useState(false)
+  const [channels, setChannels] = useState<NotificationChannel[]>([])
 
   const form = useForm<z.infer<typeof formSchema>>({
     resolver: zodResolver(formSchema),
@@ -60,14 +61,23 @@ export function AlertFrom(props: AlertFromProps) {
       net_rece_threshold: props.alert?.net_rece_threshold.Float64,
       net_send_threshold: props.alert?.net_send_threshold.Float64,
       duration: props.alert?.duration,
-      email: props.alert?.email.String,
-      discord: props.alert?.discord_webhook.String,
-      slack: props.alert?.slack_webhook.String,
+      channel_ids: [],
       enabled: props.alert?.is_active.Bool
 
     },
   })
 
+  useEffect(() => {
+    api.get('/notification-channels').then((res) => {
+      setChannels(res.data.data ?? [])
+    })
+    if (props.alert?.id) {
+      api.get(`/alerts/${props.alert.id}/channels`).then((res) => {
+        form.setValue("channel_ids", (res.data.data ?? []).map((channel: NotificationChannel) => channel.id))
+      })
+    }
+  }, [props.alert?.id])
+
   const onSubmit = () => {
 
     setIsPending(true)
@@ -225,67 +235,25 @@ export function AlertFrom(props: AlertFromProps) {
           />
           <FormField
             control={form.control}
-            name="email"
-            render={({ field }) => (
-              <FormItem className="w-full">
-                <FormLabel>Email</FormLabel>
-                <FormControl>
-                  <Input
-                    placeholder="Email"
-                    type={"email"}
-                    value={field.value}
-                    onChange={(e) => {
-                      const val = e.target.value;
-                      field.onChange(val);
-                    }}
-                  />
-                </FormControl>
-
-                <FormMessage />
-              </FormItem>
-            )
-            }
-          />
-          <FormField
-            control={form.control}
-            name="discord"
-            render={({ field }) => (
-              <FormItem className="w-full">
-                <FormLabel>Discord Webhook</FormLabel>
-                <FormControl>
-                  <Input
-                    placeholder="Discord Webhook"
-                    type={"url"}
-                    value={field.value}
-                    onChange={(e) => {
-                      const val = e.target.value;
-                      field.onChange(val);
-                    }}
-                  />
-                </FormControl>
-
-                <FormMessage />
-              </FormItem>
-            )
-            }
-          />
-          <FormField
-            control={form.control}
-            name="slack"
+            name="channel_ids"
             render={({ field }) => (
               <FormItem className="w-full">
-                <FormLabel>Slack Webhook</FormLabel>
-                <FormControl>
-                  <Input
-                    placeholder="Slack Webhook"
-                    type={"url"}
-                    value={field.value}
-                    onChange={(e) => {
-                      const val = e.target.value;
-                      field.onChange(val);
-                    }}
-                  />
-                </FormControl>
+                <FormLabel>Notification Channels</FormLabel>
+                {channels.length == 0 ? <div className="text-sm text-gray-500">No notification channels yet</div> : null}
+                {channels.map((channel) => (
+                  <div key={channel.id} className="flex items-center justify-between p-2 border rounded">
+                    <div className="text-sm">{channel.name} <span className="text-gray-500">({channel.type})</span></div>
+                    <FormControl>
+                      <Switch
+                        checked={field.value?.includes(channel.id)}
+                        onCheckedChange={(checked) => {
+                          const ids = (field.value ?? []).filter((id) => id != channel.id)
+                          field.onChange(checked ? [...ids, channel.id] : ids)
+                        }}
+                      />
+                    </FormControl>
+                  </div>
+                ))}
 
                 <FormMessage />
               </FormItem>
@@ -16,8 +16,6 @@ export const formSchema = z.object({
   "net_rece_threshold": z.number().optional(),
   "net_send_threshold": z.number().optional(),
   "duration": z.number().optional(),
-  "email": z.string().optional(),
-  "discord": z.string().optional(),
-  "slack": z.string().optional(),
+  "channel_ids": z.array(z.number()).optional(),
   "enabled": z.boolean().optional()
 });
\ No newline at end of file
@@ -6,14 +6,17 @@ export interface Alert {
   threshold:          Threshold;
   net_rece_threshold: Threshold;
   net_send_threshold: Threshold;
-  email:              PgString;
-  discord_webhook:    PgString;
-  slack_webhook:      PgString;
   is_active:          IsActive;
   created_at:         Date;
   updated_at:         Date;
 }
 
+export interface NotificationChannel {
+  id:   number;
+  name: string;
+  type: string;
+}
+
 export interface PgString {
   String: string;
   Valid:  boolean;
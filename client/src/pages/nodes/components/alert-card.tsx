@@ -3,7 +3,6 @@ import { Badge } from "@/components/ui/badge";
 import { Button } from "@/components/ui/button";
 import { Card, CardContent } from "@/components/ui/card";
 import { Edit, Trash2 } from "lucide-react";
-import { SocialIcon } from "react-social-icons";
 
 
 interface AlertCardProps {
@@ -13,9 +12,6 @@ interface AlertCardProps {
   value: number;
   net_sent: number;
   net_recv: number;
-  email?: string;
-  discord?: string;
-  slack?: string;
   onEditClick?: (id: number) => void;
   onDeleteClick?: (id: number) => void;
 }
@@ -60,12 +56,6 @@ export default function AlertCard(props: AlertCardProps) {
             </div>
             <div className="text-sm text-gray-500">{props.matric == "net" ? "Sent: " + props.net_sent + " B/s | Recv: " + props.net_recv + " B/s" : props.value + "%"}</div>
           </div>
-          <div className="flex items-center gap-2">
-            {props.email == "" ? null : <SocialIcon network="email" style={{ height: 32, width: 32 }}></SocialIcon>}
-            {props.discord == "" ? null : <SocialIcon network="discord" style={{ height: 32, width: 32 }}></SocialIcon>}
-            {props.slack == "" ? null : <SocialIcon network="slack" style={{ height: 32, width: 32 }}></SocialIcon>}
-
-          </div>
         </div>
 
       </CardContent>
@@ -67,9 +67,6 @@ export default function AlertTab() {
             isEnable={alert.is_active.Bool}
             net_recv={alert.net_rece_threshold.Float64}
             net_sent={alert.net_send_threshold.Float64}
-            email={alert.email.String}
-            discord={alert.discord_webhook.String}
-            slack={alert.slack_webhook.String}
             onDeleteClick={onAlertDelete}
             onEditClick={onAlertEdit}
           ></AlertCard>
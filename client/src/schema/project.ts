@@ -1,12 +1,18 @@
 import { z } from 'zod';
 
+// releases and the current symlink are created under it, so it has to be absolute and not /
+const deployPath = z
+  .string()
+  .min(1, 'Deploy path is required')
+  .refine((path) => path.startsWith('/') && path.replace(/[/.]+/g, '') !== '', 'Deploy path must be an absolute path other than /');
+
 export const createProjectSchema = z.object({
   name: z.string().min(1, 'Project name is required').max(100, 'Name must be less than 100 characters'),
   description: z.string().max(500, 'Description must be less than 500 characters').optional(),
   node_id: z.number().int().positive('Please select a node'),
   repo_url: z.string().url('Must be a valid URL').optional().or(z.literal('')),
   branch: z.string().min(1, 'Branch is required').default('main'),
-  deploy_path: z.string().min(1, 'Deploy path is required'),
+  deploy_path: deployPath,
 });
 
 export const updateProjectSchema = z.object({
@@ -14,7 +20,7 @@ export const updateProjectSchema = z.object({
   description: z.string().max(500, 'Description must be less than 500 characters').optional(),
   repo_url: z.string().url('Must be a valid URL').optional().or(z.literal('')),
   branch: z.string().min(1, 'Branch is required'),
-  deploy_path: z.string().min(1, 'Deploy path is required'),
+  deploy_path: deployPath,
   status: z.enum(['inactive', 'cloning', 'active', 'error']).optional(),
 });
 
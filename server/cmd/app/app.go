@@ -33,13 +33,28 @@ func Run(ctx context.Context, repo *db.Repo, port string) {
 	nodeService := services.NewNodeService(ctx, repo)
 	alertService := services.NewAlertService(ctx, repo)
 	projectService := services.NewProjectService(repo, ctx)
+	agentTokenService := services.NewAgentTokenService(ctx, repo)
+	certificateService := services.NewCertificateService(ctx, repo)
+	commandService := services.NewCommandService(ctx, repo)
+	gitHubWebhookService := services.NewGitHubWebhookService(ctx, repo, projectService)
+	backupService := services.NewBackupService(ctx, repo)
+	cronService := services.NewCronService(ctx, repo)
+	logService := services.NewLogService(ctx, repo)
+	notificationChannelService := services.NewNotificationChannelService(ctx, repo)
 
 	//init handlers
 	userHandler := handlers.NewAuthHandler(userService)
 	nodeHander := handlers.NewNodeHandler(nodeService)
 	alertHandler := handlers.NewAlertHandler(alertService)
 	projectHandler := handlers.NewProjectHandler(projectService)
-	githubHandler := handlers.NewGitHubHandler(userService)
+	githubHandler := handlers.NewGitHubHandler(userService, gitHubWebhookService)
+	agentTokenHandler := handlers.NewAgentTokenHandler(agentTokenService)
+	certificateHandler := handlers.NewCertificateHandler(certificateService)
+	commandHandler := handlers.NewCommandHandler(commandService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	cronHandler := handlers.NewCronHandler(cronService)
+	logHandler := handlers.NewLogHandler(logService)
+	notificationChannelHandler := handlers.NewNotificationChannelHandler(notificationChannelService)
 
 	server := gin.Default()
 
@@ -63,6 +78,12 @@ func Run(ctx context.Context, repo *db.Repo, port string) {
 		auth.POST("/login", userHandler.Login)
 	}
 
+	//webhook routes, authenticated by their signature
+	hooks := api.Group("/hooks")
+	{
+		hooks.POST("/github", githubHandler.Webhook)
+	}
+
 	//dashboard routes
 	dashbaord := api.Group("/")
 	dashbaord.Use(middleware.JwtAuthMiddleware())
@@ -76,6 +97,7 @@ func Run(ctx context.Context, repo *db.Repo, port string) {
 			github.GET("/repos", githubHandler.GetRepos)
 			github.GET("/status", githubHandler.GetStatus)
 			github.DELETE("/token", githubHandler.DeleteToken)
+			github.GET("/webhook-events", githubHandler.GetWebhookEvents)
 		}
 
 		nodes := dashbaord.Group("/nodes")
@@ -83,12 +105,30 @@ func Run(ctx context.Context, repo *db.Repo, port string) {
 			nodes.GET("", nodeHander.GetNodes)
 			nodes.PUT("/change-name", nodeHander.UpdateName)
 			nodes.GET("/:id", nodeHander.GetNode)
+			nodes.GET("/:id/ip-history", nodeHander.GetNodeIPHistory)
+			nodes.GET("/:id/sys-info-history", nodeHander.GetNodeSysInfoHistory)
+			nodes.GET("/:id/disks", nodeHander.GetNodeDisks)
+			nodes.GET("/:id/disks/stats", nodeHander.GetNodeDiskStats)
 			nodes.GET("/ws/system-stat", nodeHander.SystemStatWSHandler)
 			nodes.GET("/:id/projects", projectHandler.ListProjectsByNode)
+			nodes.POST("/:id/projects/scan", nodeHander.ScanProjects)
+			nodes.GET("/:id/certificates", certificateHandler.GetNodeCertificates)
+			nodes.POST("/:id/certificates", certificateHandler.IssueNodeCertificate)
+			nodes.POST("/:id/commands", commandHandler.SendCommand)
+			nodes.GET("/:id/ws/exec", commandHandler.ExecWSHandler)
+			nodes.GET("/:id/executions", commandHandler.GetExecutions)
+			nodes.GET("/:id/log-files", logHandler.ListNodeLogFiles)
+			nodes.POST("/:id/log-files", logHandler.AddNodeLogFile)
+			nodes.DELETE("/:id/log-files/:fileId", logHandler.DeleteNodeLogFile)
+			nodes.GET("/:id/logs/tail", logHandler.TailNodeLog)
+			nodes.GET("/:id/logs/ws", logHandler.FollowNodeLogWSHandler)
 		}
 		alerts := dashbaord.Group("/alerts")
 		{
+			alerts.GET("/history", alertHandler.GetAlertHistory)
 			alerts.GET("/:id", alertHandler.GetAlert)
+			alerts.GET("/:id/state", alertHandler.GetAlertState)
+			alerts.GET("/:id/channels", alertHandler.GetAlertChannels)
 			alerts.POST("", alertHandler.CreateAlert)
 			alerts.GET("", alertHandler.GetAlerts)
 			alerts.PUT("/activate", alertHandler.ActivateAlert)
@@ -103,6 +143,66 @@ func Run(ctx context.Context, repo *db.Repo, port string) {
 			projects.GET("/:id", projectHandler.GetProject)
 			projects.PUT("/:id", projectHandler.UpdateProject)
 			projects.DELETE("/:id", projectHandler.DeleteProject)
+			projects.POST("/:id/webhook-secret", projectHandler.RotateWebhookSecret)
+			projects.POST("/:id/deploy", projectHandler.DeployProject)
+			projects.POST("/:id/rollback", projectHandler.RollbackProject)
+			projects.GET("/:id/deployments", projectHandler.ListDeployments)
+			projects.POST("/:id/commands/:name", commandHandler.RunProjectCommand)
+			projects.GET("/:id/command-runs", commandHandler.GetProjectCommandRuns)
+			projects.POST("/:id/backups", backupHandler.CreateBackup)
+			projects.GET("/:id/backups", backupHandler.ListBackups)
+			projects.PUT("/:id/backup-schedule", backupHandler.SetSchedule)
+			projects.GET("/:id/logs", logHandler.ListProjectLogFiles)
+			projects.GET("/:id/logs/tail", logHandler.TailProjectLog)
+			projects.GET("/:id/logs/ws", logHandler.FollowProjectLogWSHandler)
+		}
+		backups := dashbaord.Group("/backups")
+		{
+			backups.GET("/:id", backupHandler.GetBackup)
+			backups.GET("/:id/download", backupHandler.DownloadBackup)
+			backups.DELETE("/:id", backupHandler.DeleteBackup)
+			backups.POST("/:id/restore", backupHandler.RestoreBackup)
+			backups.GET("/:id/restores", backupHandler.ListRestores)
+		}
+		cron := dashbaord.Group("/cron")
+		{
+			cron.POST("", cronHandler.CreateCronJob)
+			cron.GET("", cronHandler.ListCronJobs)
+			cron.GET("/:id", cronHandler.GetCronJob)
+			cron.PUT("/:id", cronHandler.UpdateCronJob)
+			cron.DELETE("/:id", cronHandler.DeleteCronJob)
+			cron.GET("/:id/runs", cronHandler.ListCronRuns)
+		}
+		channels := dashbaord.Group("/notification-channels")
+		{
+			channels.GET("/types", notificationChannelHandler.ListChannelTypes)
+			channels.POST("", notificationChannelHandler.CreateChannel)
+			channels.GET("", notificationChannelHandler.ListChannels)
+			channels.GET("/:id", notificationChannelHandler.GetChannel)
+			channels.PUT("/:id", notificationChannelHandler.UpdateChannel)
+			channels.DELETE("/:id", notificationChannelHandler.DeleteChannel)
+			channels.POST("/:id/test", notificationChannelHandler.TestChannel)
+			channels.GET("/:id/deliveries", notificationChannelHandler.ListDeliveries)
+		}
+		deployments := dashbaord.Group("/deployments")
+		{
+			deployments.GET("/:id", projectHandler.GetDeployment)
+		}
+		agentTokens := dashbaord.Group("/agent-tokens")
+		{
+			agentTokens.GET("", agentTokenHandler.GetTokens)
+			agentTokens.POST("", agentTokenHandler.CreateToken)
+			agentTokens.PUT("/:id/revoke", agentTokenHandler.RevokeToken)
+		}
+		executions := dashbaord.Group("/executions")
+		{
+			executions.GET("/:id", commandHandler.GetExecution)
+			executions.GET("/:id/ws", commandHandler.AttachExecutionWSHandler)
+		}
+		certificates := dashbaord.Group("/certificates")
+		{
+			certificates.GET("/ca", certificateHandler.GetCACertificate)
+			certificates.PUT("/:id/revoke", certificateHandler.RevokeCertificate)
 		}
 	}
 
@@ -5,9 +5,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
 	"github.com/sanda0/vps_pilot/internal/utils"
 )
 
@@ -63,6 +66,37 @@ func CreateSuperuser(ctx context.Context, repo *db.Repo) {
 
 }
 
+func CreateAgentToken(ctx context.Context, repo *db.Repo) {
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Enter token name: ")
+	name, _ := reader.ReadString('\n')
+
+	fmt.Print("Enter node id (leave empty to enroll a new node): ")
+	nodeIdStr, _ := reader.ReadString('\n')
+
+	fmt.Print("Expires in hours (leave empty for no expiry): ")
+	expiresInStr, _ := reader.ReadString('\n')
+
+	nodeId, _ := strconv.Atoi(strings.TrimSpace(nodeIdStr))
+	expiresIn, _ := strconv.Atoi(strings.TrimSpace(expiresInStr))
+
+	token, err := services.NewAgentTokenService(ctx, repo).CreateToken(dto.AgentTokenCreateDto{
+		Name:      strings.TrimSpace(name),
+		NodeID:    int32(nodeId),
+		ExpiresIn: int32(expiresIn),
+	})
+	if err != nil {
+		fmt.Println("Error creating agent token:", err)
+		return
+	}
+
+	fmt.Println("Agent token created with id: ", token.ID)
+	fmt.Println("Token (shown only once): ", token.Token)
+
+}
+
 func CreateMakeFile() error {
 
 	// Get the database path from the environment variable or use default
@@ -106,6 +140,10 @@ dev:
 create-superuser:
 	go run main.go -create-superuser
 
+# Create agent enrollment token
+create-agent-token:
+	go run main.go -create-agent-token
+
 # Run tests
 test:
 	go test ./...
@@ -146,7 +184,7 @@ db-reset:
 		echo "Cancelled."; \
 	fi
 
-.PHONY: migrate sqlc build build-full run dev create-superuser test test-coverage clean db-info backup db-reset
+.PHONY: migrate sqlc build build-full run dev create-superuser create-agent-token test test-coverage clean db-info backup db-reset
 
 		`
 
@@ -29,10 +29,10 @@ INSERT INTO alerts(
     threshold,
     net_rece_threshold,
     net_send_threshold,
-    email,
-    discord_webhook,
-    slack_webhook,
-    is_active
+    is_active,
+    mountpoint,
+    aggregation,
+    resend_interval
   )
 values (
     ?,
@@ -46,7 +46,7 @@ values (
     ?,
     ?
   )
-RETURNING id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, email, discord_webhook, slack_webhook, is_active, created_at, updated_at
+RETURNING id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, is_active, created_at, updated_at, mountpoint, aggregation, resend_interval
 `
 
 type CreateAlertParams struct {
@@ -56,10 +56,10 @@ type CreateAlertParams struct {
 	Threshold        sql.NullFloat64 `json:"threshold"`
 	NetReceThreshold sql.NullFloat64 `json:"net_rece_threshold"`
 	NetSendThreshold sql.NullFloat64 `json:"net_send_threshold"`
-	Email            sql.NullString  `json:"email"`
-	DiscordWebhook   sql.NullString  `json:"discord_webhook"`
-	SlackWebhook     sql.NullString  `json:"slack_webhook"`
 	IsActive         sql.NullInt64   `json:"is_active"`
+	Mountpoint       sql.NullString  `json:"mountpoint"`
+	Aggregation      string          `json:"aggregation"`
+	ResendInterval   int64           `json:"resend_interval"`
 }
 
 func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert, error) {
@@ -70,10 +70,10 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert
 		arg.Threshold,
 		arg.NetReceThreshold,
 		arg.NetSendThreshold,
-		arg.Email,
-		arg.DiscordWebhook,
-		arg.SlackWebhook,
 		arg.IsActive,
+		arg.Mountpoint,
+		arg.Aggregation,
+		arg.ResendInterval,
 	)
 	var i Alert
 	err := row.Scan(
@@ -84,12 +84,12 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert
 		&i.Threshold,
 		&i.NetReceThreshold,
 		&i.NetSendThreshold,
-		&i.Email,
-		&i.DiscordWebhook,
-		&i.SlackWebhook,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Mountpoint,
+		&i.Aggregation,
+		&i.ResendInterval,
 	)
 	return i, err
 }
@@ -115,8 +115,18 @@ func (q *Queries) DeleteAlert(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteAlertState = `-- name: DeleteAlertState :exec
+DELETE FROM alert_states
+WHERE alert_id = ?
+`
+
+func (q *Queries) DeleteAlertState(ctx context.Context, alertID int64) error {
+	_, err := q.exec(ctx, q.deleteAlertStateStmt, deleteAlertState, alertID)
+	return err
+}
+
 const getActiveAlertsByNodeAndMetric = `-- name: GetActiveAlertsByNodeAndMetric :many
-SELECT a.id, a.node_id, a.metric, a.duration, a.threshold, a.net_rece_threshold, a.net_send_threshold, a.email, a.discord_webhook, a.slack_webhook, a.is_active, a.created_at, a.updated_at,n.name as node_name,n.ip as node_ip FROM alerts a
+SELECT a.id, a.node_id, a.metric, a.duration, a.threshold, a.net_rece_threshold, a.net_send_threshold, a.is_active, a.created_at, a.updated_at, a.mountpoint, a.aggregation, a.resend_interval,n.name as node_name,n.ip as node_ip FROM alerts a
 join nodes n on a.node_id = n.id
 WHERE node_id = ? AND metric = ? AND is_active = 1
 `
@@ -134,12 +144,12 @@ type GetActiveAlertsByNodeAndMetricRow struct {
 	Threshold        sql.NullFloat64 `json:"threshold"`
 	NetReceThreshold sql.NullFloat64 `json:"net_rece_threshold"`
 	NetSendThreshold sql.NullFloat64 `json:"net_send_threshold"`
-	Email            sql.NullString  `json:"email"`
-	DiscordWebhook   sql.NullString  `json:"discord_webhook"`
-	SlackWebhook     sql.NullString  `json:"slack_webhook"`
 	IsActive         sql.NullInt64   `json:"is_active"`
 	CreatedAt        int64           `json:"created_at"`
 	UpdatedAt        int64           `json:"updated_at"`
+	Mountpoint       sql.NullString  `json:"mountpoint"`
+	Aggregation      string          `json:"aggregation"`
+	ResendInterval   int64           `json:"resend_interval"`
 	NodeName         sql.NullString  `json:"node_name"`
 	NodeIp           string          `json:"node_ip"`
 }
@@ -161,12 +171,12 @@ func (q *Queries) GetActiveAlertsByNodeAndMetric(ctx context.Context, arg GetAct
 			&i.Threshold,
 			&i.NetReceThreshold,
 			&i.NetSendThreshold,
-			&i.Email,
-			&i.DiscordWebhook,
-			&i.SlackWebhook,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Mountpoint,
+			&i.Aggregation,
+			&i.ResendInterval,
 			&i.NodeName,
 			&i.NodeIp,
 		); err != nil {
@@ -184,7 +194,7 @@ func (q *Queries) GetActiveAlertsByNodeAndMetric(ctx context.Context, arg GetAct
 }
 
 const getAlert = `-- name: GetAlert :one
-SELECT id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, email, discord_webhook, slack_webhook, is_active, created_at, updated_at FROM alerts
+SELECT id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, is_active, created_at, updated_at, mountpoint, aggregation, resend_interval FROM alerts
 WHERE id = ?
 `
 
@@ -199,18 +209,39 @@ func (q *Queries) GetAlert(ctx context.Context, id int64) (Alert, error) {
 		&i.Threshold,
 		&i.NetReceThreshold,
 		&i.NetSendThreshold,
-		&i.Email,
-		&i.DiscordWebhook,
-		&i.SlackWebhook,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Mountpoint,
+		&i.Aggregation,
+		&i.ResendInterval,
+	)
+	return i, err
+}
+
+const getAlertState = `-- name: GetAlertState :one
+SELECT alert_id, state, value, breached_at, fired_at, resolved_at, updated_at, notified_at FROM alert_states
+WHERE alert_id = ?
+`
+
+func (q *Queries) GetAlertState(ctx context.Context, alertID int64) (AlertState, error) {
+	row := q.queryRow(ctx, q.getAlertStateStmt, getAlertState, alertID)
+	var i AlertState
+	err := row.Scan(
+		&i.AlertID,
+		&i.State,
+		&i.Value,
+		&i.BreachedAt,
+		&i.FiredAt,
+		&i.ResolvedAt,
+		&i.UpdatedAt,
+		&i.NotifiedAt,
 	)
 	return i, err
 }
 
 const getAlerts = `-- name: GetAlerts :many
-SELECT id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, email, discord_webhook, slack_webhook, is_active, created_at, updated_at FROM alerts
+SELECT id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, is_active, created_at, updated_at, mountpoint, aggregation, resend_interval FROM alerts
 WHERE node_id = ?
 ORDER BY id DESC
 LIMIT ? OFFSET ?
@@ -239,12 +270,12 @@ func (q *Queries) GetAlerts(ctx context.Context, arg GetAlertsParams) ([]Alert,
 			&i.Threshold,
 			&i.NetReceThreshold,
 			&i.NetSendThreshold,
-			&i.Email,
-			&i.DiscordWebhook,
-			&i.SlackWebhook,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Mountpoint,
+			&i.Aggregation,
+			&i.ResendInterval,
 		); err != nil {
 			return nil, err
 		}
@@ -259,6 +290,42 @@ func (q *Queries) GetAlerts(ctx context.Context, arg GetAlertsParams) ([]Alert,
 	return items, nil
 }
 
+const saveAlertState = `-- name: SaveAlertState :exec
+INSERT INTO alert_states (alert_id, state, value, breached_at, fired_at, resolved_at, notified_at)
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (alert_id) DO
+UPDATE
+SET state = excluded.state,
+  value = excluded.value,
+  breached_at = excluded.breached_at,
+  fired_at = excluded.fired_at,
+  resolved_at = excluded.resolved_at,
+  notified_at = excluded.notified_at,
+  updated_at = strftime('%s', 'now')
+`
+
+type SaveAlertStateParams struct {
+	AlertID    int64          `json:"alert_id"`
+	State      string         `json:"state"`
+	Value      sql.NullString `json:"value"`
+	BreachedAt sql.NullInt64  `json:"breached_at"`
+	FiredAt    sql.NullInt64  `json:"fired_at"`
+	ResolvedAt sql.NullInt64  `json:"resolved_at"`
+	NotifiedAt sql.NullInt64  `json:"notified_at"`
+}
+
+func (q *Queries) SaveAlertState(ctx context.Context, arg SaveAlertStateParams) error {
+	_, err := q.exec(ctx, q.saveAlertStateStmt, saveAlertState,
+		arg.AlertID,
+		arg.State,
+		arg.Value,
+		arg.BreachedAt,
+		arg.FiredAt,
+		arg.ResolvedAt,
+		arg.NotifiedAt,
+	)
+	return err
+}
+
 const updateAlert = `-- name: UpdateAlert :one
 UPDATE alerts
 SET node_id = ?,
@@ -267,12 +334,12 @@ SET node_id = ?,
   threshold = ?,
   net_rece_threshold = ?,
   net_send_threshold = ?,
-  email = ?,
-  discord_webhook = ?,
-  slack_webhook = ?,
-  is_active = ?
+  is_active = ?,
+  mountpoint = ?,
+  aggregation = ?,
+  resend_interval = ?
 WHERE id = ?
-RETURNING id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, email, discord_webhook, slack_webhook, is_active, created_at, updated_at
+RETURNING id, node_id, metric, duration, threshold, net_rece_threshold, net_send_threshold, is_active, created_at, updated_at, mountpoint, aggregation, resend_interval
 `
 
 type UpdateAlertParams struct {
@@ -282,10 +349,10 @@ type UpdateAlertParams struct {
 	Threshold        sql.NullFloat64 `json:"threshold"`
 	NetReceThreshold sql.NullFloat64 `json:"net_rece_threshold"`
 	NetSendThreshold sql.NullFloat64 `json:"net_send_threshold"`
-	Email            sql.NullString  `json:"email"`
-	DiscordWebhook   sql.NullString  `json:"discord_webhook"`
-	SlackWebhook     sql.NullString  `json:"slack_webhook"`
 	IsActive         sql.NullInt64   `json:"is_active"`
+	Mountpoint       sql.NullString  `json:"mountpoint"`
+	Aggregation      string          `json:"aggregation"`
+	ResendInterval   int64           `json:"resend_interval"`
 	ID               int64           `json:"id"`
 }
 
@@ -297,10 +364,10 @@ func (q *Queries) UpdateAlert(ctx context.Context, arg UpdateAlertParams) (Alert
 		arg.Threshold,
 		arg.NetReceThreshold,
 		arg.NetSendThreshold,
-		arg.Email,
-		arg.DiscordWebhook,
-		arg.SlackWebhook,
 		arg.IsActive,
+		arg.Mountpoint,
+		arg.Aggregation,
+		arg.ResendInterval,
 		arg.ID,
 	)
 	var i Alert
@@ -312,12 +379,12 @@ func (q *Queries) UpdateAlert(ctx context.Context, arg UpdateAlertParams) (Alert
 		&i.Threshold,
 		&i.NetReceThreshold,
 		&i.NetSendThreshold,
-		&i.Email,
-		&i.DiscordWebhook,
-		&i.SlackWebhook,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Mountpoint,
+		&i.Aggregation,
+		&i.ResendInterval,
 	)
 	return i, err
 }
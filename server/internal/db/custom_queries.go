@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -65,6 +66,89 @@ func (q *Queries) GetCPUStats(ctx context.Context, arg GetCPUStatsParams) ([]map
 	return result, nil
 }
 
+// ListAlertEventsParams filters the alert history, zero values match everything
+type ListAlertEventsParams struct {
+	NodeID  int64  `json:"node_id"`
+	AlertID int64  `json:"alert_id"`
+	Metric  string `json:"metric"`
+	State   string `json:"state"` // the state the rule moved to
+	From    int64  `json:"from"`
+	To      int64  `json:"to"`
+	Limit   int64  `json:"limit"`
+	Offset  int64  `json:"offset"`
+}
+
+func (q *Queries) ListAlertEvents(ctx context.Context, arg ListAlertEventsParams) ([]AlertEvent, error) {
+	// Build the WHERE clause from the filters that are set
+	var conditions []string
+	var args []interface{}
+	if arg.NodeID != 0 {
+		conditions = append(conditions, "node_id = ?")
+		args = append(args, arg.NodeID)
+	}
+	if arg.AlertID != 0 {
+		conditions = append(conditions, "alert_id = ?")
+		args = append(args, arg.AlertID)
+	}
+	if arg.Metric != "" {
+		conditions = append(conditions, "metric = ?")
+		args = append(args, arg.Metric)
+	}
+	if arg.State != "" {
+		conditions = append(conditions, "to_state = ?")
+		args = append(args, arg.State)
+	}
+	if arg.From != 0 {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, arg.From)
+	}
+	if arg.To != 0 {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, arg.To)
+	}
+
+	query := `
+		SELECT id, alert_id, node_id, metric, value, threshold, from_state, to_state, breached_at, deliveries, created_at
+		FROM alert_events
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ? OFFSET ?"
+	args = append(args, arg.Limit, arg.Offset)
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []AlertEvent
+	for rows.Next() {
+		var i AlertEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlertID,
+			&i.NodeID,
+			&i.Metric,
+			&i.Value,
+			&i.Threshold,
+			&i.FromState,
+			&i.ToState,
+			&i.BreachedAt,
+			&i.Deliveries,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 // Helper function to parse time range string to int
 func mustParseInt(s string) int {
 	var val int
@@ -27,60 +27,213 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.activateAlertStmt, err = db.PrepareContext(ctx, activateAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query ActivateAlert: %w", err)
 	}
+	if q.addAlertChannelStmt, err = db.PrepareContext(ctx, addAlertChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAlertChannel: %w", err)
+	}
+	if q.addCronJobChannelStmt, err = db.PrepareContext(ctx, addCronJobChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCronJobChannel: %w", err)
+	}
 	if q.addNodeDiskInfoStmt, err = db.PrepareContext(ctx, addNodeDiskInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query AddNodeDiskInfo: %w", err)
 	}
 	if q.addNodeSysInfoStmt, err = db.PrepareContext(ctx, addNodeSysInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query AddNodeSysInfo: %w", err)
 	}
+	if q.addNodeSysInfoHistoryStmt, err = db.PrepareContext(ctx, addNodeSysInfoHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query AddNodeSysInfoHistory: %w", err)
+	}
+	if q.bindAgentTokenStmt, err = db.PrepareContext(ctx, bindAgentToken); err != nil {
+		return nil, fmt.Errorf("error preparing query BindAgentToken: %w", err)
+	}
+	if q.clearAlertChannelsStmt, err = db.PrepareContext(ctx, clearAlertChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearAlertChannels: %w", err)
+	}
+	if q.clearCronJobChannelsStmt, err = db.PrepareContext(ctx, clearCronJobChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearCronJobChannels: %w", err)
+	}
+	if q.countActiveDeploymentsByProjectStmt, err = db.PrepareContext(ctx, countActiveDeploymentsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query CountActiveDeploymentsByProject: %w", err)
+	}
 	if q.countProjectsStmt, err = db.PrepareContext(ctx, countProjects); err != nil {
 		return nil, fmt.Errorf("error preparing query CountProjects: %w", err)
 	}
 	if q.countProjectsByNodeStmt, err = db.PrepareContext(ctx, countProjectsByNode); err != nil {
 		return nil, fmt.Errorf("error preparing query CountProjectsByNode: %w", err)
 	}
+	if q.createAgentCertificateStmt, err = db.PrepareContext(ctx, createAgentCertificate); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAgentCertificate: %w", err)
+	}
+	if q.createAgentTokenStmt, err = db.PrepareContext(ctx, createAgentToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAgentToken: %w", err)
+	}
 	if q.createAlertStmt, err = db.PrepareContext(ctx, createAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAlert: %w", err)
 	}
+	if q.createAlertEventStmt, err = db.PrepareContext(ctx, createAlertEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAlertEvent: %w", err)
+	}
+	if q.createBackupStmt, err = db.PrepareContext(ctx, createBackup); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBackup: %w", err)
+	}
+	if q.createBackupRestoreStmt, err = db.PrepareContext(ctx, createBackupRestore); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBackupRestore: %w", err)
+	}
+	if q.createCertificateAuthorityStmt, err = db.PrepareContext(ctx, createCertificateAuthority); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCertificateAuthority: %w", err)
+	}
+	if q.createCommandExecutionStmt, err = db.PrepareContext(ctx, createCommandExecution); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCommandExecution: %w", err)
+	}
+	if q.createCronJobStmt, err = db.PrepareContext(ctx, createCronJob); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCronJob: %w", err)
+	}
+	if q.createCronRunStmt, err = db.PrepareContext(ctx, createCronRun); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCronRun: %w", err)
+	}
+	if q.createDeploymentStmt, err = db.PrepareContext(ctx, createDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeployment: %w", err)
+	}
+	if q.createDiscoveredProjectStmt, err = db.PrepareContext(ctx, createDiscoveredProject); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDiscoveredProject: %w", err)
+	}
+	if q.createGitHubWebhookEventStmt, err = db.PrepareContext(ctx, createGitHubWebhookEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateGitHubWebhookEvent: %w", err)
+	}
 	if q.createNodeStmt, err = db.PrepareContext(ctx, createNode); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateNode: %w", err)
 	}
+	if q.createNodeLogFileStmt, err = db.PrepareContext(ctx, createNodeLogFile); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateNodeLogFile: %w", err)
+	}
+	if q.createNotificationChannelStmt, err = db.PrepareContext(ctx, createNotificationChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateNotificationChannel: %w", err)
+	}
 	if q.createProjectStmt, err = db.PrepareContext(ctx, createProject); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateProject: %w", err)
 	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createWebhookDeliveryStmt, err = db.PrepareContext(ctx, createWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookDelivery: %w", err)
+	}
 	if q.deactivateAlertStmt, err = db.PrepareContext(ctx, deactivateAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query DeactivateAlert: %w", err)
 	}
 	if q.deleteAlertStmt, err = db.PrepareContext(ctx, deleteAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAlert: %w", err)
 	}
+	if q.deleteAlertStateStmt, err = db.PrepareContext(ctx, deleteAlertState); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAlertState: %w", err)
+	}
+	if q.deleteBackupStmt, err = db.PrepareContext(ctx, deleteBackup); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBackup: %w", err)
+	}
+	if q.deleteCronJobStmt, err = db.PrepareContext(ctx, deleteCronJob); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCronJob: %w", err)
+	}
 	if q.deleteNodeStmt, err = db.PrepareContext(ctx, deleteNode); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteNode: %w", err)
 	}
+	if q.deleteNodeLogFileStmt, err = db.PrepareContext(ctx, deleteNodeLogFile); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteNodeLogFile: %w", err)
+	}
+	if q.deleteNotificationChannelStmt, err = db.PrepareContext(ctx, deleteNotificationChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteNotificationChannel: %w", err)
+	}
 	if q.deleteProjectStmt, err = db.PrepareContext(ctx, deleteProject); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteProject: %w", err)
 	}
+	if q.deleteStaleNodeDiskInfoStmt, err = db.PrepareContext(ctx, deleteStaleNodeDiskInfo); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteStaleNodeDiskInfo: %w", err)
+	}
+	if q.failCloningProjectsStmt, err = db.PrepareContext(ctx, failCloningProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query FailCloningProjects: %w", err)
+	}
+	if q.failRunningCommandExecutionsStmt, err = db.PrepareContext(ctx, failRunningCommandExecutions); err != nil {
+		return nil, fmt.Errorf("error preparing query FailRunningCommandExecutions: %w", err)
+	}
+	if q.failUnfinishedBackupRestoresStmt, err = db.PrepareContext(ctx, failUnfinishedBackupRestores); err != nil {
+		return nil, fmt.Errorf("error preparing query FailUnfinishedBackupRestores: %w", err)
+	}
+	if q.failUnfinishedBackupsStmt, err = db.PrepareContext(ctx, failUnfinishedBackups); err != nil {
+		return nil, fmt.Errorf("error preparing query FailUnfinishedBackups: %w", err)
+	}
+	if q.failUnfinishedDeploymentsStmt, err = db.PrepareContext(ctx, failUnfinishedDeployments); err != nil {
+		return nil, fmt.Errorf("error preparing query FailUnfinishedDeployments: %w", err)
+	}
 	if q.findUserByEmailStmt, err = db.PrepareContext(ctx, findUserByEmail); err != nil {
 		return nil, fmt.Errorf("error preparing query FindUserByEmail: %w", err)
 	}
 	if q.findUserByIdStmt, err = db.PrepareContext(ctx, findUserById); err != nil {
 		return nil, fmt.Errorf("error preparing query FindUserById: %w", err)
 	}
+	if q.finishBackupStmt, err = db.PrepareContext(ctx, finishBackup); err != nil {
+		return nil, fmt.Errorf("error preparing query FinishBackup: %w", err)
+	}
+	if q.finishBackupRestoreStmt, err = db.PrepareContext(ctx, finishBackupRestore); err != nil {
+		return nil, fmt.Errorf("error preparing query FinishBackupRestore: %w", err)
+	}
+	if q.finishCommandExecutionStmt, err = db.PrepareContext(ctx, finishCommandExecution); err != nil {
+		return nil, fmt.Errorf("error preparing query FinishCommandExecution: %w", err)
+	}
+	if q.finishDeploymentStmt, err = db.PrepareContext(ctx, finishDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query FinishDeployment: %w", err)
+	}
 	if q.getActiveAlertsByNodeAndMetricStmt, err = db.PrepareContext(ctx, getActiveAlertsByNodeAndMetric); err != nil {
 		return nil, fmt.Errorf("error preparing query GetActiveAlertsByNodeAndMetric: %w", err)
 	}
+	if q.getAgentCertificateBySerialStmt, err = db.PrepareContext(ctx, getAgentCertificateBySerial); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAgentCertificateBySerial: %w", err)
+	}
+	if q.getAgentTokenStmt, err = db.PrepareContext(ctx, getAgentToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAgentToken: %w", err)
+	}
+	if q.getAgentTokenByHashStmt, err = db.PrepareContext(ctx, getAgentTokenByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAgentTokenByHash: %w", err)
+	}
 	if q.getAlertStmt, err = db.PrepareContext(ctx, getAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAlert: %w", err)
 	}
+	if q.getAlertStateStmt, err = db.PrepareContext(ctx, getAlertState); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAlertState: %w", err)
+	}
 	if q.getAlertsStmt, err = db.PrepareContext(ctx, getAlerts); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAlerts: %w", err)
 	}
+	if q.getBackupStmt, err = db.PrepareContext(ctx, getBackup); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBackup: %w", err)
+	}
+	if q.getCertificateAuthorityStmt, err = db.PrepareContext(ctx, getCertificateAuthority); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCertificateAuthority: %w", err)
+	}
+	if q.getCommandExecutionStmt, err = db.PrepareContext(ctx, getCommandExecution); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCommandExecution: %w", err)
+	}
+	if q.getCronJobStmt, err = db.PrepareContext(ctx, getCronJob); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCronJob: %w", err)
+	}
+	if q.getDeploymentStmt, err = db.PrepareContext(ctx, getDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDeployment: %w", err)
+	}
+	if q.getDiskStatsStmt, err = db.PrepareContext(ctx, getDiskStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDiskStats: %w", err)
+	}
 	if q.getGitHubTokenStmt, err = db.PrepareContext(ctx, getGitHubToken); err != nil {
 		return nil, fmt.Errorf("error preparing query GetGitHubToken: %w", err)
 	}
+	if q.getLatestBackupStmt, err = db.PrepareContext(ctx, getLatestBackup); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestBackup: %w", err)
+	}
+	if q.getMountDiskStatWindowStmt, err = db.PrepareContext(ctx, getMountDiskStatWindow); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMountDiskStatWindow: %w", err)
+	}
+	if q.getMountDiskStatsStmt, err = db.PrepareContext(ctx, getMountDiskStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMountDiskStats: %w", err)
+	}
+	if q.getNetStatWindowStmt, err = db.PrepareContext(ctx, getNetStatWindow); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNetStatWindow: %w", err)
+	}
 	if q.getNetStatsStmt, err = db.PrepareContext(ctx, getNetStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNetStats: %w", err)
 	}
@@ -90,12 +243,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getNodeByIPStmt, err = db.PrepareContext(ctx, getNodeByIP); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNodeByIP: %w", err)
 	}
+	if q.getNodeByMachineIDStmt, err = db.PrepareContext(ctx, getNodeByMachineID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNodeByMachineID: %w", err)
+	}
 	if q.getNodeDiskInfoByNodeIDStmt, err = db.PrepareContext(ctx, getNodeDiskInfoByNodeID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNodeDiskInfoByNodeID: %w", err)
 	}
+	if q.getNodeIPHistoryStmt, err = db.PrepareContext(ctx, getNodeIPHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNodeIPHistory: %w", err)
+	}
 	if q.getNodeSysInfoByNodeIDStmt, err = db.PrepareContext(ctx, getNodeSysInfoByNodeID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNodeSysInfoByNodeID: %w", err)
 	}
+	if q.getNodeSysInfoHistoryStmt, err = db.PrepareContext(ctx, getNodeSysInfoHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNodeSysInfoHistory: %w", err)
+	}
 	if q.getNodeWithSysInfoStmt, err = db.PrepareContext(ctx, getNodeWithSysInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNodeWithSysInfo: %w", err)
 	}
@@ -105,54 +267,207 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getNodesWithSysInfoStmt, err = db.PrepareContext(ctx, getNodesWithSysInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNodesWithSysInfo: %w", err)
 	}
+	if q.getNotificationChannelStmt, err = db.PrepareContext(ctx, getNotificationChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationChannel: %w", err)
+	}
 	if q.getProjectStmt, err = db.PrepareContext(ctx, getProject); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProject: %w", err)
 	}
 	if q.getProjectWithNodeStmt, err = db.PrepareContext(ctx, getProjectWithNode); err != nil {
 		return nil, fmt.Errorf("error preparing query GetProjectWithNode: %w", err)
 	}
+	if q.getSystemStatWindowStmt, err = db.PrepareContext(ctx, getSystemStatWindow); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSystemStatWindow: %w", err)
+	}
 	if q.getSystemStatsStmt, err = db.PrepareContext(ctx, getSystemStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSystemStats: %w", err)
 	}
+	if q.insertDiskStatStmt, err = db.PrepareContext(ctx, insertDiskStat); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertDiskStat: %w", err)
+	}
 	if q.insertNetStatsStmt, err = db.PrepareContext(ctx, insertNetStats); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertNetStats: %w", err)
 	}
 	if q.insertSystemStatsStmt, err = db.PrepareContext(ctx, insertSystemStats); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertSystemStats: %w", err)
 	}
+	if q.listAgentCertificatesByNodeStmt, err = db.PrepareContext(ctx, listAgentCertificatesByNode); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAgentCertificatesByNode: %w", err)
+	}
+	if q.listAgentTokensStmt, err = db.PrepareContext(ctx, listAgentTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAgentTokens: %w", err)
+	}
+	if q.listAlertChannelsStmt, err = db.PrepareContext(ctx, listAlertChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAlertChannels: %w", err)
+	}
+	if q.listBackupRestoresStmt, err = db.PrepareContext(ctx, listBackupRestores); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBackupRestores: %w", err)
+	}
+	if q.listBackupsByProjectStmt, err = db.PrepareContext(ctx, listBackupsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBackupsByProject: %w", err)
+	}
+	if q.listCommandExecutionsByNodeStmt, err = db.PrepareContext(ctx, listCommandExecutionsByNode); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCommandExecutionsByNode: %w", err)
+	}
+	if q.listCommandExecutionsByProjectStmt, err = db.PrepareContext(ctx, listCommandExecutionsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCommandExecutionsByProject: %w", err)
+	}
+	if q.listCronJobChannelsStmt, err = db.PrepareContext(ctx, listCronJobChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCronJobChannels: %w", err)
+	}
+	if q.listCronJobsStmt, err = db.PrepareContext(ctx, listCronJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCronJobs: %w", err)
+	}
+	if q.listCronJobsByNodeStmt, err = db.PrepareContext(ctx, listCronJobsByNode); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCronJobsByNode: %w", err)
+	}
+	if q.listCronRunsStmt, err = db.PrepareContext(ctx, listCronRuns); err != nil {
+		return nil, fmt.Errorf("error preparing query ListCronRuns: %w", err)
+	}
+	if q.listDeploymentsByProjectStmt, err = db.PrepareContext(ctx, listDeploymentsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDeploymentsByProject: %w", err)
+	}
+	if q.listEnabledCronJobsStmt, err = db.PrepareContext(ctx, listEnabledCronJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEnabledCronJobs: %w", err)
+	}
+	if q.listEnabledCronJobsByNodeStmt, err = db.PrepareContext(ctx, listEnabledCronJobsByNode); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEnabledCronJobsByNode: %w", err)
+	}
+	if q.listGitHubWebhookEventsStmt, err = db.PrepareContext(ctx, listGitHubWebhookEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGitHubWebhookEvents: %w", err)
+	}
+	if q.listGitHubWebhookEventsByProjectStmt, err = db.PrepareContext(ctx, listGitHubWebhookEventsByProject); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGitHubWebhookEventsByProject: %w", err)
+	}
+	if q.listNodeLogFilesStmt, err = db.PrepareContext(ctx, listNodeLogFiles); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNodeLogFiles: %w", err)
+	}
+	if q.listNodeProjectsStmt, err = db.PrepareContext(ctx, listNodeProjects); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNodeProjects: %w", err)
+	}
+	if q.listNodesStmt, err = db.PrepareContext(ctx, listNodes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNodes: %w", err)
+	}
+	if q.listNotificationChannelsStmt, err = db.PrepareContext(ctx, listNotificationChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNotificationChannels: %w", err)
+	}
 	if q.listProjectsStmt, err = db.PrepareContext(ctx, listProjects); err != nil {
 		return nil, fmt.Errorf("error preparing query ListProjects: %w", err)
 	}
 	if q.listProjectsByNodeStmt, err = db.PrepareContext(ctx, listProjectsByNode); err != nil {
 		return nil, fmt.Errorf("error preparing query ListProjectsByNode: %w", err)
 	}
+	if q.listProjectsWithBackupScheduleStmt, err = db.PrepareContext(ctx, listProjectsWithBackupSchedule); err != nil {
+		return nil, fmt.Errorf("error preparing query ListProjectsWithBackupSchedule: %w", err)
+	}
 	if q.listProjectsWithNodesStmt, err = db.PrepareContext(ctx, listProjectsWithNodes); err != nil {
 		return nil, fmt.Errorf("error preparing query ListProjectsWithNodes: %w", err)
 	}
+	if q.listProjectsWithRepoStmt, err = db.PrepareContext(ctx, listProjectsWithRepo); err != nil {
+		return nil, fmt.Errorf("error preparing query ListProjectsWithRepo: %w", err)
+	}
+	if q.listReleasedDeploymentsStmt, err = db.PrepareContext(ctx, listReleasedDeployments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListReleasedDeployments: %w", err)
+	}
+	if q.listSucceededBackupsStmt, err = db.PrepareContext(ctx, listSucceededBackups); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSucceededBackups: %w", err)
+	}
+	if q.listSucceededDeploymentsStmt, err = db.PrepareContext(ctx, listSucceededDeployments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSucceededDeployments: %w", err)
+	}
+	if q.listWebhookDeliveriesStmt, err = db.PrepareContext(ctx, listWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookDeliveries: %w", err)
+	}
+	if q.markReleasePrunedStmt, err = db.PrepareContext(ctx, markReleasePruned); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkReleasePruned: %w", err)
+	}
+	if q.recordNodeIPStmt, err = db.PrepareContext(ctx, recordNodeIP); err != nil {
+		return nil, fmt.Errorf("error preparing query RecordNodeIP: %w", err)
+	}
 	if q.removeGitHubTokenStmt, err = db.PrepareContext(ctx, removeGitHubToken); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveGitHubToken: %w", err)
 	}
+	if q.revokeAgentCertificateStmt, err = db.PrepareContext(ctx, revokeAgentCertificate); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAgentCertificate: %w", err)
+	}
+	if q.revokeAgentTokenStmt, err = db.PrepareContext(ctx, revokeAgentToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAgentToken: %w", err)
+	}
+	if q.saveAlertStateStmt, err = db.PrepareContext(ctx, saveAlertState); err != nil {
+		return nil, fmt.Errorf("error preparing query SaveAlertState: %w", err)
+	}
 	if q.saveGitHubTokenStmt, err = db.PrepareContext(ctx, saveGitHubToken); err != nil {
 		return nil, fmt.Errorf("error preparing query SaveGitHubToken: %w", err)
 	}
+	if q.setAlertEventDeliveriesStmt, err = db.PrepareContext(ctx, setAlertEventDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAlertEventDeliveries: %w", err)
+	}
+	if q.setCommandExecutionCorrelationIDStmt, err = db.PrepareContext(ctx, setCommandExecutionCorrelationID); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCommandExecutionCorrelationID: %w", err)
+	}
+	if q.setCronJobLastRunStmt, err = db.PrepareContext(ctx, setCronJobLastRun); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCronJobLastRun: %w", err)
+	}
+	if q.setDeploymentReleaseStmt, err = db.PrepareContext(ctx, setDeploymentRelease); err != nil {
+		return nil, fmt.Errorf("error preparing query SetDeploymentRelease: %w", err)
+	}
+	if q.setNodeMachineIDStmt, err = db.PrepareContext(ctx, setNodeMachineID); err != nil {
+		return nil, fmt.Errorf("error preparing query SetNodeMachineID: %w", err)
+	}
+	if q.setNodeStatusStmt, err = db.PrepareContext(ctx, setNodeStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetNodeStatus: %w", err)
+	}
+	if q.setProjectBackupScheduleStmt, err = db.PrepareContext(ctx, setProjectBackupSchedule); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectBackupSchedule: %w", err)
+	}
+	if q.setProjectConfigMissingStmt, err = db.PrepareContext(ctx, setProjectConfigMissing); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectConfigMissing: %w", err)
+	}
+	if q.setProjectCurrentDeploymentStmt, err = db.PrepareContext(ctx, setProjectCurrentDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectCurrentDeployment: %w", err)
+	}
+	if q.setProjectWebhookSecretStmt, err = db.PrepareContext(ctx, setProjectWebhookSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query SetProjectWebhookSecret: %w", err)
+	}
+	if q.startDeploymentStmt, err = db.PrepareContext(ctx, startDeployment); err != nil {
+		return nil, fmt.Errorf("error preparing query StartDeployment: %w", err)
+	}
+	if q.touchNodeStmt, err = db.PrepareContext(ctx, touchNode); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchNode: %w", err)
+	}
 	if q.updateAlertStmt, err = db.PrepareContext(ctx, updateAlert); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAlert: %w", err)
 	}
+	if q.updateCronJobStmt, err = db.PrepareContext(ctx, updateCronJob); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateCronJob: %w", err)
+	}
+	if q.updateDeploymentLogsStmt, err = db.PrepareContext(ctx, updateDeploymentLogs); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateDeploymentLogs: %w", err)
+	}
 	if q.updateNodeStmt, err = db.PrepareContext(ctx, updateNode); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateNode: %w", err)
 	}
 	if q.updateNodeDiskInfoStmt, err = db.PrepareContext(ctx, updateNodeDiskInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateNodeDiskInfo: %w", err)
 	}
+	if q.updateNodeIPStmt, err = db.PrepareContext(ctx, updateNodeIP); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateNodeIP: %w", err)
+	}
 	if q.updateNodeNameStmt, err = db.PrepareContext(ctx, updateNodeName); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateNodeName: %w", err)
 	}
 	if q.updateNodeSysInfoStmt, err = db.PrepareContext(ctx, updateNodeSysInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateNodeSysInfo: %w", err)
 	}
+	if q.updateNotificationChannelStmt, err = db.PrepareContext(ctx, updateNotificationChannel); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateNotificationChannel: %w", err)
+	}
 	if q.updateProjectStmt, err = db.PrepareContext(ctx, updateProject); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateProject: %w", err)
 	}
+	if q.updateProjectConfigStmt, err = db.PrepareContext(ctx, updateProjectConfig); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateProjectConfig: %w", err)
+	}
 	if q.updateProjectLastDeployedStmt, err = db.PrepareContext(ctx, updateProjectLastDeployed); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateProjectLastDeployed: %w", err)
 	}
@@ -169,6 +484,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing activateAlertStmt: %w", cerr)
 		}
 	}
+	if q.addAlertChannelStmt != nil {
+		if cerr := q.addAlertChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAlertChannelStmt: %w", cerr)
+		}
+	}
+	if q.addCronJobChannelStmt != nil {
+		if cerr := q.addCronJobChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCronJobChannelStmt: %w", cerr)
+		}
+	}
 	if q.addNodeDiskInfoStmt != nil {
 		if cerr := q.addNodeDiskInfoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addNodeDiskInfoStmt: %w", cerr)
@@ -179,6 +504,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing addNodeSysInfoStmt: %w", cerr)
 		}
 	}
+	if q.addNodeSysInfoHistoryStmt != nil {
+		if cerr := q.addNodeSysInfoHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addNodeSysInfoHistoryStmt: %w", cerr)
+		}
+	}
+	if q.bindAgentTokenStmt != nil {
+		if cerr := q.bindAgentTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing bindAgentTokenStmt: %w", cerr)
+		}
+	}
+	if q.clearAlertChannelsStmt != nil {
+		if cerr := q.clearAlertChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearAlertChannelsStmt: %w", cerr)
+		}
+	}
+	if q.clearCronJobChannelsStmt != nil {
+		if cerr := q.clearCronJobChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearCronJobChannelsStmt: %w", cerr)
+		}
+	}
+	if q.countActiveDeploymentsByProjectStmt != nil {
+		if cerr := q.countActiveDeploymentsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countActiveDeploymentsByProjectStmt: %w", cerr)
+		}
+	}
 	if q.countProjectsStmt != nil {
 		if cerr := q.countProjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countProjectsStmt: %w", cerr)
@@ -189,16 +539,86 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countProjectsByNodeStmt: %w", cerr)
 		}
 	}
+	if q.createAgentCertificateStmt != nil {
+		if cerr := q.createAgentCertificateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAgentCertificateStmt: %w", cerr)
+		}
+	}
+	if q.createAgentTokenStmt != nil {
+		if cerr := q.createAgentTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAgentTokenStmt: %w", cerr)
+		}
+	}
 	if q.createAlertStmt != nil {
 		if cerr := q.createAlertStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAlertStmt: %w", cerr)
 		}
 	}
+	if q.createAlertEventStmt != nil {
+		if cerr := q.createAlertEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAlertEventStmt: %w", cerr)
+		}
+	}
+	if q.createBackupStmt != nil {
+		if cerr := q.createBackupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBackupStmt: %w", cerr)
+		}
+	}
+	if q.createBackupRestoreStmt != nil {
+		if cerr := q.createBackupRestoreStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBackupRestoreStmt: %w", cerr)
+		}
+	}
+	if q.createCertificateAuthorityStmt != nil {
+		if cerr := q.createCertificateAuthorityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCertificateAuthorityStmt: %w", cerr)
+		}
+	}
+	if q.createCommandExecutionStmt != nil {
+		if cerr := q.createCommandExecutionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCommandExecutionStmt: %w", cerr)
+		}
+	}
+	if q.createCronJobStmt != nil {
+		if cerr := q.createCronJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCronJobStmt: %w", cerr)
+		}
+	}
+	if q.createCronRunStmt != nil {
+		if cerr := q.createCronRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCronRunStmt: %w", cerr)
+		}
+	}
+	if q.createDeploymentStmt != nil {
+		if cerr := q.createDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.createDiscoveredProjectStmt != nil {
+		if cerr := q.createDiscoveredProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDiscoveredProjectStmt: %w", cerr)
+		}
+	}
+	if q.createGitHubWebhookEventStmt != nil {
+		if cerr := q.createGitHubWebhookEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createGitHubWebhookEventStmt: %w", cerr)
+		}
+	}
 	if q.createNodeStmt != nil {
 		if cerr := q.createNodeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createNodeStmt: %w", cerr)
 		}
 	}
+	if q.createNodeLogFileStmt != nil {
+		if cerr := q.createNodeLogFileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createNodeLogFileStmt: %w", cerr)
+		}
+	}
+	if q.createNotificationChannelStmt != nil {
+		if cerr := q.createNotificationChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createNotificationChannelStmt: %w", cerr)
+		}
+	}
 	if q.createProjectStmt != nil {
 		if cerr := q.createProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createProjectStmt: %w", cerr)
@@ -209,6 +629,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
+	if q.createWebhookDeliveryStmt != nil {
+		if cerr := q.createWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookDeliveryStmt: %w", cerr)
+		}
+	}
 	if q.deactivateAlertStmt != nil {
 		if cerr := q.deactivateAlertStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deactivateAlertStmt: %w", cerr)
@@ -219,16 +644,71 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteAlertStmt: %w", cerr)
 		}
 	}
+	if q.deleteAlertStateStmt != nil {
+		if cerr := q.deleteAlertStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAlertStateStmt: %w", cerr)
+		}
+	}
+	if q.deleteBackupStmt != nil {
+		if cerr := q.deleteBackupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBackupStmt: %w", cerr)
+		}
+	}
+	if q.deleteCronJobStmt != nil {
+		if cerr := q.deleteCronJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCronJobStmt: %w", cerr)
+		}
+	}
 	if q.deleteNodeStmt != nil {
 		if cerr := q.deleteNodeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteNodeStmt: %w", cerr)
 		}
 	}
+	if q.deleteNodeLogFileStmt != nil {
+		if cerr := q.deleteNodeLogFileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteNodeLogFileStmt: %w", cerr)
+		}
+	}
+	if q.deleteNotificationChannelStmt != nil {
+		if cerr := q.deleteNotificationChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteNotificationChannelStmt: %w", cerr)
+		}
+	}
 	if q.deleteProjectStmt != nil {
 		if cerr := q.deleteProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteProjectStmt: %w", cerr)
 		}
 	}
+	if q.deleteStaleNodeDiskInfoStmt != nil {
+		if cerr := q.deleteStaleNodeDiskInfoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteStaleNodeDiskInfoStmt: %w", cerr)
+		}
+	}
+	if q.failCloningProjectsStmt != nil {
+		if cerr := q.failCloningProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failCloningProjectsStmt: %w", cerr)
+		}
+	}
+	if q.failRunningCommandExecutionsStmt != nil {
+		if cerr := q.failRunningCommandExecutionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failRunningCommandExecutionsStmt: %w", cerr)
+		}
+	}
+	if q.failUnfinishedBackupRestoresStmt != nil {
+		if cerr := q.failUnfinishedBackupRestoresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failUnfinishedBackupRestoresStmt: %w", cerr)
+		}
+	}
+	if q.failUnfinishedBackupsStmt != nil {
+		if cerr := q.failUnfinishedBackupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failUnfinishedBackupsStmt: %w", cerr)
+		}
+	}
+	if q.failUnfinishedDeploymentsStmt != nil {
+		if cerr := q.failUnfinishedDeploymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failUnfinishedDeploymentsStmt: %w", cerr)
+		}
+	}
 	if q.findUserByEmailStmt != nil {
 		if cerr := q.findUserByEmailStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing findUserByEmailStmt: %w", cerr)
@@ -239,26 +719,116 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing findUserByIdStmt: %w", cerr)
 		}
 	}
+	if q.finishBackupStmt != nil {
+		if cerr := q.finishBackupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing finishBackupStmt: %w", cerr)
+		}
+	}
+	if q.finishBackupRestoreStmt != nil {
+		if cerr := q.finishBackupRestoreStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing finishBackupRestoreStmt: %w", cerr)
+		}
+	}
+	if q.finishCommandExecutionStmt != nil {
+		if cerr := q.finishCommandExecutionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing finishCommandExecutionStmt: %w", cerr)
+		}
+	}
+	if q.finishDeploymentStmt != nil {
+		if cerr := q.finishDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing finishDeploymentStmt: %w", cerr)
+		}
+	}
 	if q.getActiveAlertsByNodeAndMetricStmt != nil {
 		if cerr := q.getActiveAlertsByNodeAndMetricStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getActiveAlertsByNodeAndMetricStmt: %w", cerr)
 		}
 	}
+	if q.getAgentCertificateBySerialStmt != nil {
+		if cerr := q.getAgentCertificateBySerialStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAgentCertificateBySerialStmt: %w", cerr)
+		}
+	}
+	if q.getAgentTokenStmt != nil {
+		if cerr := q.getAgentTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAgentTokenStmt: %w", cerr)
+		}
+	}
+	if q.getAgentTokenByHashStmt != nil {
+		if cerr := q.getAgentTokenByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAgentTokenByHashStmt: %w", cerr)
+		}
+	}
 	if q.getAlertStmt != nil {
 		if cerr := q.getAlertStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAlertStmt: %w", cerr)
 		}
 	}
+	if q.getAlertStateStmt != nil {
+		if cerr := q.getAlertStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAlertStateStmt: %w", cerr)
+		}
+	}
 	if q.getAlertsStmt != nil {
 		if cerr := q.getAlertsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAlertsStmt: %w", cerr)
 		}
 	}
+	if q.getBackupStmt != nil {
+		if cerr := q.getBackupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBackupStmt: %w", cerr)
+		}
+	}
+	if q.getCertificateAuthorityStmt != nil {
+		if cerr := q.getCertificateAuthorityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCertificateAuthorityStmt: %w", cerr)
+		}
+	}
+	if q.getCommandExecutionStmt != nil {
+		if cerr := q.getCommandExecutionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCommandExecutionStmt: %w", cerr)
+		}
+	}
+	if q.getCronJobStmt != nil {
+		if cerr := q.getCronJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCronJobStmt: %w", cerr)
+		}
+	}
+	if q.getDeploymentStmt != nil {
+		if cerr := q.getDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.getDiskStatsStmt != nil {
+		if cerr := q.getDiskStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDiskStatsStmt: %w", cerr)
+		}
+	}
 	if q.getGitHubTokenStmt != nil {
 		if cerr := q.getGitHubTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getGitHubTokenStmt: %w", cerr)
 		}
 	}
+	if q.getLatestBackupStmt != nil {
+		if cerr := q.getLatestBackupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestBackupStmt: %w", cerr)
+		}
+	}
+	if q.getMountDiskStatWindowStmt != nil {
+		if cerr := q.getMountDiskStatWindowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMountDiskStatWindowStmt: %w", cerr)
+		}
+	}
+	if q.getMountDiskStatsStmt != nil {
+		if cerr := q.getMountDiskStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMountDiskStatsStmt: %w", cerr)
+		}
+	}
+	if q.getNetStatWindowStmt != nil {
+		if cerr := q.getNetStatWindowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNetStatWindowStmt: %w", cerr)
+		}
+	}
 	if q.getNetStatsStmt != nil {
 		if cerr := q.getNetStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNetStatsStmt: %w", cerr)
@@ -274,16 +844,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getNodeByIPStmt: %w", cerr)
 		}
 	}
+	if q.getNodeByMachineIDStmt != nil {
+		if cerr := q.getNodeByMachineIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNodeByMachineIDStmt: %w", cerr)
+		}
+	}
 	if q.getNodeDiskInfoByNodeIDStmt != nil {
 		if cerr := q.getNodeDiskInfoByNodeIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNodeDiskInfoByNodeIDStmt: %w", cerr)
 		}
 	}
+	if q.getNodeIPHistoryStmt != nil {
+		if cerr := q.getNodeIPHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNodeIPHistoryStmt: %w", cerr)
+		}
+	}
 	if q.getNodeSysInfoByNodeIDStmt != nil {
 		if cerr := q.getNodeSysInfoByNodeIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNodeSysInfoByNodeIDStmt: %w", cerr)
 		}
 	}
+	if q.getNodeSysInfoHistoryStmt != nil {
+		if cerr := q.getNodeSysInfoHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNodeSysInfoHistoryStmt: %w", cerr)
+		}
+	}
 	if q.getNodeWithSysInfoStmt != nil {
 		if cerr := q.getNodeWithSysInfoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNodeWithSysInfoStmt: %w", cerr)
@@ -299,6 +884,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getNodesWithSysInfoStmt: %w", cerr)
 		}
 	}
+	if q.getNotificationChannelStmt != nil {
+		if cerr := q.getNotificationChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationChannelStmt: %w", cerr)
+		}
+	}
 	if q.getProjectStmt != nil {
 		if cerr := q.getProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getProjectStmt: %w", cerr)
@@ -309,11 +899,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getProjectWithNodeStmt: %w", cerr)
 		}
 	}
+	if q.getSystemStatWindowStmt != nil {
+		if cerr := q.getSystemStatWindowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSystemStatWindowStmt: %w", cerr)
+		}
+	}
 	if q.getSystemStatsStmt != nil {
 		if cerr := q.getSystemStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSystemStatsStmt: %w", cerr)
 		}
 	}
+	if q.insertDiskStatStmt != nil {
+		if cerr := q.insertDiskStatStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertDiskStatStmt: %w", cerr)
+		}
+	}
 	if q.insertNetStatsStmt != nil {
 		if cerr := q.insertNetStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertNetStatsStmt: %w", cerr)
@@ -324,6 +924,106 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertSystemStatsStmt: %w", cerr)
 		}
 	}
+	if q.listAgentCertificatesByNodeStmt != nil {
+		if cerr := q.listAgentCertificatesByNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAgentCertificatesByNodeStmt: %w", cerr)
+		}
+	}
+	if q.listAgentTokensStmt != nil {
+		if cerr := q.listAgentTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAgentTokensStmt: %w", cerr)
+		}
+	}
+	if q.listAlertChannelsStmt != nil {
+		if cerr := q.listAlertChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAlertChannelsStmt: %w", cerr)
+		}
+	}
+	if q.listBackupRestoresStmt != nil {
+		if cerr := q.listBackupRestoresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBackupRestoresStmt: %w", cerr)
+		}
+	}
+	if q.listBackupsByProjectStmt != nil {
+		if cerr := q.listBackupsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBackupsByProjectStmt: %w", cerr)
+		}
+	}
+	if q.listCommandExecutionsByNodeStmt != nil {
+		if cerr := q.listCommandExecutionsByNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCommandExecutionsByNodeStmt: %w", cerr)
+		}
+	}
+	if q.listCommandExecutionsByProjectStmt != nil {
+		if cerr := q.listCommandExecutionsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCommandExecutionsByProjectStmt: %w", cerr)
+		}
+	}
+	if q.listCronJobChannelsStmt != nil {
+		if cerr := q.listCronJobChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCronJobChannelsStmt: %w", cerr)
+		}
+	}
+	if q.listCronJobsStmt != nil {
+		if cerr := q.listCronJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCronJobsStmt: %w", cerr)
+		}
+	}
+	if q.listCronJobsByNodeStmt != nil {
+		if cerr := q.listCronJobsByNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCronJobsByNodeStmt: %w", cerr)
+		}
+	}
+	if q.listCronRunsStmt != nil {
+		if cerr := q.listCronRunsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listCronRunsStmt: %w", cerr)
+		}
+	}
+	if q.listDeploymentsByProjectStmt != nil {
+		if cerr := q.listDeploymentsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDeploymentsByProjectStmt: %w", cerr)
+		}
+	}
+	if q.listEnabledCronJobsStmt != nil {
+		if cerr := q.listEnabledCronJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEnabledCronJobsStmt: %w", cerr)
+		}
+	}
+	if q.listEnabledCronJobsByNodeStmt != nil {
+		if cerr := q.listEnabledCronJobsByNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEnabledCronJobsByNodeStmt: %w", cerr)
+		}
+	}
+	if q.listGitHubWebhookEventsStmt != nil {
+		if cerr := q.listGitHubWebhookEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGitHubWebhookEventsStmt: %w", cerr)
+		}
+	}
+	if q.listGitHubWebhookEventsByProjectStmt != nil {
+		if cerr := q.listGitHubWebhookEventsByProjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGitHubWebhookEventsByProjectStmt: %w", cerr)
+		}
+	}
+	if q.listNodeLogFilesStmt != nil {
+		if cerr := q.listNodeLogFilesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNodeLogFilesStmt: %w", cerr)
+		}
+	}
+	if q.listNodeProjectsStmt != nil {
+		if cerr := q.listNodeProjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNodeProjectsStmt: %w", cerr)
+		}
+	}
+	if q.listNodesStmt != nil {
+		if cerr := q.listNodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNodesStmt: %w", cerr)
+		}
+	}
+	if q.listNotificationChannelsStmt != nil {
+		if cerr := q.listNotificationChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNotificationChannelsStmt: %w", cerr)
+		}
+	}
 	if q.listProjectsStmt != nil {
 		if cerr := q.listProjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listProjectsStmt: %w", cerr)
@@ -334,26 +1034,151 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listProjectsByNodeStmt: %w", cerr)
 		}
 	}
+	if q.listProjectsWithBackupScheduleStmt != nil {
+		if cerr := q.listProjectsWithBackupScheduleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listProjectsWithBackupScheduleStmt: %w", cerr)
+		}
+	}
 	if q.listProjectsWithNodesStmt != nil {
 		if cerr := q.listProjectsWithNodesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listProjectsWithNodesStmt: %w", cerr)
 		}
 	}
+	if q.listProjectsWithRepoStmt != nil {
+		if cerr := q.listProjectsWithRepoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listProjectsWithRepoStmt: %w", cerr)
+		}
+	}
+	if q.listReleasedDeploymentsStmt != nil {
+		if cerr := q.listReleasedDeploymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listReleasedDeploymentsStmt: %w", cerr)
+		}
+	}
+	if q.listSucceededBackupsStmt != nil {
+		if cerr := q.listSucceededBackupsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSucceededBackupsStmt: %w", cerr)
+		}
+	}
+	if q.listSucceededDeploymentsStmt != nil {
+		if cerr := q.listSucceededDeploymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSucceededDeploymentsStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookDeliveriesStmt != nil {
+		if cerr := q.listWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.markReleasePrunedStmt != nil {
+		if cerr := q.markReleasePrunedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markReleasePrunedStmt: %w", cerr)
+		}
+	}
+	if q.recordNodeIPStmt != nil {
+		if cerr := q.recordNodeIPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recordNodeIPStmt: %w", cerr)
+		}
+	}
 	if q.removeGitHubTokenStmt != nil {
 		if cerr := q.removeGitHubTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing removeGitHubTokenStmt: %w", cerr)
 		}
 	}
+	if q.revokeAgentCertificateStmt != nil {
+		if cerr := q.revokeAgentCertificateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAgentCertificateStmt: %w", cerr)
+		}
+	}
+	if q.revokeAgentTokenStmt != nil {
+		if cerr := q.revokeAgentTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAgentTokenStmt: %w", cerr)
+		}
+	}
+	if q.saveAlertStateStmt != nil {
+		if cerr := q.saveAlertStateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing saveAlertStateStmt: %w", cerr)
+		}
+	}
 	if q.saveGitHubTokenStmt != nil {
 		if cerr := q.saveGitHubTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing saveGitHubTokenStmt: %w", cerr)
 		}
 	}
+	if q.setAlertEventDeliveriesStmt != nil {
+		if cerr := q.setAlertEventDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAlertEventDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.setCommandExecutionCorrelationIDStmt != nil {
+		if cerr := q.setCommandExecutionCorrelationIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCommandExecutionCorrelationIDStmt: %w", cerr)
+		}
+	}
+	if q.setCronJobLastRunStmt != nil {
+		if cerr := q.setCronJobLastRunStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCronJobLastRunStmt: %w", cerr)
+		}
+	}
+	if q.setDeploymentReleaseStmt != nil {
+		if cerr := q.setDeploymentReleaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setDeploymentReleaseStmt: %w", cerr)
+		}
+	}
+	if q.setNodeMachineIDStmt != nil {
+		if cerr := q.setNodeMachineIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setNodeMachineIDStmt: %w", cerr)
+		}
+	}
+	if q.setNodeStatusStmt != nil {
+		if cerr := q.setNodeStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setNodeStatusStmt: %w", cerr)
+		}
+	}
+	if q.setProjectBackupScheduleStmt != nil {
+		if cerr := q.setProjectBackupScheduleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectBackupScheduleStmt: %w", cerr)
+		}
+	}
+	if q.setProjectConfigMissingStmt != nil {
+		if cerr := q.setProjectConfigMissingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectConfigMissingStmt: %w", cerr)
+		}
+	}
+	if q.setProjectCurrentDeploymentStmt != nil {
+		if cerr := q.setProjectCurrentDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectCurrentDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.setProjectWebhookSecretStmt != nil {
+		if cerr := q.setProjectWebhookSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setProjectWebhookSecretStmt: %w", cerr)
+		}
+	}
+	if q.startDeploymentStmt != nil {
+		if cerr := q.startDeploymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing startDeploymentStmt: %w", cerr)
+		}
+	}
+	if q.touchNodeStmt != nil {
+		if cerr := q.touchNodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchNodeStmt: %w", cerr)
+		}
+	}
 	if q.updateAlertStmt != nil {
 		if cerr := q.updateAlertStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAlertStmt: %w", cerr)
 		}
 	}
+	if q.updateCronJobStmt != nil {
+		if cerr := q.updateCronJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateCronJobStmt: %w", cerr)
+		}
+	}
+	if q.updateDeploymentLogsStmt != nil {
+		if cerr := q.updateDeploymentLogsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateDeploymentLogsStmt: %w", cerr)
+		}
+	}
 	if q.updateNodeStmt != nil {
 		if cerr := q.updateNodeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateNodeStmt: %w", cerr)
@@ -364,6 +1189,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateNodeDiskInfoStmt: %w", cerr)
 		}
 	}
+	if q.updateNodeIPStmt != nil {
+		if cerr := q.updateNodeIPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateNodeIPStmt: %w", cerr)
+		}
+	}
 	if q.updateNodeNameStmt != nil {
 		if cerr := q.updateNodeNameStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateNodeNameStmt: %w", cerr)
@@ -374,11 +1204,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateNodeSysInfoStmt: %w", cerr)
 		}
 	}
+	if q.updateNotificationChannelStmt != nil {
+		if cerr := q.updateNotificationChannelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateNotificationChannelStmt: %w", cerr)
+		}
+	}
 	if q.updateProjectStmt != nil {
 		if cerr := q.updateProjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateProjectStmt: %w", cerr)
 		}
 	}
+	if q.updateProjectConfigStmt != nil {
+		if cerr := q.updateProjectConfigStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateProjectConfigStmt: %w", cerr)
+		}
+	}
 	if q.updateProjectLastDeployedStmt != nil {
 		if cerr := q.updateProjectLastDeployedStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateProjectLastDeployedStmt: %w", cerr)
@@ -426,103 +1266,313 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                                 DBTX
-	tx                                 *sql.Tx
-	activateAlertStmt                  *sql.Stmt
-	addNodeDiskInfoStmt                *sql.Stmt
-	addNodeSysInfoStmt                 *sql.Stmt
-	countProjectsStmt                  *sql.Stmt
-	countProjectsByNodeStmt            *sql.Stmt
-	createAlertStmt                    *sql.Stmt
-	createNodeStmt                     *sql.Stmt
-	createProjectStmt                  *sql.Stmt
-	createUserStmt                     *sql.Stmt
-	deactivateAlertStmt                *sql.Stmt
-	deleteAlertStmt                    *sql.Stmt
-	deleteNodeStmt                     *sql.Stmt
-	deleteProjectStmt                  *sql.Stmt
-	findUserByEmailStmt                *sql.Stmt
-	findUserByIdStmt                   *sql.Stmt
-	getActiveAlertsByNodeAndMetricStmt *sql.Stmt
-	getAlertStmt                       *sql.Stmt
-	getAlertsStmt                      *sql.Stmt
-	getGitHubTokenStmt                 *sql.Stmt
-	getNetStatsStmt                    *sql.Stmt
-	getNodeStmt                        *sql.Stmt
-	getNodeByIPStmt                    *sql.Stmt
-	getNodeDiskInfoByNodeIDStmt        *sql.Stmt
-	getNodeSysInfoByNodeIDStmt         *sql.Stmt
-	getNodeWithSysInfoStmt             *sql.Stmt
-	getNodesStmt                       *sql.Stmt
-	getNodesWithSysInfoStmt            *sql.Stmt
-	getProjectStmt                     *sql.Stmt
-	getProjectWithNodeStmt             *sql.Stmt
-	getSystemStatsStmt                 *sql.Stmt
-	insertNetStatsStmt                 *sql.Stmt
-	insertSystemStatsStmt              *sql.Stmt
-	listProjectsStmt                   *sql.Stmt
-	listProjectsByNodeStmt             *sql.Stmt
-	listProjectsWithNodesStmt          *sql.Stmt
-	removeGitHubTokenStmt              *sql.Stmt
-	saveGitHubTokenStmt                *sql.Stmt
-	updateAlertStmt                    *sql.Stmt
-	updateNodeStmt                     *sql.Stmt
-	updateNodeDiskInfoStmt             *sql.Stmt
-	updateNodeNameStmt                 *sql.Stmt
-	updateNodeSysInfoStmt              *sql.Stmt
-	updateProjectStmt                  *sql.Stmt
-	updateProjectLastDeployedStmt      *sql.Stmt
-	updateProjectStatusStmt            *sql.Stmt
+	db                                   DBTX
+	tx                                   *sql.Tx
+	activateAlertStmt                    *sql.Stmt
+	addAlertChannelStmt                  *sql.Stmt
+	addCronJobChannelStmt                *sql.Stmt
+	addNodeDiskInfoStmt                  *sql.Stmt
+	addNodeSysInfoStmt                   *sql.Stmt
+	addNodeSysInfoHistoryStmt            *sql.Stmt
+	bindAgentTokenStmt                   *sql.Stmt
+	clearAlertChannelsStmt               *sql.Stmt
+	clearCronJobChannelsStmt             *sql.Stmt
+	countActiveDeploymentsByProjectStmt  *sql.Stmt
+	countProjectsStmt                    *sql.Stmt
+	countProjectsByNodeStmt              *sql.Stmt
+	createAgentCertificateStmt           *sql.Stmt
+	createAgentTokenStmt                 *sql.Stmt
+	createAlertStmt                      *sql.Stmt
+	createAlertEventStmt                 *sql.Stmt
+	createBackupStmt                     *sql.Stmt
+	createBackupRestoreStmt              *sql.Stmt
+	createCertificateAuthorityStmt       *sql.Stmt
+	createCommandExecutionStmt           *sql.Stmt
+	createCronJobStmt                    *sql.Stmt
+	createCronRunStmt                    *sql.Stmt
+	createDeploymentStmt                 *sql.Stmt
+	createDiscoveredProjectStmt          *sql.Stmt
+	createGitHubWebhookEventStmt         *sql.Stmt
+	createNodeStmt                       *sql.Stmt
+	createNodeLogFileStmt                *sql.Stmt
+	createNotificationChannelStmt        *sql.Stmt
+	createProjectStmt                    *sql.Stmt
+	createUserStmt                       *sql.Stmt
+	createWebhookDeliveryStmt            *sql.Stmt
+	deactivateAlertStmt                  *sql.Stmt
+	deleteAlertStmt                      *sql.Stmt
+	deleteAlertStateStmt                 *sql.Stmt
+	deleteBackupStmt                     *sql.Stmt
+	deleteCronJobStmt                    *sql.Stmt
+	deleteNodeStmt                       *sql.Stmt
+	deleteNodeLogFileStmt                *sql.Stmt
+	deleteNotificationChannelStmt        *sql.Stmt
+	deleteProjectStmt                    *sql.Stmt
+	deleteStaleNodeDiskInfoStmt          *sql.Stmt
+	failCloningProjectsStmt              *sql.Stmt
+	failRunningCommandExecutionsStmt     *sql.Stmt
+	failUnfinishedBackupRestoresStmt     *sql.Stmt
+	failUnfinishedBackupsStmt            *sql.Stmt
+	failUnfinishedDeploymentsStmt        *sql.Stmt
+	findUserByEmailStmt                  *sql.Stmt
+	findUserByIdStmt                     *sql.Stmt
+	finishBackupStmt                     *sql.Stmt
+	finishBackupRestoreStmt              *sql.Stmt
+	finishCommandExecutionStmt           *sql.Stmt
+	finishDeploymentStmt                 *sql.Stmt
+	getActiveAlertsByNodeAndMetricStmt   *sql.Stmt
+	getAgentCertificateBySerialStmt      *sql.Stmt
+	getAgentTokenStmt                    *sql.Stmt
+	getAgentTokenByHashStmt              *sql.Stmt
+	getAlertStmt                         *sql.Stmt
+	getAlertStateStmt                    *sql.Stmt
+	getAlertsStmt                        *sql.Stmt
+	getBackupStmt                        *sql.Stmt
+	getCertificateAuthorityStmt          *sql.Stmt
+	getCommandExecutionStmt              *sql.Stmt
+	getCronJobStmt                       *sql.Stmt
+	getDeploymentStmt                    *sql.Stmt
+	getDiskStatsStmt                     *sql.Stmt
+	getGitHubTokenStmt                   *sql.Stmt
+	getLatestBackupStmt                  *sql.Stmt
+	getMountDiskStatWindowStmt           *sql.Stmt
+	getMountDiskStatsStmt                *sql.Stmt
+	getNetStatWindowStmt                 *sql.Stmt
+	getNetStatsStmt                      *sql.Stmt
+	getNodeStmt                          *sql.Stmt
+	getNodeByIPStmt                      *sql.Stmt
+	getNodeByMachineIDStmt               *sql.Stmt
+	getNodeDiskInfoByNodeIDStmt          *sql.Stmt
+	getNodeIPHistoryStmt                 *sql.Stmt
+	getNodeSysInfoByNodeIDStmt           *sql.Stmt
+	getNodeSysInfoHistoryStmt            *sql.Stmt
+	getNodeWithSysInfoStmt               *sql.Stmt
+	getNodesStmt                         *sql.Stmt
+	getNodesWithSysInfoStmt              *sql.Stmt
+	getNotificationChannelStmt           *sql.Stmt
+	getProjectStmt                       *sql.Stmt
+	getProjectWithNodeStmt               *sql.Stmt
+	getSystemStatWindowStmt              *sql.Stmt
+	getSystemStatsStmt                   *sql.Stmt
+	insertDiskStatStmt                   *sql.Stmt
+	insertNetStatsStmt                   *sql.Stmt
+	insertSystemStatsStmt                *sql.Stmt
+	listAgentCertificatesByNodeStmt      *sql.Stmt
+	listAgentTokensStmt                  *sql.Stmt
+	listAlertChannelsStmt                *sql.Stmt
+	listBackupRestoresStmt               *sql.Stmt
+	listBackupsByProjectStmt             *sql.Stmt
+	listCommandExecutionsByNodeStmt      *sql.Stmt
+	listCommandExecutionsByProjectStmt   *sql.Stmt
+	listCronJobChannelsStmt              *sql.Stmt
+	listCronJobsStmt                     *sql.Stmt
+	listCronJobsByNodeStmt               *sql.Stmt
+	listCronRunsStmt                     *sql.Stmt
+	listDeploymentsByProjectStmt         *sql.Stmt
+	listEnabledCronJobsStmt              *sql.Stmt
+	listEnabledCronJobsByNodeStmt        *sql.Stmt
+	listGitHubWebhookEventsStmt          *sql.Stmt
+	listGitHubWebhookEventsByProjectStmt *sql.Stmt
+	listNodeLogFilesStmt                 *sql.Stmt
+	listNodeProjectsStmt                 *sql.Stmt
+	listNodesStmt                        *sql.Stmt
+	listNotificationChannelsStmt         *sql.Stmt
+	listProjectsStmt                     *sql.Stmt
+	listProjectsByNodeStmt               *sql.Stmt
+	listProjectsWithBackupScheduleStmt   *sql.Stmt
+	listProjectsWithNodesStmt            *sql.Stmt
+	listProjectsWithRepoStmt             *sql.Stmt
+	listReleasedDeploymentsStmt          *sql.Stmt
+	listSucceededBackupsStmt             *sql.Stmt
+	listSucceededDeploymentsStmt         *sql.Stmt
+	listWebhookDeliveriesStmt            *sql.Stmt
+	markReleasePrunedStmt                *sql.Stmt
+	recordNodeIPStmt                     *sql.Stmt
+	removeGitHubTokenStmt                *sql.Stmt
+	revokeAgentCertificateStmt           *sql.Stmt
+	revokeAgentTokenStmt                 *sql.Stmt
+	saveAlertStateStmt                   *sql.Stmt
+	saveGitHubTokenStmt                  *sql.Stmt
+	setAlertEventDeliveriesStmt          *sql.Stmt
+	setCommandExecutionCorrelationIDStmt *sql.Stmt
+	setCronJobLastRunStmt                *sql.Stmt
+	setDeploymentReleaseStmt             *sql.Stmt
+	setNodeMachineIDStmt                 *sql.Stmt
+	setNodeStatusStmt                    *sql.Stmt
+	setProjectBackupScheduleStmt         *sql.Stmt
+	setProjectConfigMissingStmt          *sql.Stmt
+	setProjectCurrentDeploymentStmt      *sql.Stmt
+	setProjectWebhookSecretStmt          *sql.Stmt
+	startDeploymentStmt                  *sql.Stmt
+	touchNodeStmt                        *sql.Stmt
+	updateAlertStmt                      *sql.Stmt
+	updateCronJobStmt                    *sql.Stmt
+	updateDeploymentLogsStmt             *sql.Stmt
+	updateNodeStmt                       *sql.Stmt
+	updateNodeDiskInfoStmt               *sql.Stmt
+	updateNodeIPStmt                     *sql.Stmt
+	updateNodeNameStmt                   *sql.Stmt
+	updateNodeSysInfoStmt                *sql.Stmt
+	updateNotificationChannelStmt        *sql.Stmt
+	updateProjectStmt                    *sql.Stmt
+	updateProjectConfigStmt              *sql.Stmt
+	updateProjectLastDeployedStmt        *sql.Stmt
+	updateProjectStatusStmt              *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                                 tx,
-		tx:                                 tx,
-		activateAlertStmt:                  q.activateAlertStmt,
-		addNodeDiskInfoStmt:                q.addNodeDiskInfoStmt,
-		addNodeSysInfoStmt:                 q.addNodeSysInfoStmt,
-		countProjectsStmt:                  q.countProjectsStmt,
-		countProjectsByNodeStmt:            q.countProjectsByNodeStmt,
-		createAlertStmt:                    q.createAlertStmt,
-		createNodeStmt:                     q.createNodeStmt,
-		createProjectStmt:                  q.createProjectStmt,
-		createUserStmt:                     q.createUserStmt,
-		deactivateAlertStmt:                q.deactivateAlertStmt,
-		deleteAlertStmt:                    q.deleteAlertStmt,
-		deleteNodeStmt:                     q.deleteNodeStmt,
-		deleteProjectStmt:                  q.deleteProjectStmt,
-		findUserByEmailStmt:                q.findUserByEmailStmt,
-		findUserByIdStmt:                   q.findUserByIdStmt,
-		getActiveAlertsByNodeAndMetricStmt: q.getActiveAlertsByNodeAndMetricStmt,
-		getAlertStmt:                       q.getAlertStmt,
-		getAlertsStmt:                      q.getAlertsStmt,
-		getGitHubTokenStmt:                 q.getGitHubTokenStmt,
-		getNetStatsStmt:                    q.getNetStatsStmt,
-		getNodeStmt:                        q.getNodeStmt,
-		getNodeByIPStmt:                    q.getNodeByIPStmt,
-		getNodeDiskInfoByNodeIDStmt:        q.getNodeDiskInfoByNodeIDStmt,
-		getNodeSysInfoByNodeIDStmt:         q.getNodeSysInfoByNodeIDStmt,
-		getNodeWithSysInfoStmt:             q.getNodeWithSysInfoStmt,
-		getNodesStmt:                       q.getNodesStmt,
-		getNodesWithSysInfoStmt:            q.getNodesWithSysInfoStmt,
-		getProjectStmt:                     q.getProjectStmt,
-		getProjectWithNodeStmt:             q.getProjectWithNodeStmt,
-		getSystemStatsStmt:                 q.getSystemStatsStmt,
-		insertNetStatsStmt:                 q.insertNetStatsStmt,
-		insertSystemStatsStmt:              q.insertSystemStatsStmt,
-		listProjectsStmt:                   q.listProjectsStmt,
-		listProjectsByNodeStmt:             q.listProjectsByNodeStmt,
-		listProjectsWithNodesStmt:          q.listProjectsWithNodesStmt,
-		removeGitHubTokenStmt:              q.removeGitHubTokenStmt,
-		saveGitHubTokenStmt:                q.saveGitHubTokenStmt,
-		updateAlertStmt:                    q.updateAlertStmt,
-		updateNodeStmt:                     q.updateNodeStmt,
-		updateNodeDiskInfoStmt:             q.updateNodeDiskInfoStmt,
-		updateNodeNameStmt:                 q.updateNodeNameStmt,
-		updateNodeSysInfoStmt:              q.updateNodeSysInfoStmt,
-		updateProjectStmt:                  q.updateProjectStmt,
-		updateProjectLastDeployedStmt:      q.updateProjectLastDeployedStmt,
-		updateProjectStatusStmt:            q.updateProjectStatusStmt,
+		db:                                   tx,
+		tx:                                   tx,
+		activateAlertStmt:                    q.activateAlertStmt,
+		addAlertChannelStmt:                  q.addAlertChannelStmt,
+		addCronJobChannelStmt:                q.addCronJobChannelStmt,
+		addNodeDiskInfoStmt:                  q.addNodeDiskInfoStmt,
+		addNodeSysInfoStmt:                   q.addNodeSysInfoStmt,
+		addNodeSysInfoHistoryStmt:            q.addNodeSysInfoHistoryStmt,
+		bindAgentTokenStmt:                   q.bindAgentTokenStmt,
+		clearAlertChannelsStmt:               q.clearAlertChannelsStmt,
+		clearCronJobChannelsStmt:             q.clearCronJobChannelsStmt,
+		countActiveDeploymentsByProjectStmt:  q.countActiveDeploymentsByProjectStmt,
+		countProjectsStmt:                    q.countProjectsStmt,
+		countProjectsByNodeStmt:              q.countProjectsByNodeStmt,
+		createAgentCertificateStmt:           q.createAgentCertificateStmt,
+		createAgentTokenStmt:                 q.createAgentTokenStmt,
+		createAlertStmt:                      q.createAlertStmt,
+		createAlertEventStmt:                 q.createAlertEventStmt,
+		createBackupStmt:                     q.createBackupStmt,
+		createBackupRestoreStmt:              q.createBackupRestoreStmt,
+		createCertificateAuthorityStmt:       q.createCertificateAuthorityStmt,
+		createCommandExecutionStmt:           q.createCommandExecutionStmt,
+		createCronJobStmt:                    q.createCronJobStmt,
+		createCronRunStmt:                    q.createCronRunStmt,
+		createDeploymentStmt:                 q.createDeploymentStmt,
+		createDiscoveredProjectStmt:          q.createDiscoveredProjectStmt,
+		createGitHubWebhookEventStmt:         q.createGitHubWebhookEventStmt,
+		createNodeStmt:                       q.createNodeStmt,
+		createNodeLogFileStmt:                q.createNodeLogFileStmt,
+		createNotificationChannelStmt:        q.createNotificationChannelStmt,
+		createProjectStmt:                    q.createProjectStmt,
+		createUserStmt:                       q.createUserStmt,
+		createWebhookDeliveryStmt:            q.createWebhookDeliveryStmt,
+		deactivateAlertStmt:                  q.deactivateAlertStmt,
+		deleteAlertStmt:                      q.deleteAlertStmt,
+		deleteAlertStateStmt:                 q.deleteAlertStateStmt,
+		deleteBackupStmt:                     q.deleteBackupStmt,
+		deleteCronJobStmt:                    q.deleteCronJobStmt,
+		deleteNodeStmt:                       q.deleteNodeStmt,
+		deleteNodeLogFileStmt:                q.deleteNodeLogFileStmt,
+		deleteNotificationChannelStmt:        q.deleteNotificationChannelStmt,
+		deleteProjectStmt:                    q.deleteProjectStmt,
+		deleteStaleNodeDiskInfoStmt:          q.deleteStaleNodeDiskInfoStmt,
+		failCloningProjectsStmt:              q.failCloningProjectsStmt,
+		failRunningCommandExecutionsStmt:     q.failRunningCommandExecutionsStmt,
+		failUnfinishedBackupRestoresStmt:     q.failUnfinishedBackupRestoresStmt,
+		failUnfinishedBackupsStmt:            q.failUnfinishedBackupsStmt,
+		failUnfinishedDeploymentsStmt:        q.failUnfinishedDeploymentsStmt,
+		findUserByEmailStmt:                  q.findUserByEmailStmt,
+		findUserByIdStmt:                     q.findUserByIdStmt,
+		finishBackupStmt:                     q.finishBackupStmt,
+		finishBackupRestoreStmt:              q.finishBackupRestoreStmt,
+		finishCommandExecutionStmt:           q.finishCommandExecutionStmt,
+		finishDeploymentStmt:                 q.finishDeploymentStmt,
+		getActiveAlertsByNodeAndMetricStmt:   q.getActiveAlertsByNodeAndMetricStmt,
+		getAgentCertificateBySerialStmt:      q.getAgentCertificateBySerialStmt,
+		getAgentTokenStmt:                    q.getAgentTokenStmt,
+		getAgentTokenByHashStmt:              q.getAgentTokenByHashStmt,
+		getAlertStmt:                         q.getAlertStmt,
+		getAlertStateStmt:                    q.getAlertStateStmt,
+		getAlertsStmt:                        q.getAlertsStmt,
+		getBackupStmt:                        q.getBackupStmt,
+		getCertificateAuthorityStmt:          q.getCertificateAuthorityStmt,
+		getCommandExecutionStmt:              q.getCommandExecutionStmt,
+		getCronJobStmt:                       q.getCronJobStmt,
+		getDeploymentStmt:                    q.getDeploymentStmt,
+		getDiskStatsStmt:                     q.getDiskStatsStmt,
+		getGitHubTokenStmt:                   q.getGitHubTokenStmt,
+		getLatestBackupStmt:                  q.getLatestBackupStmt,
+		getMountDiskStatWindowStmt:           q.getMountDiskStatWindowStmt,
+		getMountDiskStatsStmt:                q.getMountDiskStatsStmt,
+		getNetStatWindowStmt:                 q.getNetStatWindowStmt,
+		getNetStatsStmt:                      q.getNetStatsStmt,
+		getNodeStmt:                          q.getNodeStmt,
+		getNodeByIPStmt:                      q.getNodeByIPStmt,
+		getNodeByMachineIDStmt:               q.getNodeByMachineIDStmt,
+		getNodeDiskInfoByNodeIDStmt:          q.getNodeDiskInfoByNodeIDStmt,
+		getNodeIPHistoryStmt:                 q.getNodeIPHistoryStmt,
+		getNodeSysInfoByNodeIDStmt:           q.getNodeSysInfoByNodeIDStmt,
+		getNodeSysInfoHistoryStmt:            q.getNodeSysInfoHistoryStmt,
+		getNodeWithSysInfoStmt:               q.getNodeWithSysInfoStmt,
+		getNodesStmt:                         q.getNodesStmt,
+		getNodesWithSysInfoStmt:              q.getNodesWithSysInfoStmt,
+		getNotificationChannelStmt:           q.getNotificationChannelStmt,
+		getProjectStmt:                       q.getProjectStmt,
+		getProjectWithNodeStmt:               q.getProjectWithNodeStmt,
+		getSystemStatWindowStmt:              q.getSystemStatWindowStmt,
+		getSystemStatsStmt:                   q.getSystemStatsStmt,
+		insertDiskStatStmt:                   q.insertDiskStatStmt,
+		insertNetStatsStmt:                   q.insertNetStatsStmt,
+		insertSystemStatsStmt:                q.insertSystemStatsStmt,
+		listAgentCertificatesByNodeStmt:      q.listAgentCertificatesByNodeStmt,
+		listAgentTokensStmt:                  q.listAgentTokensStmt,
+		listAlertChannelsStmt:                q.listAlertChannelsStmt,
+		listBackupRestoresStmt:               q.listBackupRestoresStmt,
+		listBackupsByProjectStmt:             q.listBackupsByProjectStmt,
+		listCommandExecutionsByNodeStmt:      q.listCommandExecutionsByNodeStmt,
+		listCommandExecutionsByProjectStmt:   q.listCommandExecutionsByProjectStmt,
+		listCronJobChannelsStmt:              q.listCronJobChannelsStmt,
+		listCronJobsStmt:                     q.listCronJobsStmt,
+		listCronJobsByNodeStmt:               q.listCronJobsByNodeStmt,
+		listCronRunsStmt:                     q.listCronRunsStmt,
+		listDeploymentsByProjectStmt:         q.listDeploymentsByProjectStmt,
+		listEnabledCronJobsStmt:              q.listEnabledCronJobsStmt,
+		listEnabledCronJobsByNodeStmt:        q.listEnabledCronJobsByNodeStmt,
+		listGitHubWebhookEventsStmt:          q.listGitHubWebhookEventsStmt,
+		listGitHubWebhookEventsByProjectStmt: q.listGitHubWebhookEventsByProjectStmt,
+		listNodeLogFilesStmt:                 q.listNodeLogFilesStmt,
+		listNodeProjectsStmt:                 q.listNodeProjectsStmt,
+		listNodesStmt:                        q.listNodesStmt,
+		listNotificationChannelsStmt:         q.listNotificationChannelsStmt,
+		listProjectsStmt:                     q.listProjectsStmt,
+		listProjectsByNodeStmt:               q.listProjectsByNodeStmt,
+		listProjectsWithBackupScheduleStmt:   q.listProjectsWithBackupScheduleStmt,
+		listProjectsWithNodesStmt:            q.listProjectsWithNodesStmt,
+		listProjectsWithRepoStmt:             q.listProjectsWithRepoStmt,
+		listReleasedDeploymentsStmt:          q.listReleasedDeploymentsStmt,
+		listSucceededBackupsStmt:             q.listSucceededBackupsStmt,
+		listSucceededDeploymentsStmt:         q.listSucceededDeploymentsStmt,
+		listWebhookDeliveriesStmt:            q.listWebhookDeliveriesStmt,
+		markReleasePrunedStmt:                q.markReleasePrunedStmt,
+		recordNodeIPStmt:                     q.recordNodeIPStmt,
+		removeGitHubTokenStmt:                q.removeGitHubTokenStmt,
+		revokeAgentCertificateStmt:           q.revokeAgentCertificateStmt,
+		revokeAgentTokenStmt:                 q.revokeAgentTokenStmt,
+		saveAlertStateStmt:                   q.saveAlertStateStmt,
+		saveGitHubTokenStmt:                  q.saveGitHubTokenStmt,
+		setAlertEventDeliveriesStmt:          q.setAlertEventDeliveriesStmt,
+		setCommandExecutionCorrelationIDStmt: q.setCommandExecutionCorrelationIDStmt,
+		setCronJobLastRunStmt:                q.setCronJobLastRunStmt,
+		setDeploymentReleaseStmt:             q.setDeploymentReleaseStmt,
+		setNodeMachineIDStmt:                 q.setNodeMachineIDStmt,
+		setNodeStatusStmt:                    q.setNodeStatusStmt,
+		setProjectBackupScheduleStmt:         q.setProjectBackupScheduleStmt,
+		setProjectConfigMissingStmt:          q.setProjectConfigMissingStmt,
+		setProjectCurrentDeploymentStmt:      q.setProjectCurrentDeploymentStmt,
+		setProjectWebhookSecretStmt:          q.setProjectWebhookSecretStmt,
+		startDeploymentStmt:                  q.startDeploymentStmt,
+		touchNodeStmt:                        q.touchNodeStmt,
+		updateAlertStmt:                      q.updateAlertStmt,
+		updateCronJobStmt:                    q.updateCronJobStmt,
+		updateDeploymentLogsStmt:             q.updateDeploymentLogsStmt,
+		updateNodeStmt:                       q.updateNodeStmt,
+		updateNodeDiskInfoStmt:               q.updateNodeDiskInfoStmt,
+		updateNodeIPStmt:                     q.updateNodeIPStmt,
+		updateNodeNameStmt:                   q.updateNodeNameStmt,
+		updateNodeSysInfoStmt:                q.updateNodeSysInfoStmt,
+		updateNotificationChannelStmt:        q.updateNotificationChannelStmt,
+		updateProjectStmt:                    q.updateProjectStmt,
+		updateProjectConfigStmt:              q.updateProjectConfigStmt,
+		updateProjectLastDeployedStmt:        q.updateProjectLastDeployedStmt,
+		updateProjectStatusStmt:              q.updateProjectStatusStmt,
 	}
 }
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -42,12 +43,14 @@ var DefaultMigrationConfig = MigrationConfig{
 	TimeseriesIncludePatterns: []string{
 		"system_stat",
 		"net_stat",
+		"disk_stat",
 	},
 	OperationalExcludePatterns: []string{
 		"retention_policy",
 		"enable_tablefunc",
 		"system_stat",
 		"net_stat",
+		"disk_stat",
 	},
 }
 
@@ -110,30 +113,54 @@ func RunMigrationsWithConfig(db *sql.DB, dbType string, config *MigrationConfig)
 
 		fmt.Printf("Applying migration %s: %s\n", migration.Version, migration.Name)
 
-		// Start transaction
-		tx, err := db.Begin()
-		if err != nil {
-			return fmt.Errorf("failed to start transaction: %w", err)
+		if err := applyMigration(db, migration); err != nil {
+			return err
 		}
 
-		// Execute migration
-		if _, err := tx.Exec(migration.UpSQL); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to execute migration %s: %w", migration.Version, err)
-		}
+		fmt.Printf("Migration %s applied successfully\n", migration.Version)
+	}
 
-		// Record migration
-		if _, err := tx.Exec("INSERT INTO migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
-		}
+	return nil
+}
 
-		// Commit transaction
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit migration %s: %w", migration.Version, err)
-		}
+// applyMigration runs a single migration in a transaction on a dedicated connection.
+// Foreign key enforcement is switched off while the migration runs so that table
+// rebuilds (create new, copy, drop old, rename) do not cascade deletes into child tables.
+func applyMigration(db *sql.DB, migration Migration) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
 
-		fmt.Printf("Migration %s applied successfully\n", migration.Version)
+	// PRAGMA foreign_keys is a no-op inside a transaction, so it is set before BEGIN
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	// Start transaction
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	// Execute migration
+	if _, err := tx.Exec(migration.UpSQL); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to execute migration %s: %w", migration.Version, err)
+	}
+
+	// Record migration
+	if _, err := tx.Exec("INSERT INTO migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", migration.Version, err)
 	}
 
 	return nil
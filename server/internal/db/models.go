@@ -8,6 +8,28 @@ import (
 	"database/sql"
 )
 
+type AgentCertificate struct {
+	ID          int64         `json:"id"`
+	NodeID      int64         `json:"node_id"`
+	Serial      string        `json:"serial"`
+	Fingerprint string        `json:"fingerprint"`
+	NotAfter    int64         `json:"not_after"`
+	RevokedAt   sql.NullInt64 `json:"revoked_at"`
+	CreatedAt   int64         `json:"created_at"`
+}
+
+type AgentToken struct {
+	ID         int64         `json:"id"`
+	Name       string        `json:"name"`
+	TokenHash  string        `json:"token_hash"`
+	NodeID     sql.NullInt64 `json:"node_id"`
+	ExpiresAt  sql.NullInt64 `json:"expires_at"`
+	RevokedAt  sql.NullInt64 `json:"revoked_at"`
+	LastUsedAt sql.NullInt64 `json:"last_used_at"`
+	CreatedAt  int64         `json:"created_at"`
+	UpdatedAt  int64         `json:"updated_at"`
+}
+
 type Alert struct {
 	ID               int64           `json:"id"`
 	NodeID           int64           `json:"node_id"`
@@ -16,12 +38,173 @@ type Alert struct {
 	Threshold        sql.NullFloat64 `json:"threshold"`
 	NetReceThreshold sql.NullFloat64 `json:"net_rece_threshold"`
 	NetSendThreshold sql.NullFloat64 `json:"net_send_threshold"`
-	Email            sql.NullString  `json:"email"`
-	DiscordWebhook   sql.NullString  `json:"discord_webhook"`
-	SlackWebhook     sql.NullString  `json:"slack_webhook"`
 	IsActive         sql.NullInt64   `json:"is_active"`
 	CreatedAt        int64           `json:"created_at"`
 	UpdatedAt        int64           `json:"updated_at"`
+	Mountpoint       sql.NullString  `json:"mountpoint"`
+	Aggregation      string          `json:"aggregation"`
+	ResendInterval   int64           `json:"resend_interval"`
+}
+
+type AlertChannel struct {
+	AlertID   int64 `json:"alert_id"`
+	ChannelID int64 `json:"channel_id"`
+}
+
+type AlertEvent struct {
+	ID         int64          `json:"id"`
+	AlertID    int64          `json:"alert_id"`
+	NodeID     int64          `json:"node_id"`
+	Metric     string         `json:"metric"`
+	Value      sql.NullString `json:"value"`
+	Threshold  sql.NullString `json:"threshold"`
+	FromState  string         `json:"from_state"`
+	ToState    string         `json:"to_state"`
+	BreachedAt sql.NullInt64  `json:"breached_at"`
+	Deliveries sql.NullString `json:"deliveries"`
+	CreatedAt  int64          `json:"created_at"`
+}
+
+type AlertState struct {
+	AlertID    int64          `json:"alert_id"`
+	State      string         `json:"state"`
+	Value      sql.NullString `json:"value"`
+	BreachedAt sql.NullInt64  `json:"breached_at"`
+	FiredAt    sql.NullInt64  `json:"fired_at"`
+	ResolvedAt sql.NullInt64  `json:"resolved_at"`
+	UpdatedAt  int64          `json:"updated_at"`
+	NotifiedAt sql.NullInt64  `json:"notified_at"`
+}
+
+type Backup struct {
+	ID          int64          `json:"id"`
+	ProjectID   string         `json:"project_id"`
+	NodeID      int64          `json:"node_id"`
+	Status      string         `json:"status"`
+	TriggerType string         `json:"trigger_type"`
+	TriggeredBy sql.NullInt64  `json:"triggered_by"`
+	FileName    string         `json:"file_name"`
+	FilePath    string         `json:"file_path"`
+	SizeBytes   int64          `json:"size_bytes"`
+	Sha256      sql.NullString `json:"sha256"`
+	Contents    sql.NullString `json:"contents"`
+	Error       sql.NullString `json:"error"`
+	StartedAt   int64          `json:"started_at"`
+	FinishedAt  sql.NullInt64  `json:"finished_at"`
+}
+
+type BackupRestore struct {
+	ID              int64          `json:"id"`
+	BackupID        int64          `json:"backup_id"`
+	ProjectID       string         `json:"project_id"`
+	Status          string         `json:"status"`
+	RestoreDatabase int64          `json:"restore_database"`
+	TriggeredBy     sql.NullInt64  `json:"triggered_by"`
+	Restored        sql.NullString `json:"restored"`
+	Error           sql.NullString `json:"error"`
+	StartedAt       int64          `json:"started_at"`
+	FinishedAt      sql.NullInt64  `json:"finished_at"`
+}
+
+type CertificateAuthority struct {
+	ID        int64  `json:"id"`
+	CertPem   string `json:"cert_pem"`
+	KeyPem    string `json:"key_pem"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type CommandExecution struct {
+	ID            int64          `json:"id"`
+	NodeID        int64          `json:"node_id"`
+	UserID        sql.NullInt64  `json:"user_id"`
+	Command       string         `json:"command"`
+	CorrelationID sql.NullString `json:"correlation_id"`
+	Status        string         `json:"status"`
+	ExitCode      sql.NullInt64  `json:"exit_code"`
+	Output        string         `json:"output"`
+	Error         sql.NullString `json:"error"`
+	DurationMs    sql.NullInt64  `json:"duration_ms"`
+	StartedAt     int64          `json:"started_at"`
+	FinishedAt    sql.NullInt64  `json:"finished_at"`
+	ProjectID     sql.NullString `json:"project_id"`
+	CommandName   sql.NullString `json:"command_name"`
+}
+
+type CronJob struct {
+	ID              int64          `json:"id"`
+	NodeID          int64          `json:"node_id"`
+	Name            string         `json:"name"`
+	Schedule        string         `json:"schedule"`
+	Command         string         `json:"command"`
+	WorkingDir      sql.NullString `json:"working_dir"`
+	RunAsUser       sql.NullString `json:"run_as_user"`
+	Enabled         int64          `json:"enabled"`
+	LastStatus      sql.NullString `json:"last_status"`
+	LastRunAt       sql.NullInt64  `json:"last_run_at"`
+	LastScheduledAt sql.NullInt64  `json:"last_scheduled_at"`
+	CreatedAt       int64          `json:"created_at"`
+	UpdatedAt       int64          `json:"updated_at"`
+}
+
+type CronJobChannel struct {
+	CronJobID int64 `json:"cron_job_id"`
+	ChannelID int64 `json:"channel_id"`
+}
+
+type CronRun struct {
+	ID          int64          `json:"id"`
+	CronJobID   int64          `json:"cron_job_id"`
+	NodeID      int64          `json:"node_id"`
+	Status      string         `json:"status"`
+	ExitCode    sql.NullInt64  `json:"exit_code"`
+	DurationMs  int64          `json:"duration_ms"`
+	Output      sql.NullString `json:"output"`
+	Error       sql.NullString `json:"error"`
+	ScheduledAt int64          `json:"scheduled_at"`
+	StartedAt   sql.NullInt64  `json:"started_at"`
+	FinishedAt  sql.NullInt64  `json:"finished_at"`
+	CreatedAt   int64          `json:"created_at"`
+}
+
+type Deployment struct {
+	ID            int64          `json:"id"`
+	ProjectID     string         `json:"project_id"`
+	NodeID        int64          `json:"node_id"`
+	Branch        sql.NullString `json:"branch"`
+	CommitSha     sql.NullString `json:"commit_sha"`
+	Status        string         `json:"status"`
+	Logs          string         `json:"logs"`
+	Error         sql.NullString `json:"error"`
+	StartedAt     sql.NullInt64  `json:"started_at"`
+	FinishedAt    sql.NullInt64  `json:"finished_at"`
+	CreatedAt     int64          `json:"created_at"`
+	TriggeredBy   sql.NullInt64  `json:"triggered_by"`
+	RollbackOf    sql.NullInt64  `json:"rollback_of"`
+	ReleasePath   sql.NullString `json:"release_path"`
+	ReleasePruned int64          `json:"release_pruned"`
+}
+
+type DiskStat struct {
+	Timestamp   int64   `json:"timestamp"`
+	NodeID      int64   `json:"node_id"`
+	Mountpoint  string  `json:"mountpoint"`
+	Total       int64   `json:"total"`
+	Used        int64   `json:"used"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+type GithubWebhookEvent struct {
+	ID           int64          `json:"id"`
+	DeliveryID   sql.NullString `json:"delivery_id"`
+	Event        string         `json:"event"`
+	Repository   sql.NullString `json:"repository"`
+	Branch       sql.NullString `json:"branch"`
+	CommitSha    sql.NullString `json:"commit_sha"`
+	ProjectID    sql.NullString `json:"project_id"`
+	DeploymentID sql.NullInt64  `json:"deployment_id"`
+	Status       string         `json:"status"`
+	Reason       sql.NullString `json:"reason"`
+	CreatedAt    int64          `json:"created_at"`
 }
 
 type NetStat struct {
@@ -32,11 +215,14 @@ type NetStat struct {
 }
 
 type Node struct {
-	ID        int64          `json:"id"`
-	Name      sql.NullString `json:"name"`
-	Ip        string         `json:"ip"`
-	CreatedAt int64          `json:"created_at"`
-	UpdatedAt int64          `json:"updated_at"`
+	ID         int64          `json:"id"`
+	Name       sql.NullString `json:"name"`
+	Ip         string         `json:"ip"`
+	MachineID  sql.NullString `json:"machine_id"`
+	CreatedAt  int64          `json:"created_at"`
+	UpdatedAt  int64          `json:"updated_at"`
+	LastSeenAt sql.NullInt64  `json:"last_seen_at"`
+	Status     string         `json:"status"`
 }
 
 type NodeDiskInfo struct {
@@ -51,6 +237,21 @@ type NodeDiskInfo struct {
 	UpdatedAt  int64           `json:"updated_at"`
 }
 
+type NodeIpHistory struct {
+	ID          int64  `json:"id"`
+	NodeID      int64  `json:"node_id"`
+	Ip          string `json:"ip"`
+	FirstSeenAt int64  `json:"first_seen_at"`
+	LastSeenAt  int64  `json:"last_seen_at"`
+}
+
+type NodeLogFile struct {
+	ID        int64  `json:"id"`
+	NodeID    int64  `json:"node_id"`
+	Path      string `json:"path"`
+	CreatedAt int64  `json:"created_at"`
+}
+
 type NodeSysInfo struct {
 	ID              int64           `json:"id"`
 	NodeID          int64           `json:"node_id"`
@@ -64,18 +265,54 @@ type NodeSysInfo struct {
 	UpdatedAt       int64           `json:"updated_at"`
 }
 
+type NodeSysInfoHistory struct {
+	ID              int64           `json:"id"`
+	NodeID          int64           `json:"node_id"`
+	Os              sql.NullString  `json:"os"`
+	Platform        sql.NullString  `json:"platform"`
+	PlatformVersion sql.NullString  `json:"platform_version"`
+	KernelVersion   sql.NullString  `json:"kernel_version"`
+	Cpus            sql.NullInt64   `json:"cpus"`
+	TotalMemory     sql.NullFloat64 `json:"total_memory"`
+	ChangedAt       int64           `json:"changed_at"`
+}
+
+type NotificationChannel struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Config    string `json:"config"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
 type Project struct {
-	ID             string         `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	NodeID         int64          `json:"node_id"`
-	RepoUrl        sql.NullString `json:"repo_url"`
-	Branch         sql.NullString `json:"branch"`
-	DeployPath     string         `json:"deploy_path"`
-	Status         sql.NullString `json:"status"`
-	LastDeployedAt sql.NullInt64  `json:"last_deployed_at"`
-	CreatedAt      int64          `json:"created_at"`
-	UpdatedAt      int64          `json:"updated_at"`
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	NodeID              int64          `json:"node_id"`
+	RepoUrl             sql.NullString `json:"repo_url"`
+	Branch              sql.NullString `json:"branch"`
+	DeployPath          string         `json:"deploy_path"`
+	Status              sql.NullString `json:"status"`
+	LastDeployedAt      sql.NullInt64  `json:"last_deployed_at"`
+	CreatedAt           int64          `json:"created_at"`
+	UpdatedAt           int64          `json:"updated_at"`
+	BuildSteps          sql.NullString `json:"build_steps"`
+	KeepReleases        int64          `json:"keep_releases"`
+	CurrentDeploymentID sql.NullInt64  `json:"current_deployment_id"`
+	AutoDeploy          int64          `json:"auto_deploy"`
+	WebhookSecret       sql.NullString `json:"webhook_secret"`
+	Source              string         `json:"source"`
+	Tech                sql.NullString `json:"tech"`
+	Commands            sql.NullString `json:"commands"`
+	LogPaths            sql.NullString `json:"log_paths"`
+	BackupConfig        sql.NullString `json:"backup_config"`
+	ConfigPath          sql.NullString `json:"config_path"`
+	ConfigSeenAt        sql.NullInt64  `json:"config_seen_at"`
+	ConfigMissing       int64          `json:"config_missing"`
+	BackupIntervalHours int64          `json:"backup_interval_hours"`
+	BackupRetention     int64          `json:"backup_retention"`
 }
 
 type SystemStat struct {
@@ -95,3 +332,15 @@ type User struct {
 	UpdatedAt    sql.NullInt64  `json:"updated_at"`
 	GithubToken  sql.NullString `json:"github_token"`
 }
+
+type WebhookDelivery struct {
+	ID         int64          `json:"id"`
+	ChannelID  int64          `json:"channel_id"`
+	Attempt    int64          `json:"attempt"`
+	Url        string         `json:"url"`
+	StatusCode sql.NullInt64  `json:"status_code"`
+	Error      sql.NullString `json:"error"`
+	Response   sql.NullString `json:"response"`
+	DurationMs int64          `json:"duration_ms"`
+	CreatedAt  int64          `json:"created_at"`
+}
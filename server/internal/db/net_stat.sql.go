@@ -9,6 +9,48 @@ import (
 	"context"
 )
 
+const getNetStatWindow = `-- name: GetNetStatWindow :many
+select timestamp, sent, recv from net_stat ns
+where node_id = ?
+and timestamp >= ? and timestamp < ?
+order by timestamp
+`
+
+type GetNetStatWindowParams struct {
+	NodeID      int64 `json:"node_id"`
+	Timestamp   int64 `json:"timestamp"`
+	Timestamp_2 int64 `json:"timestamp_2"`
+}
+
+type GetNetStatWindowRow struct {
+	Timestamp int64 `json:"timestamp"`
+	Sent      int64 `json:"sent"`
+	Recv      int64 `json:"recv"`
+}
+
+func (q *Queries) GetNetStatWindow(ctx context.Context, arg GetNetStatWindowParams) ([]GetNetStatWindowRow, error) {
+	rows, err := q.query(ctx, q.getNetStatWindowStmt, getNetStatWindow, arg.NodeID, arg.Timestamp, arg.Timestamp_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNetStatWindowRow
+	for rows.Next() {
+		var i GetNetStatWindowRow
+		if err := rows.Scan(&i.Timestamp, &i.Sent, &i.Recv); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNetStats = `-- name: GetNetStats :many
 select timestamp, sent, recv from net_stat ns
 where node_id = ?
@@ -110,26 +110,66 @@ func (q *Queries) AddNodeSysInfo(ctx context.Context, arg AddNodeSysInfoParams)
 	return i, err
 }
 
+const addNodeSysInfoHistory = `-- name: AddNodeSysInfoHistory :exec
+INSERT INTO node_sys_info_history (
+    node_id,
+    os,
+    platform,
+    platform_version,
+    kernel_version,
+    cpus,
+    total_memory
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type AddNodeSysInfoHistoryParams struct {
+	NodeID          int64           `json:"node_id"`
+	Os              sql.NullString  `json:"os"`
+	Platform        sql.NullString  `json:"platform"`
+	PlatformVersion sql.NullString  `json:"platform_version"`
+	KernelVersion   sql.NullString  `json:"kernel_version"`
+	Cpus            sql.NullInt64   `json:"cpus"`
+	TotalMemory     sql.NullFloat64 `json:"total_memory"`
+}
+
+func (q *Queries) AddNodeSysInfoHistory(ctx context.Context, arg AddNodeSysInfoHistoryParams) error {
+	_, err := q.exec(ctx, q.addNodeSysInfoHistoryStmt, addNodeSysInfoHistory,
+		arg.NodeID,
+		arg.Os,
+		arg.Platform,
+		arg.PlatformVersion,
+		arg.KernelVersion,
+		arg.Cpus,
+		arg.TotalMemory,
+	)
+	return err
+}
+
 const createNode = `-- name: CreateNode :one
-INSERT INTO nodes (name, ip)
-VALUES (?, ?)
-RETURNING id, name, ip, created_at, updated_at
+INSERT INTO nodes (name, ip, machine_id)
+VALUES (?, ?, ?)
+RETURNING id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
 `
 
 type CreateNodeParams struct {
-	Name sql.NullString `json:"name"`
-	Ip   string         `json:"ip"`
+	Name      sql.NullString `json:"name"`
+	Ip        string         `json:"ip"`
+	MachineID sql.NullString `json:"machine_id"`
 }
 
 func (q *Queries) CreateNode(ctx context.Context, arg CreateNodeParams) (Node, error) {
-	row := q.queryRow(ctx, q.createNodeStmt, createNode, arg.Name, arg.Ip)
+	row := q.queryRow(ctx, q.createNodeStmt, createNode, arg.Name, arg.Ip, arg.MachineID)
 	var i Node
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Ip,
+		&i.MachineID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastSeenAt,
+		&i.Status,
 	)
 	return i, err
 }
@@ -147,8 +187,24 @@ func (q *Queries) DeleteNode(ctx context.Context, id int64) (int64, error) {
 	return result.RowsAffected()
 }
 
+const deleteStaleNodeDiskInfo = `-- name: DeleteStaleNodeDiskInfo :exec
+DELETE FROM node_disk_info
+WHERE node_id = ?
+  AND updated_at < ?
+`
+
+type DeleteStaleNodeDiskInfoParams struct {
+	NodeID    int64 `json:"node_id"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+func (q *Queries) DeleteStaleNodeDiskInfo(ctx context.Context, arg DeleteStaleNodeDiskInfoParams) error {
+	_, err := q.exec(ctx, q.deleteStaleNodeDiskInfoStmt, deleteStaleNodeDiskInfo, arg.NodeID, arg.UpdatedAt)
+	return err
+}
+
 const getNode = `-- name: GetNode :one
-SELECT id, name, ip, created_at, updated_at
+SELECT id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
 FROM nodes
 WHERE id = ?
 `
@@ -160,14 +216,17 @@ func (q *Queries) GetNode(ctx context.Context, id int64) (Node, error) {
 		&i.ID,
 		&i.Name,
 		&i.Ip,
+		&i.MachineID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastSeenAt,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getNodeByIP = `-- name: GetNodeByIP :one
-SELECT id, name, ip, created_at, updated_at
+SELECT id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
 FROM nodes
 WHERE ip = ?
 `
@@ -179,8 +238,33 @@ func (q *Queries) GetNodeByIP(ctx context.Context, ip string) (Node, error) {
 		&i.ID,
 		&i.Name,
 		&i.Ip,
+		&i.MachineID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastSeenAt,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getNodeByMachineID = `-- name: GetNodeByMachineID :one
+SELECT id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
+FROM nodes
+WHERE machine_id = ?
+`
+
+func (q *Queries) GetNodeByMachineID(ctx context.Context, machineID sql.NullString) (Node, error) {
+	row := q.queryRow(ctx, q.getNodeByMachineIDStmt, getNodeByMachineID, machineID)
+	var i Node
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Ip,
+		&i.MachineID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastSeenAt,
+		&i.Status,
 	)
 	return i, err
 }
@@ -224,6 +308,42 @@ func (q *Queries) GetNodeDiskInfoByNodeID(ctx context.Context, nodeID int64) ([]
 	return items, nil
 }
 
+const getNodeIPHistory = `-- name: GetNodeIPHistory :many
+SELECT id, node_id, ip, first_seen_at, last_seen_at
+FROM node_ip_history
+WHERE node_id = ?
+ORDER BY last_seen_at DESC
+`
+
+func (q *Queries) GetNodeIPHistory(ctx context.Context, nodeID int64) ([]NodeIpHistory, error) {
+	rows, err := q.query(ctx, q.getNodeIPHistoryStmt, getNodeIPHistory, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NodeIpHistory
+	for rows.Next() {
+		var i NodeIpHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.NodeID,
+			&i.Ip,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNodeSysInfoByNodeID = `-- name: GetNodeSysInfoByNodeID :one
 SELECT id, node_id, os, platform, platform_version, kernel_version, cpus, total_memory, created_at, updated_at
 FROM node_sys_info
@@ -248,10 +368,53 @@ func (q *Queries) GetNodeSysInfoByNodeID(ctx context.Context, nodeID int64) (Nod
 	return i, err
 }
 
+const getNodeSysInfoHistory = `-- name: GetNodeSysInfoHistory :many
+SELECT id, node_id, os, platform, platform_version, kernel_version, cpus, total_memory, changed_at
+FROM node_sys_info_history
+WHERE node_id = ?
+ORDER BY changed_at DESC, id DESC
+`
+
+func (q *Queries) GetNodeSysInfoHistory(ctx context.Context, nodeID int64) ([]NodeSysInfoHistory, error) {
+	rows, err := q.query(ctx, q.getNodeSysInfoHistoryStmt, getNodeSysInfoHistory, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NodeSysInfoHistory
+	for rows.Next() {
+		var i NodeSysInfoHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.NodeID,
+			&i.Os,
+			&i.Platform,
+			&i.PlatformVersion,
+			&i.KernelVersion,
+			&i.Cpus,
+			&i.TotalMemory,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNodeWithSysInfo = `-- name: GetNodeWithSysInfo :one
 SELECT n.id,
   n.name,
   n.ip,
+  n.machine_id,
+  n.status,
+  n.last_seen_at,
   nsi.os,
   nsi.platform,
   nsi.platform_version,
@@ -267,6 +430,9 @@ type GetNodeWithSysInfoRow struct {
 	ID              int64           `json:"id"`
 	Name            sql.NullString  `json:"name"`
 	Ip              string          `json:"ip"`
+	MachineID       sql.NullString  `json:"machine_id"`
+	Status          string          `json:"status"`
+	LastSeenAt      sql.NullInt64   `json:"last_seen_at"`
 	Os              sql.NullString  `json:"os"`
 	Platform        sql.NullString  `json:"platform"`
 	PlatformVersion sql.NullString  `json:"platform_version"`
@@ -282,6 +448,9 @@ func (q *Queries) GetNodeWithSysInfo(ctx context.Context, id int64) (GetNodeWith
 		&i.ID,
 		&i.Name,
 		&i.Ip,
+		&i.MachineID,
+		&i.Status,
+		&i.LastSeenAt,
 		&i.Os,
 		&i.Platform,
 		&i.PlatformVersion,
@@ -293,7 +462,7 @@ func (q *Queries) GetNodeWithSysInfo(ctx context.Context, id int64) (GetNodeWith
 }
 
 const getNodes = `-- name: GetNodes :many
-SELECT id, name, ip, created_at, updated_at
+SELECT id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
 FROM nodes
 LIMIT ? OFFSET ?
 `
@@ -316,8 +485,11 @@ func (q *Queries) GetNodes(ctx context.Context, arg GetNodesParams) ([]Node, err
 			&i.ID,
 			&i.Name,
 			&i.Ip,
+			&i.MachineID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LastSeenAt,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -336,6 +508,9 @@ const getNodesWithSysInfo = `-- name: GetNodesWithSysInfo :many
 SELECT n.id,
   n.name,
   n.ip,
+  n.machine_id,
+  n.status,
+  n.last_seen_at,
   nsi.os,
   nsi.platform,
   nsi.platform_version,
@@ -368,6 +543,9 @@ type GetNodesWithSysInfoRow struct {
 	ID              int64           `json:"id"`
 	Name            sql.NullString  `json:"name"`
 	Ip              string          `json:"ip"`
+	MachineID       sql.NullString  `json:"machine_id"`
+	Status          string          `json:"status"`
+	LastSeenAt      sql.NullInt64   `json:"last_seen_at"`
 	Os              sql.NullString  `json:"os"`
 	Platform        sql.NullString  `json:"platform"`
 	PlatformVersion sql.NullString  `json:"platform_version"`
@@ -398,6 +576,9 @@ func (q *Queries) GetNodesWithSysInfo(ctx context.Context, arg GetNodesWithSysIn
 			&i.ID,
 			&i.Name,
 			&i.Ip,
+			&i.MachineID,
+			&i.Status,
+			&i.LastSeenAt,
 			&i.Os,
 			&i.Platform,
 			&i.PlatformVersion,
@@ -418,13 +599,118 @@ func (q *Queries) GetNodesWithSysInfo(ctx context.Context, arg GetNodesWithSysIn
 	return items, nil
 }
 
+const listNodes = `-- name: ListNodes :many
+SELECT id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
+FROM nodes
+`
+
+func (q *Queries) ListNodes(ctx context.Context) ([]Node, error) {
+	rows, err := q.query(ctx, q.listNodesStmt, listNodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Node
+	for rows.Next() {
+		var i Node
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Ip,
+			&i.MachineID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastSeenAt,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordNodeIP = `-- name: RecordNodeIP :exec
+INSERT INTO node_ip_history (node_id, ip)
+VALUES (?, ?) ON CONFLICT (node_id, ip) DO
+UPDATE
+SET last_seen_at = strftime('%s', 'now')
+`
+
+type RecordNodeIPParams struct {
+	NodeID int64  `json:"node_id"`
+	Ip     string `json:"ip"`
+}
+
+// ######################################################################################
+// ----------------------------------ip history-------------------------------------------
+func (q *Queries) RecordNodeIP(ctx context.Context, arg RecordNodeIPParams) error {
+	_, err := q.exec(ctx, q.recordNodeIPStmt, recordNodeIP, arg.NodeID, arg.Ip)
+	return err
+}
+
+const setNodeMachineID = `-- name: SetNodeMachineID :exec
+UPDATE nodes
+SET machine_id = ?,
+  updated_at = strftime('%s', 'now')
+WHERE id = ?
+`
+
+type SetNodeMachineIDParams struct {
+	MachineID sql.NullString `json:"machine_id"`
+	ID        int64          `json:"id"`
+}
+
+func (q *Queries) SetNodeMachineID(ctx context.Context, arg SetNodeMachineIDParams) error {
+	_, err := q.exec(ctx, q.setNodeMachineIDStmt, setNodeMachineID, arg.MachineID, arg.ID)
+	return err
+}
+
+const setNodeStatus = `-- name: SetNodeStatus :exec
+UPDATE nodes
+SET status = ?
+WHERE id = ?
+`
+
+type SetNodeStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetNodeStatus(ctx context.Context, arg SetNodeStatusParams) error {
+	_, err := q.exec(ctx, q.setNodeStatusStmt, setNodeStatus, arg.Status, arg.ID)
+	return err
+}
+
+const touchNode = `-- name: TouchNode :exec
+UPDATE nodes
+SET last_seen_at = ?
+WHERE id = ?
+`
+
+type TouchNodeParams struct {
+	LastSeenAt sql.NullInt64 `json:"last_seen_at"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) TouchNode(ctx context.Context, arg TouchNodeParams) error {
+	_, err := q.exec(ctx, q.touchNodeStmt, touchNode, arg.LastSeenAt, arg.ID)
+	return err
+}
+
 const updateNode = `-- name: UpdateNode :one
 UPDATE nodes
 SET name = ?,
   ip = ?,
   updated_at = strftime('%s', 'now')
 WHERE id = ?
-RETURNING id, name, ip, created_at, updated_at
+RETURNING id, name, ip, machine_id, created_at, updated_at, last_seen_at, status
 `
 
 type UpdateNodeParams struct {
@@ -440,8 +726,11 @@ func (q *Queries) UpdateNode(ctx context.Context, arg UpdateNodeParams) (Node, e
 		&i.ID,
 		&i.Name,
 		&i.Ip,
+		&i.MachineID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastSeenAt,
+		&i.Status,
 	)
 	return i, err
 }
@@ -449,32 +738,32 @@ func (q *Queries) UpdateNode(ctx context.Context, arg UpdateNodeParams) (Node, e
 const updateNodeDiskInfo = `-- name: UpdateNodeDiskInfo :one
 UPDATE node_disk_info
 SET device = ?,
-  mount_point = ?,
   fstype = ?,
   total = ?,
   used = ?,
   updated_at = strftime('%s', 'now')
 WHERE node_id = ?
+  AND mount_point = ?
 RETURNING id, node_id, device, mount_point, fstype, total, used, created_at, updated_at
 `
 
 type UpdateNodeDiskInfoParams struct {
 	Device     sql.NullString  `json:"device"`
-	MountPoint sql.NullString  `json:"mount_point"`
 	Fstype     sql.NullString  `json:"fstype"`
 	Total      sql.NullFloat64 `json:"total"`
 	Used       sql.NullFloat64 `json:"used"`
 	NodeID     int64           `json:"node_id"`
+	MountPoint sql.NullString  `json:"mount_point"`
 }
 
 func (q *Queries) UpdateNodeDiskInfo(ctx context.Context, arg UpdateNodeDiskInfoParams) (NodeDiskInfo, error) {
 	row := q.queryRow(ctx, q.updateNodeDiskInfoStmt, updateNodeDiskInfo,
 		arg.Device,
-		arg.MountPoint,
 		arg.Fstype,
 		arg.Total,
 		arg.Used,
 		arg.NodeID,
+		arg.MountPoint,
 	)
 	var i NodeDiskInfo
 	err := row.Scan(
@@ -491,6 +780,23 @@ func (q *Queries) UpdateNodeDiskInfo(ctx context.Context, arg UpdateNodeDiskInfo
 	return i, err
 }
 
+const updateNodeIP = `-- name: UpdateNodeIP :exec
+UPDATE nodes
+SET ip = ?,
+  updated_at = strftime('%s', 'now')
+WHERE id = ?
+`
+
+type UpdateNodeIPParams struct {
+	Ip string `json:"ip"`
+	ID int64  `json:"id"`
+}
+
+func (q *Queries) UpdateNodeIP(ctx context.Context, arg UpdateNodeIPParams) error {
+	_, err := q.exec(ctx, q.updateNodeIPStmt, updateNodeIP, arg.Ip, arg.ID)
+	return err
+}
+
 const updateNodeName = `-- name: UpdateNodeName :exec
 UPDATE nodes
 SET name = ?
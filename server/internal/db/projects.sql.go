@@ -32,20 +32,90 @@ func (q *Queries) CountProjectsByNode(ctx context.Context, nodeID int64) (int64,
 	return count, err
 }
 
+const createDiscoveredProject = `-- name: CreateDiscoveredProject :one
+INSERT INTO projects (name, node_id, repo_url, branch, deploy_path, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, 'discovered', ?, ?, ?, ?, ?, strftime('%s', 'now'))
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
+`
+
+type CreateDiscoveredProjectParams struct {
+	Name          string         `json:"name"`
+	NodeID        int64          `json:"node_id"`
+	RepoUrl       sql.NullString `json:"repo_url"`
+	Branch        sql.NullString `json:"branch"`
+	DeployPath    string         `json:"deploy_path"`
+	WebhookSecret sql.NullString `json:"webhook_secret"`
+	Tech          sql.NullString `json:"tech"`
+	Commands      sql.NullString `json:"commands"`
+	LogPaths      sql.NullString `json:"log_paths"`
+	BackupConfig  sql.NullString `json:"backup_config"`
+	ConfigPath    sql.NullString `json:"config_path"`
+}
+
+func (q *Queries) CreateDiscoveredProject(ctx context.Context, arg CreateDiscoveredProjectParams) (Project, error) {
+	row := q.queryRow(ctx, q.createDiscoveredProjectStmt, createDiscoveredProject,
+		arg.Name,
+		arg.NodeID,
+		arg.RepoUrl,
+		arg.Branch,
+		arg.DeployPath,
+		arg.WebhookSecret,
+		arg.Tech,
+		arg.Commands,
+		arg.LogPaths,
+		arg.BackupConfig,
+		arg.ConfigPath,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.NodeID,
+		&i.RepoUrl,
+		&i.Branch,
+		&i.DeployPath,
+		&i.Status,
+		&i.LastDeployedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
+	)
+	return i, err
+}
+
 const createProject = `-- name: CreateProject :one
-INSERT INTO projects (name, description, node_id, repo_url, branch, deploy_path, status)
-VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at
+INSERT INTO projects (name, description, node_id, repo_url, branch, deploy_path, status, build_steps, keep_releases, auto_deploy, webhook_secret)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
 `
 
 type CreateProjectParams struct {
-	Name        string         `json:"name"`
-	Description sql.NullString `json:"description"`
-	NodeID      int64          `json:"node_id"`
-	RepoUrl     sql.NullString `json:"repo_url"`
-	Branch      sql.NullString `json:"branch"`
-	DeployPath  string         `json:"deploy_path"`
-	Status      sql.NullString `json:"status"`
+	Name          string         `json:"name"`
+	Description   sql.NullString `json:"description"`
+	NodeID        int64          `json:"node_id"`
+	RepoUrl       sql.NullString `json:"repo_url"`
+	Branch        sql.NullString `json:"branch"`
+	DeployPath    string         `json:"deploy_path"`
+	Status        sql.NullString `json:"status"`
+	BuildSteps    sql.NullString `json:"build_steps"`
+	KeepReleases  int64          `json:"keep_releases"`
+	AutoDeploy    int64          `json:"auto_deploy"`
+	WebhookSecret sql.NullString `json:"webhook_secret"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
@@ -57,6 +127,10 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		arg.Branch,
 		arg.DeployPath,
 		arg.Status,
+		arg.BuildSteps,
+		arg.KeepReleases,
+		arg.AutoDeploy,
+		arg.WebhookSecret,
 	)
 	var i Project
 	err := row.Scan(
@@ -71,6 +145,21 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 	)
 	return i, err
 }
@@ -87,8 +176,20 @@ func (q *Queries) DeleteProject(ctx context.Context, id string) (int64, error) {
 	return result.RowsAffected()
 }
 
+const failCloningProjects = `-- name: FailCloningProjects :exec
+UPDATE projects
+SET status = 'error',
+    updated_at = strftime('%s', 'now')
+WHERE status = 'cloning'
+`
+
+func (q *Queries) FailCloningProjects(ctx context.Context) error {
+	_, err := q.exec(ctx, q.failCloningProjectsStmt, failCloningProjects)
+	return err
+}
+
 const getProject = `-- name: GetProject :one
-SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at FROM projects WHERE id = ?
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects WHERE id = ?
 `
 
 func (q *Queries) GetProject(ctx context.Context, id string) (Project, error) {
@@ -106,13 +207,28 @@ func (q *Queries) GetProject(ctx context.Context, id string) (Project, error) {
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 	)
 	return i, err
 }
 
 const getProjectWithNode = `-- name: GetProjectWithNode :one
 SELECT 
-    p.id, p.name, p.description, p.node_id, p.repo_url, p.branch, p.deploy_path, p.status, p.last_deployed_at, p.created_at, p.updated_at,
+    p.id, p.name, p.description, p.node_id, p.repo_url, p.branch, p.deploy_path, p.status, p.last_deployed_at, p.created_at, p.updated_at, p.build_steps, p.keep_releases, p.current_deployment_id, p.auto_deploy, p.webhook_secret, p.source, p.tech, p.commands, p.log_paths, p.backup_config, p.config_path, p.config_seen_at, p.config_missing, p.backup_interval_hours, p.backup_retention,
     n.name as node_name,
     n.ip as node_ip
 FROM projects p
@@ -121,19 +237,34 @@ WHERE p.id = ?
 `
 
 type GetProjectWithNodeRow struct {
-	ID             string         `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	NodeID         int64          `json:"node_id"`
-	RepoUrl        sql.NullString `json:"repo_url"`
-	Branch         sql.NullString `json:"branch"`
-	DeployPath     string         `json:"deploy_path"`
-	Status         sql.NullString `json:"status"`
-	LastDeployedAt sql.NullInt64  `json:"last_deployed_at"`
-	CreatedAt      int64          `json:"created_at"`
-	UpdatedAt      int64          `json:"updated_at"`
-	NodeName       sql.NullString `json:"node_name"`
-	NodeIp         sql.NullString `json:"node_ip"`
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	NodeID              int64          `json:"node_id"`
+	RepoUrl             sql.NullString `json:"repo_url"`
+	Branch              sql.NullString `json:"branch"`
+	DeployPath          string         `json:"deploy_path"`
+	Status              sql.NullString `json:"status"`
+	LastDeployedAt      sql.NullInt64  `json:"last_deployed_at"`
+	CreatedAt           int64          `json:"created_at"`
+	UpdatedAt           int64          `json:"updated_at"`
+	BuildSteps          sql.NullString `json:"build_steps"`
+	KeepReleases        int64          `json:"keep_releases"`
+	CurrentDeploymentID sql.NullInt64  `json:"current_deployment_id"`
+	AutoDeploy          int64          `json:"auto_deploy"`
+	WebhookSecret       sql.NullString `json:"webhook_secret"`
+	Source              string         `json:"source"`
+	Tech                sql.NullString `json:"tech"`
+	Commands            sql.NullString `json:"commands"`
+	LogPaths            sql.NullString `json:"log_paths"`
+	BackupConfig        sql.NullString `json:"backup_config"`
+	ConfigPath          sql.NullString `json:"config_path"`
+	ConfigSeenAt        sql.NullInt64  `json:"config_seen_at"`
+	ConfigMissing       int64          `json:"config_missing"`
+	BackupIntervalHours int64          `json:"backup_interval_hours"`
+	BackupRetention     int64          `json:"backup_retention"`
+	NodeName            sql.NullString `json:"node_name"`
+	NodeIp              sql.NullString `json:"node_ip"`
 }
 
 func (q *Queries) GetProjectWithNode(ctx context.Context, id string) (GetProjectWithNodeRow, error) {
@@ -151,14 +282,84 @@ func (q *Queries) GetProjectWithNode(ctx context.Context, id string) (GetProject
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 		&i.NodeName,
 		&i.NodeIp,
 	)
 	return i, err
 }
 
+const listNodeProjects = `-- name: ListNodeProjects :many
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects
+WHERE node_id = ?
+`
+
+func (q *Queries) ListNodeProjects(ctx context.Context, nodeID int64) ([]Project, error) {
+	rows, err := q.query(ctx, q.listNodeProjectsStmt, listNodeProjects, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.NodeID,
+			&i.RepoUrl,
+			&i.Branch,
+			&i.DeployPath,
+			&i.Status,
+			&i.LastDeployedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjects = `-- name: ListProjects :many
-SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at FROM projects 
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects 
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -189,6 +390,21 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 			&i.LastDeployedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
 		); err != nil {
 			return nil, err
 		}
@@ -204,7 +420,7 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 }
 
 const listProjectsByNode = `-- name: ListProjectsByNode :many
-SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at FROM projects 
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects 
 WHERE node_id = ? 
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
@@ -237,6 +453,76 @@ func (q *Queries) ListProjectsByNode(ctx context.Context, arg ListProjectsByNode
 			&i.LastDeployedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsWithBackupSchedule = `-- name: ListProjectsWithBackupSchedule :many
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects
+WHERE backup_interval_hours > 0 AND backup_config IS NOT NULL
+`
+
+func (q *Queries) ListProjectsWithBackupSchedule(ctx context.Context) ([]Project, error) {
+	rows, err := q.query(ctx, q.listProjectsWithBackupScheduleStmt, listProjectsWithBackupSchedule)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.NodeID,
+			&i.RepoUrl,
+			&i.Branch,
+			&i.DeployPath,
+			&i.Status,
+			&i.LastDeployedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
 		); err != nil {
 			return nil, err
 		}
@@ -253,7 +539,7 @@ func (q *Queries) ListProjectsByNode(ctx context.Context, arg ListProjectsByNode
 
 const listProjectsWithNodes = `-- name: ListProjectsWithNodes :many
 SELECT 
-    p.id, p.name, p.description, p.node_id, p.repo_url, p.branch, p.deploy_path, p.status, p.last_deployed_at, p.created_at, p.updated_at,
+    p.id, p.name, p.description, p.node_id, p.repo_url, p.branch, p.deploy_path, p.status, p.last_deployed_at, p.created_at, p.updated_at, p.build_steps, p.keep_releases, p.current_deployment_id, p.auto_deploy, p.webhook_secret, p.source, p.tech, p.commands, p.log_paths, p.backup_config, p.config_path, p.config_seen_at, p.config_missing, p.backup_interval_hours, p.backup_retention,
     n.name as node_name,
     n.ip as node_ip
 FROM projects p
@@ -268,19 +554,34 @@ type ListProjectsWithNodesParams struct {
 }
 
 type ListProjectsWithNodesRow struct {
-	ID             string         `json:"id"`
-	Name           string         `json:"name"`
-	Description    sql.NullString `json:"description"`
-	NodeID         int64          `json:"node_id"`
-	RepoUrl        sql.NullString `json:"repo_url"`
-	Branch         sql.NullString `json:"branch"`
-	DeployPath     string         `json:"deploy_path"`
-	Status         sql.NullString `json:"status"`
-	LastDeployedAt sql.NullInt64  `json:"last_deployed_at"`
-	CreatedAt      int64          `json:"created_at"`
-	UpdatedAt      int64          `json:"updated_at"`
-	NodeName       sql.NullString `json:"node_name"`
-	NodeIp         sql.NullString `json:"node_ip"`
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	Description         sql.NullString `json:"description"`
+	NodeID              int64          `json:"node_id"`
+	RepoUrl             sql.NullString `json:"repo_url"`
+	Branch              sql.NullString `json:"branch"`
+	DeployPath          string         `json:"deploy_path"`
+	Status              sql.NullString `json:"status"`
+	LastDeployedAt      sql.NullInt64  `json:"last_deployed_at"`
+	CreatedAt           int64          `json:"created_at"`
+	UpdatedAt           int64          `json:"updated_at"`
+	BuildSteps          sql.NullString `json:"build_steps"`
+	KeepReleases        int64          `json:"keep_releases"`
+	CurrentDeploymentID sql.NullInt64  `json:"current_deployment_id"`
+	AutoDeploy          int64          `json:"auto_deploy"`
+	WebhookSecret       sql.NullString `json:"webhook_secret"`
+	Source              string         `json:"source"`
+	Tech                sql.NullString `json:"tech"`
+	Commands            sql.NullString `json:"commands"`
+	LogPaths            sql.NullString `json:"log_paths"`
+	BackupConfig        sql.NullString `json:"backup_config"`
+	ConfigPath          sql.NullString `json:"config_path"`
+	ConfigSeenAt        sql.NullInt64  `json:"config_seen_at"`
+	ConfigMissing       int64          `json:"config_missing"`
+	BackupIntervalHours int64          `json:"backup_interval_hours"`
+	BackupRetention     int64          `json:"backup_retention"`
+	NodeName            sql.NullString `json:"node_name"`
+	NodeIp              sql.NullString `json:"node_ip"`
 }
 
 func (q *Queries) ListProjectsWithNodes(ctx context.Context, arg ListProjectsWithNodesParams) ([]ListProjectsWithNodesRow, error) {
@@ -304,6 +605,21 @@ func (q *Queries) ListProjectsWithNodes(ctx context.Context, arg ListProjectsWit
 			&i.LastDeployedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
 			&i.NodeName,
 			&i.NodeIp,
 		); err != nil {
@@ -320,6 +636,187 @@ func (q *Queries) ListProjectsWithNodes(ctx context.Context, arg ListProjectsWit
 	return items, nil
 }
 
+const listProjectsWithRepo = `-- name: ListProjectsWithRepo :many
+SELECT id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention FROM projects
+WHERE repo_url IS NOT NULL AND repo_url != ''
+`
+
+func (q *Queries) ListProjectsWithRepo(ctx context.Context) ([]Project, error) {
+	rows, err := q.query(ctx, q.listProjectsWithRepoStmt, listProjectsWithRepo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.NodeID,
+			&i.RepoUrl,
+			&i.Branch,
+			&i.DeployPath,
+			&i.Status,
+			&i.LastDeployedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.BuildSteps,
+			&i.KeepReleases,
+			&i.CurrentDeploymentID,
+			&i.AutoDeploy,
+			&i.WebhookSecret,
+			&i.Source,
+			&i.Tech,
+			&i.Commands,
+			&i.LogPaths,
+			&i.BackupConfig,
+			&i.ConfigPath,
+			&i.ConfigSeenAt,
+			&i.ConfigMissing,
+			&i.BackupIntervalHours,
+			&i.BackupRetention,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProjectBackupSchedule = `-- name: SetProjectBackupSchedule :one
+UPDATE projects
+SET backup_interval_hours = ?,
+    backup_retention = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
+`
+
+type SetProjectBackupScheduleParams struct {
+	BackupIntervalHours int64  `json:"backup_interval_hours"`
+	BackupRetention     int64  `json:"backup_retention"`
+	ID                  string `json:"id"`
+}
+
+func (q *Queries) SetProjectBackupSchedule(ctx context.Context, arg SetProjectBackupScheduleParams) (Project, error) {
+	row := q.queryRow(ctx, q.setProjectBackupScheduleStmt, setProjectBackupSchedule, arg.BackupIntervalHours, arg.BackupRetention, arg.ID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.NodeID,
+		&i.RepoUrl,
+		&i.Branch,
+		&i.DeployPath,
+		&i.Status,
+		&i.LastDeployedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
+	)
+	return i, err
+}
+
+const setProjectConfigMissing = `-- name: SetProjectConfigMissing :exec
+UPDATE projects
+SET config_missing = 1,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+`
+
+func (q *Queries) SetProjectConfigMissing(ctx context.Context, id string) error {
+	_, err := q.exec(ctx, q.setProjectConfigMissingStmt, setProjectConfigMissing, id)
+	return err
+}
+
+const setProjectCurrentDeployment = `-- name: SetProjectCurrentDeployment :exec
+UPDATE projects
+SET current_deployment_id = ?,
+    last_deployed_at = strftime('%s', 'now'),
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+`
+
+type SetProjectCurrentDeploymentParams struct {
+	CurrentDeploymentID sql.NullInt64 `json:"current_deployment_id"`
+	ID                  string        `json:"id"`
+}
+
+func (q *Queries) SetProjectCurrentDeployment(ctx context.Context, arg SetProjectCurrentDeploymentParams) error {
+	_, err := q.exec(ctx, q.setProjectCurrentDeploymentStmt, setProjectCurrentDeployment, arg.CurrentDeploymentID, arg.ID)
+	return err
+}
+
+const setProjectWebhookSecret = `-- name: SetProjectWebhookSecret :one
+UPDATE projects
+SET webhook_secret = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
+`
+
+type SetProjectWebhookSecretParams struct {
+	WebhookSecret sql.NullString `json:"webhook_secret"`
+	ID            string         `json:"id"`
+}
+
+func (q *Queries) SetProjectWebhookSecret(ctx context.Context, arg SetProjectWebhookSecretParams) (Project, error) {
+	row := q.queryRow(ctx, q.setProjectWebhookSecretStmt, setProjectWebhookSecret, arg.WebhookSecret, arg.ID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.NodeID,
+		&i.RepoUrl,
+		&i.Branch,
+		&i.DeployPath,
+		&i.Status,
+		&i.LastDeployedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
+	)
+	return i, err
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects 
 SET name = ?,
@@ -328,19 +825,25 @@ SET name = ?,
     branch = ?,
     deploy_path = ?,
     status = ?,
+    build_steps = ?,
+    keep_releases = ?,
+    auto_deploy = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?
-RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
 `
 
 type UpdateProjectParams struct {
-	Name        string         `json:"name"`
-	Description sql.NullString `json:"description"`
-	RepoUrl     sql.NullString `json:"repo_url"`
-	Branch      sql.NullString `json:"branch"`
-	DeployPath  string         `json:"deploy_path"`
-	Status      sql.NullString `json:"status"`
-	ID          string         `json:"id"`
+	Name         string         `json:"name"`
+	Description  sql.NullString `json:"description"`
+	RepoUrl      sql.NullString `json:"repo_url"`
+	Branch       sql.NullString `json:"branch"`
+	DeployPath   string         `json:"deploy_path"`
+	Status       sql.NullString `json:"status"`
+	BuildSteps   sql.NullString `json:"build_steps"`
+	KeepReleases int64          `json:"keep_releases"`
+	AutoDeploy   int64          `json:"auto_deploy"`
+	ID           string         `json:"id"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
@@ -351,6 +854,9 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		arg.Branch,
 		arg.DeployPath,
 		arg.Status,
+		arg.BuildSteps,
+		arg.KeepReleases,
+		arg.AutoDeploy,
 		arg.ID,
 	)
 	var i Project
@@ -366,16 +872,74 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 	)
 	return i, err
 }
 
+const updateProjectConfig = `-- name: UpdateProjectConfig :exec
+UPDATE projects
+SET name = ?,
+    repo_url = ?,
+    branch = ?,
+    tech = ?,
+    commands = ?,
+    log_paths = ?,
+    backup_config = ?,
+    config_path = ?,
+    config_seen_at = strftime('%s', 'now'),
+    config_missing = 0,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+`
+
+type UpdateProjectConfigParams struct {
+	Name         string         `json:"name"`
+	RepoUrl      sql.NullString `json:"repo_url"`
+	Branch       sql.NullString `json:"branch"`
+	Tech         sql.NullString `json:"tech"`
+	Commands     sql.NullString `json:"commands"`
+	LogPaths     sql.NullString `json:"log_paths"`
+	BackupConfig sql.NullString `json:"backup_config"`
+	ConfigPath   sql.NullString `json:"config_path"`
+	ID           string         `json:"id"`
+}
+
+func (q *Queries) UpdateProjectConfig(ctx context.Context, arg UpdateProjectConfigParams) error {
+	_, err := q.exec(ctx, q.updateProjectConfigStmt, updateProjectConfig,
+		arg.Name,
+		arg.RepoUrl,
+		arg.Branch,
+		arg.Tech,
+		arg.Commands,
+		arg.LogPaths,
+		arg.BackupConfig,
+		arg.ConfigPath,
+		arg.ID,
+	)
+	return err
+}
+
 const updateProjectLastDeployed = `-- name: UpdateProjectLastDeployed :one
 UPDATE projects 
 SET last_deployed_at = strftime('%s', 'now'),
     updated_at = strftime('%s', 'now')
 WHERE id = ?
-RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
 `
 
 func (q *Queries) UpdateProjectLastDeployed(ctx context.Context, id string) (Project, error) {
@@ -393,6 +957,21 @@ func (q *Queries) UpdateProjectLastDeployed(ctx context.Context, id string) (Pro
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 	)
 	return i, err
 }
@@ -402,7 +981,7 @@ UPDATE projects
 SET status = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?
-RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at
+RETURNING id, name, description, node_id, repo_url, branch, deploy_path, status, last_deployed_at, created_at, updated_at, build_steps, keep_releases, current_deployment_id, auto_deploy, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at, config_missing, backup_interval_hours, backup_retention
 `
 
 type UpdateProjectStatusParams struct {
@@ -425,6 +1004,21 @@ func (q *Queries) UpdateProjectStatus(ctx context.Context, arg UpdateProjectStat
 		&i.LastDeployedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.BuildSteps,
+		&i.KeepReleases,
+		&i.CurrentDeploymentID,
+		&i.AutoDeploy,
+		&i.WebhookSecret,
+		&i.Source,
+		&i.Tech,
+		&i.Commands,
+		&i.LogPaths,
+		&i.BackupConfig,
+		&i.ConfigPath,
+		&i.ConfigSeenAt,
+		&i.ConfigMissing,
+		&i.BackupIntervalHours,
+		&i.BackupRetention,
 	)
 	return i, err
 }
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agent_certificates_node_id;
+DROP TABLE IF EXISTS agent_certificates;
+DROP TABLE IF EXISTS certificate_authority;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS certificate_authority (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  cert_pem TEXT NOT NULL,
+  key_pem TEXT NOT NULL,
+  created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS agent_certificates (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  node_id INTEGER NOT NULL,
+  serial TEXT NOT NULL UNIQUE,
+  fingerprint TEXT NOT NULL,
+  not_after INTEGER NOT NULL,
+  revoked_at INTEGER,
+  created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_certificates_node_id ON agent_certificates(node_id);
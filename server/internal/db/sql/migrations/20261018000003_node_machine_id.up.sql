@@ -0,0 +1,32 @@
+-- Rebuild nodes so that machine_id is the node identity and ip is no longer unique.
+-- Existing nodes keep a NULL machine_id until their agent reports one on the next handshake.
+CREATE TABLE IF NOT EXISTS nodes_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT,
+  ip TEXT NOT NULL,
+  machine_id TEXT UNIQUE,
+  created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+);
+
+INSERT INTO nodes_new (id, name, ip, created_at, updated_at)
+SELECT id, name, ip, created_at, updated_at FROM nodes;
+
+DROP TABLE nodes;
+
+ALTER TABLE nodes_new RENAME TO nodes;
+
+CREATE INDEX IF NOT EXISTS idx_nodes_ip ON nodes(ip);
+
+CREATE TABLE IF NOT EXISTS node_ip_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  node_id INTEGER NOT NULL,
+  ip TEXT NOT NULL,
+  first_seen_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  last_seen_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  UNIQUE (node_id, ip),
+  FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE
+);
+
+INSERT INTO node_ip_history (node_id, ip, first_seen_at, last_seen_at)
+SELECT id, ip, created_at, updated_at FROM nodes;
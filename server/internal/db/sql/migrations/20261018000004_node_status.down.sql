@@ -0,0 +1,3 @@
+-- Remove node status tracking columns
+ALTER TABLE nodes DROP COLUMN status;
+ALTER TABLE nodes DROP COLUMN last_seen_at;
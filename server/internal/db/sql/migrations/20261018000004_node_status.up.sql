@@ -0,0 +1,3 @@
+-- Track when each node last reported and its derived connection status
+ALTER TABLE nodes ADD COLUMN last_seen_at INTEGER;
+ALTER TABLE nodes ADD COLUMN status TEXT NOT NULL DEFAULT 'offline';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_disk_stat_node_time;
+DROP INDEX IF EXISTS idx_disk_stat_timestamp;
+DROP TABLE IF EXISTS disk_stat;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS disk_stat (
+    timestamp INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    mountpoint TEXT NOT NULL,
+    total INTEGER NOT NULL,         -- Bytes
+    used INTEGER NOT NULL,          -- Bytes
+    used_percent REAL NOT NULL,
+    PRIMARY KEY (timestamp, node_id, mountpoint)
+);
+
+-- Create index for time-based queries
+CREATE INDEX IF NOT EXISTS idx_disk_stat_timestamp ON disk_stat(timestamp);
+CREATE INDEX IF NOT EXISTS idx_disk_stat_node_time ON disk_stat(node_id, timestamp);
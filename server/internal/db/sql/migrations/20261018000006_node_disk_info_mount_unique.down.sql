@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_node_disk_info_node_mount;
@@ -0,0 +1,9 @@
+-- Keep only the newest row per mount so that the inventory can be keyed on (node_id, mount_point)
+DELETE FROM node_disk_info
+WHERE id NOT IN (
+    SELECT MAX(id)
+    FROM node_disk_info
+    GROUP BY node_id, mount_point
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_node_disk_info_node_mount ON node_disk_info(node_id, mount_point);
@@ -0,0 +1,2 @@
+-- Remove mountpoint column from alerts table
+ALTER TABLE alerts DROP COLUMN mountpoint;
@@ -0,0 +1,2 @@
+-- Mountpoint targeted by disk and disk_fill alerts, NULL or empty means any mount
+ALTER TABLE alerts ADD COLUMN mountpoint TEXT;
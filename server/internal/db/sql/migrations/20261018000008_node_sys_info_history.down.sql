@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_node_sys_info_history_node_id;
+DROP TABLE IF EXISTS node_sys_info_history;
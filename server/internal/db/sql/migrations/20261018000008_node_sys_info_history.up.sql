@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS node_sys_info_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  node_id INTEGER NOT NULL,
+  os TEXT,
+  platform TEXT,
+  platform_version TEXT,
+  kernel_version TEXT,
+  cpus INTEGER,
+  total_memory REAL,
+  changed_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_sys_info_history_node_id ON node_sys_info_history(node_id, changed_at);
+
+-- Seed the history with the sys info known so far
+INSERT INTO node_sys_info_history (
+    node_id,
+    os,
+    platform,
+    platform_version,
+    kernel_version,
+    cpus,
+    total_memory,
+    changed_at
+  )
+SELECT node_id,
+  os,
+  platform,
+  platform_version,
+  kernel_version,
+  cpus,
+  total_memory,
+  updated_at
+FROM node_sys_info;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_command_executions_node_id;
+DROP TABLE IF EXISTS command_executions;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS command_executions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  node_id INTEGER NOT NULL,
+  user_id INTEGER,
+  command TEXT NOT NULL,
+  correlation_id TEXT,
+  status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed', 'timeout')),
+  exit_code INTEGER,
+  output TEXT NOT NULL DEFAULT '',
+  error TEXT,
+  duration_ms INTEGER,
+  started_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+  finished_at INTEGER,
+  FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_command_executions_node_id ON command_executions(node_id, started_at);
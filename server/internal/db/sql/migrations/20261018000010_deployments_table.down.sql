@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_deployments_status;
+DROP INDEX IF EXISTS idx_deployments_project_id;
+DROP TABLE IF EXISTS deployments;
+
+ALTER TABLE projects DROP COLUMN build_steps;
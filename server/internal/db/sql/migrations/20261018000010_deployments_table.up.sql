@@ -0,0 +1,22 @@
+-- Build steps run in deploy_path after the repository is updated, stored as a JSON array of commands
+ALTER TABLE projects ADD COLUMN build_steps TEXT;
+
+CREATE TABLE IF NOT EXISTS deployments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id TEXT NOT NULL,
+    node_id INTEGER NOT NULL,
+    branch TEXT,
+    commit_sha TEXT,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'running', 'succeeded', 'failed')),
+    logs TEXT NOT NULL DEFAULT '',
+    error TEXT,
+    started_at INTEGER,
+    finished_at INTEGER,
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployments_project_id ON deployments(project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_deployments_status ON deployments(status);
@@ -0,0 +1,7 @@
+ALTER TABLE projects DROP COLUMN current_deployment_id;
+ALTER TABLE projects DROP COLUMN keep_releases;
+
+ALTER TABLE deployments DROP COLUMN release_pruned;
+ALTER TABLE deployments DROP COLUMN release_path;
+ALTER TABLE deployments DROP COLUMN rollback_of;
+ALTER TABLE deployments DROP COLUMN triggered_by;
@@ -0,0 +1,9 @@
+-- Each deployment is built in its own release directory under deploy_path and
+-- deploy_path/current points at the active one
+ALTER TABLE deployments ADD COLUMN triggered_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE deployments ADD COLUMN rollback_of INTEGER REFERENCES deployments(id) ON DELETE SET NULL;
+ALTER TABLE deployments ADD COLUMN release_path TEXT;
+ALTER TABLE deployments ADD COLUMN release_pruned INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE projects ADD COLUMN keep_releases INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE projects ADD COLUMN current_deployment_id INTEGER;
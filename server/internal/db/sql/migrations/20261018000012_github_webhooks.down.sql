@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_github_webhook_events_project_id;
+DROP TABLE IF EXISTS github_webhook_events;
+
+ALTER TABLE projects DROP COLUMN webhook_secret;
+ALTER TABLE projects DROP COLUMN auto_deploy;
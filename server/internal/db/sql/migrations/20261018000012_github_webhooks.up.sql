@@ -0,0 +1,25 @@
+-- Pushes to a project's repository and branch trigger a deploy when auto_deploy is on.
+-- Deliveries are signed with the project's webhook_secret.
+ALTER TABLE projects ADD COLUMN auto_deploy INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE projects ADD COLUMN webhook_secret TEXT;
+
+UPDATE projects SET webhook_secret = lower(hex(randomblob(20))) WHERE webhook_secret IS NULL;
+
+CREATE TABLE IF NOT EXISTS github_webhook_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id TEXT,
+    event TEXT NOT NULL,
+    repository TEXT,
+    branch TEXT,
+    commit_sha TEXT,
+    project_id TEXT,
+    deployment_id INTEGER,
+    status TEXT NOT NULL CHECK(status IN ('deployed', 'ignored')),
+    reason TEXT,
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (deployment_id) REFERENCES deployments(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_webhook_events_project_id ON github_webhook_events(project_id, created_at);
@@ -0,0 +1,8 @@
+ALTER TABLE projects DROP COLUMN config_missing;
+ALTER TABLE projects DROP COLUMN config_seen_at;
+ALTER TABLE projects DROP COLUMN config_path;
+ALTER TABLE projects DROP COLUMN backup_config;
+ALTER TABLE projects DROP COLUMN log_paths;
+ALTER TABLE projects DROP COLUMN commands;
+ALTER TABLE projects DROP COLUMN tech;
+ALTER TABLE projects DROP COLUMN source;
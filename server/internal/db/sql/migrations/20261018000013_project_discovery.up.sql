@@ -0,0 +1,11 @@
+-- Projects found by agents from config.vpspilot.json files. The parsed config is
+-- stored as JSON: tech and log_paths are string arrays, commands is an array of
+-- {name, command} and backup_config is the "backups" object.
+ALTER TABLE projects ADD COLUMN source TEXT NOT NULL DEFAULT 'manual' CHECK(source IN ('manual', 'discovered'));
+ALTER TABLE projects ADD COLUMN tech TEXT;
+ALTER TABLE projects ADD COLUMN commands TEXT;
+ALTER TABLE projects ADD COLUMN log_paths TEXT;
+ALTER TABLE projects ADD COLUMN backup_config TEXT;
+ALTER TABLE projects ADD COLUMN config_path TEXT;
+ALTER TABLE projects ADD COLUMN config_seen_at INTEGER;
+ALTER TABLE projects ADD COLUMN config_missing INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_command_executions_project_id;
+
+ALTER TABLE command_executions DROP COLUMN command_name;
+ALTER TABLE command_executions DROP COLUMN project_id;
@@ -0,0 +1,5 @@
+-- Runs of commands declared in a project's config.vpspilot.json
+ALTER TABLE command_executions ADD COLUMN project_id TEXT REFERENCES projects(id) ON DELETE SET NULL;
+ALTER TABLE command_executions ADD COLUMN command_name TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_command_executions_project_id ON command_executions(project_id, id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_backup_restores_backup_id;
+DROP TABLE IF EXISTS backup_restores;
+DROP INDEX IF EXISTS idx_backups_project_id;
+DROP TABLE IF EXISTS backups;
+
+ALTER TABLE projects DROP COLUMN backup_retention;
+ALTER TABLE projects DROP COLUMN backup_interval_hours;
@@ -0,0 +1,46 @@
+-- Backups are taken every backup_interval_hours (0 disables the schedule) and the
+-- newest backup_retention successful ones are kept
+ALTER TABLE projects ADD COLUMN backup_interval_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE projects ADD COLUMN backup_retention INTEGER NOT NULL DEFAULT 7;
+
+CREATE TABLE IF NOT EXISTS backups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id TEXT NOT NULL,
+    node_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'succeeded', 'failed')),
+    trigger_type TEXT NOT NULL DEFAULT 'manual' CHECK(trigger_type IN ('manual', 'scheduled')),
+    triggered_by INTEGER,
+    file_name TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    sha256 TEXT,
+    contents TEXT, -- JSON array of the entries in the archive
+    error TEXT,
+    started_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+    finished_at INTEGER,
+
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    FOREIGN KEY (triggered_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_backups_project_id ON backups(project_id, id);
+
+CREATE TABLE IF NOT EXISTS backup_restores (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    backup_id INTEGER NOT NULL,
+    project_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'succeeded', 'failed')),
+    restore_database INTEGER NOT NULL DEFAULT 0,
+    triggered_by INTEGER,
+    restored TEXT, -- JSON array of what the agent restored
+    error TEXT,
+    started_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+    finished_at INTEGER,
+
+    FOREIGN KEY (backup_id) REFERENCES backups(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (triggered_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_backup_restores_backup_id ON backup_restores(backup_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_cron_runs_cron_job_id;
+DROP TABLE IF EXISTS cron_runs;
+DROP INDEX IF EXISTS idx_cron_jobs_node_id;
+DROP TABLE IF EXISTS cron_jobs;
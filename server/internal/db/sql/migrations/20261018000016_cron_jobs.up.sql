@@ -0,0 +1,44 @@
+-- Cron jobs are synced to the node's agent, which runs them and reports every run.
+-- last_scheduled_at is the newest schedule time that has a run or was recorded as missed.
+CREATE TABLE IF NOT EXISTS cron_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    node_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    schedule TEXT NOT NULL,
+    command TEXT NOT NULL,
+    working_dir TEXT,
+    run_as_user TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    email TEXT,
+    discord_webhook TEXT,
+    slack_webhook TEXT,
+    last_status TEXT,
+    last_run_at INTEGER,
+    last_scheduled_at INTEGER,
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+    updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cron_jobs_node_id ON cron_jobs(node_id);
+
+CREATE TABLE IF NOT EXISTS cron_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cron_job_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('succeeded', 'failed', 'missed')),
+    exit_code INTEGER,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    output TEXT,
+    error TEXT,
+    scheduled_at INTEGER NOT NULL,
+    started_at INTEGER,
+    finished_at INTEGER,
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (cron_job_id) REFERENCES cron_jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cron_runs_cron_job_id ON cron_runs(cron_job_id, id);
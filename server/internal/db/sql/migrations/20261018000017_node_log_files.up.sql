@@ -0,0 +1,10 @@
+-- Log files that may be tailed on a node besides the ones declared by its projects
+CREATE TABLE IF NOT EXISTS node_log_files (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    node_id INTEGER NOT NULL,
+    path TEXT NOT NULL, -- absolute path or glob, e.g. /var/log/nginx/*.log
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    UNIQUE (node_id, path)
+);
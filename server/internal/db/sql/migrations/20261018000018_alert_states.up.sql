@@ -0,0 +1,14 @@
+-- Incident state of each alert rule. A breach makes a rule pending, a breach that
+-- lasts the rule's duration makes it firing, and the first sample back under the
+-- threshold resolves it. Notifications go out on the firing and resolved transitions.
+CREATE TABLE IF NOT EXISTS alert_states (
+    alert_id INTEGER PRIMARY KEY,
+    state TEXT NOT NULL DEFAULT 'ok' CHECK(state IN ('ok', 'pending', 'firing', 'resolved')),
+    value TEXT, -- observed value at the last transition
+    breached_at INTEGER, -- start of the current or last breach
+    fired_at INTEGER,
+    resolved_at INTEGER,
+    updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+
+    FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE alert_states DROP COLUMN notified_at;
+ALTER TABLE alerts DROP COLUMN resend_interval;
+ALTER TABLE alerts DROP COLUMN aggregation;
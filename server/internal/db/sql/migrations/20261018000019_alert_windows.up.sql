@@ -0,0 +1,10 @@
+-- duration is now the window a breach must be sustained for before the rule fires,
+-- aggregation says how the samples in that window are reduced to one value and
+-- resend_interval (minutes, 0 for none) repeats the notification while still firing.
+ALTER TABLE alerts ADD COLUMN aggregation TEXT NOT NULL DEFAULT 'all' CHECK(aggregation IN ('all', 'avg', 'max', 'p95'));
+ALTER TABLE alerts ADD COLUMN resend_interval INTEGER NOT NULL DEFAULT 0;
+
+-- duration used to be the resend cooldown, keep existing rules reminding at the same pace
+UPDATE alerts SET resend_interval = duration;
+
+ALTER TABLE alert_states ADD COLUMN notified_at INTEGER;
@@ -0,0 +1,17 @@
+-- History of alert rule state transitions. Rows are kept when the rule is deleted.
+CREATE TABLE IF NOT EXISTS alert_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    alert_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    metric TEXT NOT NULL,
+    value TEXT, -- observed value
+    threshold TEXT,
+    from_state TEXT NOT NULL,
+    to_state TEXT NOT NULL, -- a reminder of a firing rule goes from firing to firing
+    breached_at INTEGER,
+    deliveries TEXT, -- JSON array of {channel, status, error}, NULL when nothing was sent
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_node_created ON alert_events(node_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert ON alert_events(alert_id);
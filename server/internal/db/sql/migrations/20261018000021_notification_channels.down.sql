@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alert_channels;
+DROP TABLE IF EXISTS notification_channels;
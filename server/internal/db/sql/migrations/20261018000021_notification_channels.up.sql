@@ -0,0 +1,22 @@
+-- Reusable notification channels. config is a JSON object whose shape depends on the
+-- type, so new channel types need no schema change.
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    config TEXT NOT NULL DEFAULT '{}',
+    created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+    updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+);
+
+-- Channels notified by each alert rule, on top of the rule's own email and webhook columns
+CREATE TABLE IF NOT EXISTS alert_channels (
+    alert_id INTEGER NOT NULL,
+    channel_id INTEGER NOT NULL,
+
+    PRIMARY KEY (alert_id, channel_id),
+    FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE,
+    FOREIGN KEY (channel_id) REFERENCES notification_channels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_channels_channel ON alert_channels(channel_id);
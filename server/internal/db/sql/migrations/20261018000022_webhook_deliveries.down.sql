@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_channel;
+DROP TABLE IF EXISTS webhook_deliveries;
@@ -0,0 +1,26 @@
+ALTER TABLE alerts ADD COLUMN email TEXT;
+ALTER TABLE alerts ADD COLUMN discord_webhook TEXT;
+ALTER TABLE alerts ADD COLUMN slack_webhook TEXT;
+
+ALTER TABLE cron_jobs ADD COLUMN email TEXT;
+ALTER TABLE cron_jobs ADD COLUMN discord_webhook TEXT;
+ALTER TABLE cron_jobs ADD COLUMN slack_webhook TEXT;
+
+-- Restore the first linked channel of each kind, the channels themselves are kept
+UPDATE alerts SET
+    email = (SELECT json_extract(c.config, '$.to') FROM alert_channels ac JOIN notification_channels c ON c.id = ac.channel_id
+             WHERE ac.alert_id = alerts.id AND c.type = 'email' ORDER BY c.id LIMIT 1),
+    discord_webhook = (SELECT json_extract(c.config, '$.webhook_url') FROM alert_channels ac JOIN notification_channels c ON c.id = ac.channel_id
+                       WHERE ac.alert_id = alerts.id AND c.type = 'discord' ORDER BY c.id LIMIT 1),
+    slack_webhook = (SELECT json_extract(c.config, '$.webhook_url') FROM alert_channels ac JOIN notification_channels c ON c.id = ac.channel_id
+                     WHERE ac.alert_id = alerts.id AND c.type = 'slack' ORDER BY c.id LIMIT 1);
+
+UPDATE cron_jobs SET
+    email = (SELECT json_extract(c.config, '$.to') FROM cron_job_channels jc JOIN notification_channels c ON c.id = jc.channel_id
+             WHERE jc.cron_job_id = cron_jobs.id AND c.type = 'email' ORDER BY c.id LIMIT 1),
+    discord_webhook = (SELECT json_extract(c.config, '$.webhook_url') FROM cron_job_channels jc JOIN notification_channels c ON c.id = jc.channel_id
+                       WHERE jc.cron_job_id = cron_jobs.id AND c.type = 'discord' ORDER BY c.id LIMIT 1),
+    slack_webhook = (SELECT json_extract(c.config, '$.webhook_url') FROM cron_job_channels jc JOIN notification_channels c ON c.id = jc.channel_id
+                     WHERE jc.cron_job_id = cron_jobs.id AND c.type = 'slack' ORDER BY c.id LIMIT 1);
+
+DROP TABLE IF EXISTS cron_job_channels;
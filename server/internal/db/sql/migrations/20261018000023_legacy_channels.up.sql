@@ -0,0 +1,56 @@
+-- Channels notified by each cron job, like alert_channels for alert rules
+CREATE TABLE IF NOT EXISTS cron_job_channels (
+    cron_job_id INTEGER NOT NULL,
+    channel_id INTEGER NOT NULL,
+
+    PRIMARY KEY (cron_job_id, channel_id),
+    FOREIGN KEY (cron_job_id) REFERENCES cron_jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (channel_id) REFERENCES notification_channels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cron_job_channels_channel ON cron_job_channels(channel_id);
+
+-- The email and webhook columns of alerts and cron jobs become channels. Each distinct
+-- target gets one channel, unless a channel with the same config already exists.
+INSERT INTO notification_channels (name, type, config)
+SELECT 'Migrated ' || type || ' ' || ROW_NUMBER() OVER (PARTITION BY type ORDER BY config), type, config
+FROM (
+    SELECT 'email' AS type, json_object('to', target) AS config
+    FROM (SELECT email AS target FROM alerts UNION SELECT email FROM cron_jobs)
+    WHERE target != ''
+    UNION
+    SELECT 'discord', json_object('webhook_url', target)
+    FROM (SELECT discord_webhook AS target FROM alerts UNION SELECT discord_webhook FROM cron_jobs)
+    WHERE target != ''
+    UNION
+    SELECT 'slack', json_object('webhook_url', target)
+    FROM (SELECT slack_webhook AS target FROM alerts UNION SELECT slack_webhook FROM cron_jobs)
+    WHERE target != ''
+) targets
+WHERE NOT EXISTS (
+    SELECT 1 FROM notification_channels c WHERE c.type = targets.type AND c.config = targets.config
+);
+
+INSERT OR IGNORE INTO alert_channels (alert_id, channel_id)
+SELECT a.id, c.id
+FROM alerts a
+JOIN notification_channels c
+  ON (c.type = 'email' AND c.config = json_object('to', a.email))
+  OR (c.type = 'discord' AND c.config = json_object('webhook_url', a.discord_webhook))
+  OR (c.type = 'slack' AND c.config = json_object('webhook_url', a.slack_webhook));
+
+INSERT OR IGNORE INTO cron_job_channels (cron_job_id, channel_id)
+SELECT j.id, c.id
+FROM cron_jobs j
+JOIN notification_channels c
+  ON (c.type = 'email' AND c.config = json_object('to', j.email))
+  OR (c.type = 'discord' AND c.config = json_object('webhook_url', j.discord_webhook))
+  OR (c.type = 'slack' AND c.config = json_object('webhook_url', j.slack_webhook));
+
+ALTER TABLE alerts DROP COLUMN email;
+ALTER TABLE alerts DROP COLUMN discord_webhook;
+ALTER TABLE alerts DROP COLUMN slack_webhook;
+
+ALTER TABLE cron_jobs DROP COLUMN email;
+ALTER TABLE cron_jobs DROP COLUMN discord_webhook;
+ALTER TABLE cron_jobs DROP COLUMN slack_webhook;
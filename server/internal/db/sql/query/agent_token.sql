@@ -0,0 +1,28 @@
+-- name: CreateAgentToken :one
+INSERT INTO agent_tokens (name, token_hash, node_id, expires_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetAgentTokenByHash :one
+SELECT * FROM agent_tokens WHERE token_hash = ?;
+
+-- name: GetAgentToken :one
+SELECT * FROM agent_tokens WHERE id = ?;
+
+-- name: ListAgentTokens :many
+SELECT * FROM agent_tokens
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: RevokeAgentToken :execrows
+UPDATE agent_tokens
+SET revoked_at = strftime('%s', 'now'),
+  updated_at = strftime('%s', 'now')
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: BindAgentToken :exec
+UPDATE agent_tokens
+SET node_id = ?,
+  last_used_at = strftime('%s', 'now'),
+  updated_at = strftime('%s', 'now')
+WHERE id = ?;
@@ -0,0 +1,19 @@
+-- name: CreateAlertEvent :one
+INSERT INTO alert_events (
+    alert_id,
+    node_id,
+    metric,
+    value,
+    threshold,
+    from_state,
+    to_state,
+    breached_at,
+    created_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: SetAlertEventDeliveries :exec
+UPDATE alert_events
+SET deliveries = ?
+WHERE id = ?;
@@ -6,10 +6,10 @@ INSERT INTO alerts(
     threshold,
     net_rece_threshold,
     net_send_threshold,
-    email,
-    discord_webhook,
-    slack_webhook,
-    is_active
+    is_active,
+    mountpoint,
+    aggregation,
+    resend_interval
   )
 values (
     ?,
@@ -43,10 +43,10 @@ SET node_id = ?,
   threshold = ?,
   net_rece_threshold = ?,
   net_send_threshold = ?,
-  email = ?,
-  discord_webhook = ?,
-  slack_webhook = ?,
-  is_active = ?
+  is_active = ?,
+  mountpoint = ?,
+  aggregation = ?,
+  resend_interval = ?
 WHERE id = ?
 RETURNING *;
 
@@ -68,3 +68,23 @@ WHERE id = ?;
 SELECT a.*,n.name as node_name,n.ip as node_ip FROM alerts a
 join nodes n on a.node_id = n.id
 WHERE node_id = ? AND metric = ? AND is_active = 1;
+
+-- name: GetAlertState :one
+SELECT * FROM alert_states
+WHERE alert_id = ?;
+
+-- name: SaveAlertState :exec
+INSERT INTO alert_states (alert_id, state, value, breached_at, fired_at, resolved_at, notified_at)
+VALUES (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (alert_id) DO
+UPDATE
+SET state = excluded.state,
+  value = excluded.value,
+  breached_at = excluded.breached_at,
+  fired_at = excluded.fired_at,
+  resolved_at = excluded.resolved_at,
+  notified_at = excluded.notified_at,
+  updated_at = strftime('%s', 'now');
+
+-- name: DeleteAlertState :exec
+DELETE FROM alert_states
+WHERE alert_id = ?;
@@ -0,0 +1,70 @@
+-- name: CreateBackup :one
+INSERT INTO backups (project_id, node_id, trigger_type, triggered_by, file_name, file_path)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: FinishBackup :one
+UPDATE backups
+SET status = ?,
+    size_bytes = ?,
+    sha256 = ?,
+    contents = ?,
+    error = ?,
+    finished_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: GetBackup :one
+SELECT * FROM backups WHERE id = ?;
+
+-- name: GetLatestBackup :one
+SELECT * FROM backups
+WHERE project_id = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListBackupsByProject :many
+SELECT * FROM backups
+WHERE project_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListSucceededBackups :many
+SELECT * FROM backups
+WHERE project_id = ? AND status = 'succeeded'
+ORDER BY id DESC;
+
+-- name: DeleteBackup :exec
+DELETE FROM backups WHERE id = ?;
+
+-- name: FailUnfinishedBackups :exec
+UPDATE backups
+SET status = 'failed',
+    error = 'server restarted before the backup finished',
+    finished_at = strftime('%s', 'now')
+WHERE status = 'running';
+
+-- name: CreateBackupRestore :one
+INSERT INTO backup_restores (backup_id, project_id, restore_database, triggered_by)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: FinishBackupRestore :exec
+UPDATE backup_restores
+SET status = ?,
+    restored = ?,
+    error = ?,
+    finished_at = strftime('%s', 'now')
+WHERE id = ?;
+
+-- name: ListBackupRestores :many
+SELECT * FROM backup_restores
+WHERE backup_id = ?
+ORDER BY id DESC;
+
+-- name: FailUnfinishedBackupRestores :exec
+UPDATE backup_restores
+SET status = 'failed',
+    error = 'server restarted before the restore finished',
+    finished_at = strftime('%s', 'now')
+WHERE status = 'running';
@@ -0,0 +1,27 @@
+-- name: GetCertificateAuthority :one
+SELECT * FROM certificate_authority
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateCertificateAuthority :one
+INSERT INTO certificate_authority (cert_pem, key_pem)
+VALUES (?, ?)
+RETURNING *;
+
+-- name: CreateAgentCertificate :one
+INSERT INTO agent_certificates (node_id, serial, fingerprint, not_after)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetAgentCertificateBySerial :one
+SELECT * FROM agent_certificates WHERE serial = ?;
+
+-- name: ListAgentCertificatesByNode :many
+SELECT * FROM agent_certificates
+WHERE node_id = ?
+ORDER BY id DESC;
+
+-- name: RevokeAgentCertificate :execrows
+UPDATE agent_certificates
+SET revoked_at = strftime('%s', 'now')
+WHERE id = ? AND revoked_at IS NULL;
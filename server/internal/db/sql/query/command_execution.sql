@@ -0,0 +1,39 @@
+-- name: CreateCommandExecution :one
+INSERT INTO command_executions (node_id, user_id, command, project_id, command_name)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+-- name: SetCommandExecutionCorrelationID :exec
+UPDATE command_executions
+SET correlation_id = ?
+WHERE id = ?;
+-- name: FinishCommandExecution :exec
+UPDATE command_executions
+SET status = ?,
+  exit_code = ?,
+  output = ?,
+  error = ?,
+  duration_ms = ?,
+  finished_at = strftime('%s', 'now')
+WHERE id = ?;
+-- name: GetCommandExecution :one
+SELECT *
+FROM command_executions
+WHERE id = ?;
+-- name: ListCommandExecutionsByNode :many
+SELECT *
+FROM command_executions
+WHERE node_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+-- name: ListCommandExecutionsByProject :many
+SELECT *
+FROM command_executions
+WHERE project_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+-- name: FailRunningCommandExecutions :exec
+UPDATE command_executions
+SET status = 'failed',
+  error = 'server restarted before the command finished',
+  finished_at = strftime('%s', 'now')
+WHERE status = 'running';
@@ -0,0 +1,62 @@
+-- name: CreateCronJob :one
+INSERT INTO cron_jobs (node_id, name, schedule, command, working_dir, run_as_user, enabled, last_scheduled_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, strftime('%s', 'now'))
+RETURNING *;
+
+-- name: UpdateCronJob :one
+UPDATE cron_jobs
+SET name = ?,
+    schedule = ?,
+    command = ?,
+    working_dir = ?,
+    run_as_user = ?,
+    enabled = ?,
+    last_scheduled_at = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: GetCronJob :one
+SELECT * FROM cron_jobs WHERE id = ?;
+
+-- name: ListCronJobs :many
+SELECT * FROM cron_jobs
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListCronJobsByNode :many
+SELECT * FROM cron_jobs
+WHERE node_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListEnabledCronJobsByNode :many
+SELECT * FROM cron_jobs
+WHERE node_id = ? AND enabled = 1
+ORDER BY id;
+
+-- name: ListEnabledCronJobs :many
+SELECT * FROM cron_jobs
+WHERE enabled = 1
+ORDER BY id;
+
+-- name: DeleteCronJob :execrows
+DELETE FROM cron_jobs WHERE id = ?;
+
+-- name: SetCronJobLastRun :exec
+UPDATE cron_jobs
+SET last_status = ?,
+    last_run_at = ?,
+    last_scheduled_at = ?
+WHERE id = ?;
+
+-- name: CreateCronRun :one
+INSERT INTO cron_runs (cron_job_id, node_id, status, exit_code, duration_ms, output, error, scheduled_at, started_at, finished_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListCronRuns :many
+SELECT * FROM cron_runs
+WHERE cron_job_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
@@ -0,0 +1,67 @@
+-- name: CreateDeployment :one
+INSERT INTO deployments (project_id, node_id, branch, commit_sha, triggered_by, rollback_of)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: StartDeployment :exec
+UPDATE deployments
+SET status = 'running',
+    started_at = strftime('%s', 'now')
+WHERE id = ?;
+
+-- name: UpdateDeploymentLogs :exec
+UPDATE deployments
+SET logs = ?,
+    commit_sha = ?
+WHERE id = ?;
+
+-- name: FinishDeployment :one
+UPDATE deployments
+SET status = ?,
+    commit_sha = ?,
+    logs = ?,
+    error = ?,
+    finished_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: SetDeploymentRelease :exec
+UPDATE deployments
+SET release_path = ?
+WHERE id = ?;
+
+-- name: GetDeployment :one
+SELECT * FROM deployments WHERE id = ?;
+
+-- name: ListDeploymentsByProject :many
+SELECT * FROM deployments
+WHERE project_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountActiveDeploymentsByProject :one
+SELECT COUNT(*) FROM deployments
+WHERE project_id = ? AND status IN ('pending', 'running');
+
+-- name: FailUnfinishedDeployments :exec
+UPDATE deployments
+SET status = 'failed',
+    error = 'server restarted before the deployment finished',
+    finished_at = strftime('%s', 'now')
+WHERE status IN ('pending', 'running');
+
+-- name: ListReleasedDeployments :many
+SELECT * FROM deployments
+WHERE project_id = ? AND status = 'succeeded' AND release_path IS NOT NULL AND release_pruned = 0
+ORDER BY id DESC;
+
+-- name: ListSucceededDeployments :many
+SELECT * FROM deployments
+WHERE project_id = ? AND status = 'succeeded' AND commit_sha IS NOT NULL
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: MarkReleasePruned :exec
+UPDATE deployments
+SET release_pruned = 1
+WHERE project_id = ? AND release_path = ?;
@@ -0,0 +1,21 @@
+-- name: InsertDiskStat :exec
+INSERT INTO disk_stat (timestamp, node_id, mountpoint, total, used, used_percent) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetDiskStats :many
+select timestamp, mountpoint, total, used, used_percent from disk_stat ds
+where node_id = ?
+and timestamp >= strftime('%s', 'now') - ?
+order by timestamp, mountpoint;
+
+
+-- name: GetMountDiskStats :many
+select timestamp, used_percent from disk_stat ds
+where node_id = ? and mountpoint = ?
+and timestamp >= ?
+order by timestamp;
+
+-- name: GetMountDiskStatWindow :many
+select timestamp, used_percent from disk_stat ds
+where node_id = ? and mountpoint = ?
+and timestamp >= ? and timestamp < ?
+order by timestamp;
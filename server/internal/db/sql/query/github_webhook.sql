@@ -0,0 +1,15 @@
+-- name: CreateGitHubWebhookEvent :one
+INSERT INTO github_webhook_events (delivery_id, event, repository, branch, commit_sha, project_id, deployment_id, status, reason)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListGitHubWebhookEvents :many
+SELECT * FROM github_webhook_events
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListGitHubWebhookEventsByProject :many
+SELECT * FROM github_webhook_events
+WHERE project_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
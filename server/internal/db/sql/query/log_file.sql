@@ -0,0 +1,13 @@
+-- name: ListNodeLogFiles :many
+SELECT * FROM node_log_files
+WHERE node_id = ?
+ORDER BY path;
+
+-- name: CreateNodeLogFile :one
+INSERT INTO node_log_files (node_id, path)
+VALUES (?, ?)
+RETURNING *;
+
+-- name: DeleteNodeLogFile :execrows
+DELETE FROM node_log_files
+WHERE id = ? AND node_id = ?;
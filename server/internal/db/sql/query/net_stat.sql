@@ -4,4 +4,10 @@ INSERT INTO net_stat (timestamp, node_id, sent, recv) VALUES (?, ?, ?, ?);
 -- name: GetNetStats :many
 select timestamp, sent, recv from net_stat ns
 where node_id = ?
-and timestamp >= strftime('%s', 'now') - ?;
\ No newline at end of file
+and timestamp >= strftime('%s', 'now') - ?;
+
+-- name: GetNetStatWindow :many
+select timestamp, sent, recv from net_stat ns
+where node_id = ?
+and timestamp >= ? and timestamp < ?
+order by timestamp;
@@ -1,6 +1,6 @@
 -- name: CreateNode :one
-INSERT INTO nodes (name, ip)
-VALUES (?, ?)
+INSERT INTO nodes (name, ip, machine_id)
+VALUES (?, ?, ?)
 RETURNING *;
 -- name: GetNode :one
 SELECT *
@@ -28,6 +28,9 @@ WHERE ip = ?;
 SELECT n.id,
   n.name,
   n.ip,
+  n.machine_id,
+  n.status,
+  n.last_seen_at,
   nsi.os,
   nsi.platform,
   nsi.platform_version,
@@ -48,6 +51,9 @@ LIMIT ? OFFSET ?;
 SELECT n.id,
   n.name,
   n.ip,
+  n.machine_id,
+  n.status,
+  n.last_seen_at,
   nsi.os,
   nsi.platform,
   nsi.platform_version,
@@ -61,6 +67,43 @@ WHERE n.id = ?;
 UPDATE nodes
 SET name = ?
 WHERE id = ?;
+-- name: GetNodeByMachineID :one
+SELECT *
+FROM nodes
+WHERE machine_id = ?;
+-- name: SetNodeMachineID :exec
+UPDATE nodes
+SET machine_id = ?,
+  updated_at = strftime('%s', 'now')
+WHERE id = ?;
+-- name: UpdateNodeIP :exec
+UPDATE nodes
+SET ip = ?,
+  updated_at = strftime('%s', 'now')
+WHERE id = ?;
+-- name: TouchNode :exec
+UPDATE nodes
+SET last_seen_at = ?
+WHERE id = ?;
+-- name: SetNodeStatus :exec
+UPDATE nodes
+SET status = ?
+WHERE id = ?;
+-- name: ListNodes :many
+SELECT *
+FROM nodes;
+--######################################################################################
+------------------------------------ip history-------------------------------------------
+-- name: RecordNodeIP :exec
+INSERT INTO node_ip_history (node_id, ip)
+VALUES (?, ?) ON CONFLICT (node_id, ip) DO
+UPDATE
+SET last_seen_at = strftime('%s', 'now');
+-- name: GetNodeIPHistory :many
+SELECT *
+FROM node_ip_history
+WHERE node_id = ?
+ORDER BY last_seen_at DESC;
 --######################################################################################
 ------------------------------------sys info-------------------------------------------
 -- name: AddNodeSysInfo :one
@@ -90,6 +133,22 @@ SET os = ?,
   updated_at = strftime('%s', 'now')
 WHERE node_id = ?
 RETURNING *;
+-- name: AddNodeSysInfoHistory :exec
+INSERT INTO node_sys_info_history (
+    node_id,
+    os,
+    platform,
+    platform_version,
+    kernel_version,
+    cpus,
+    total_memory
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?);
+-- name: GetNodeSysInfoHistory :many
+SELECT *
+FROM node_sys_info_history
+WHERE node_id = ?
+ORDER BY changed_at DESC, id DESC;
 --######################################################################################
 ------------------------------------disk info-------------------------------------------
 -- name: AddNodeDiskInfo :one
@@ -110,10 +169,14 @@ WHERE node_id = ?;
 -- name: UpdateNodeDiskInfo :one
 UPDATE node_disk_info
 SET device = ?,
-  mount_point = ?,
   fstype = ?,
   total = ?,
   used = ?,
   updated_at = strftime('%s', 'now')
 WHERE node_id = ?
-RETURNING *;
\ No newline at end of file
+  AND mount_point = ?
+RETURNING *;
+-- name: DeleteStaleNodeDiskInfo :exec
+DELETE FROM node_disk_info
+WHERE node_id = ?
+  AND updated_at < ?;
\ No newline at end of file
@@ -0,0 +1,72 @@
+-- name: CreateNotificationChannel :one
+INSERT INTO notification_channels (name, type, config)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: UpdateNotificationChannel :one
+UPDATE notification_channels
+SET name = ?,
+  type = ?,
+  config = ?,
+  updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: GetNotificationChannel :one
+SELECT * FROM notification_channels
+WHERE id = ?;
+
+-- name: ListNotificationChannels :many
+SELECT * FROM notification_channels
+ORDER BY name;
+
+-- name: DeleteNotificationChannel :execrows
+DELETE FROM notification_channels
+WHERE id = ?;
+
+-- name: ListAlertChannels :many
+SELECT nc.* FROM notification_channels nc
+JOIN alert_channels ac ON ac.channel_id = nc.id
+WHERE ac.alert_id = ?
+ORDER BY nc.id;
+
+-- name: AddAlertChannel :exec
+INSERT OR IGNORE INTO alert_channels (alert_id, channel_id)
+VALUES (?, ?);
+
+-- name: ClearAlertChannels :exec
+DELETE FROM alert_channels
+WHERE alert_id = ?;
+
+-- name: ListCronJobChannels :many
+SELECT nc.* FROM notification_channels nc
+JOIN cron_job_channels jc ON jc.channel_id = nc.id
+WHERE jc.cron_job_id = ?
+ORDER BY nc.id;
+
+-- name: AddCronJobChannel :exec
+INSERT OR IGNORE INTO cron_job_channels (cron_job_id, channel_id)
+VALUES (?, ?);
+
+-- name: ClearCronJobChannels :exec
+DELETE FROM cron_job_channels
+WHERE cron_job_id = ?;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    channel_id,
+    attempt,
+    url,
+    status_code,
+    error,
+    response,
+    duration_ms,
+    created_at
+  )
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE channel_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
@@ -1,6 +1,6 @@
 -- name: CreateProject :one
-INSERT INTO projects (name, description, node_id, repo_url, branch, deploy_path, status)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO projects (name, description, node_id, repo_url, branch, deploy_path, status, build_steps, keep_releases, auto_deploy, webhook_secret)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetProject :one
@@ -25,6 +25,9 @@ SET name = ?,
     branch = ?,
     deploy_path = ?,
     status = ?,
+    build_steps = ?,
+    keep_releases = ?,
+    auto_deploy = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?
 RETURNING *;
@@ -43,6 +46,66 @@ SET last_deployed_at = strftime('%s', 'now'),
 WHERE id = ?
 RETURNING *;
 
+-- name: SetProjectCurrentDeployment :exec
+UPDATE projects
+SET current_deployment_id = ?,
+    last_deployed_at = strftime('%s', 'now'),
+    updated_at = strftime('%s', 'now')
+WHERE id = ?;
+
+-- name: SetProjectWebhookSecret :one
+UPDATE projects
+SET webhook_secret = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: ListProjectsWithRepo :many
+SELECT * FROM projects
+WHERE repo_url IS NOT NULL AND repo_url != '';
+
+-- name: ListNodeProjects :many
+SELECT * FROM projects
+WHERE node_id = ?;
+
+-- name: CreateDiscoveredProject :one
+INSERT INTO projects (name, node_id, repo_url, branch, deploy_path, webhook_secret, source, tech, commands, log_paths, backup_config, config_path, config_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, 'discovered', ?, ?, ?, ?, ?, strftime('%s', 'now'))
+RETURNING *;
+
+-- name: UpdateProjectConfig :exec
+UPDATE projects
+SET name = ?,
+    repo_url = ?,
+    branch = ?,
+    tech = ?,
+    commands = ?,
+    log_paths = ?,
+    backup_config = ?,
+    config_path = ?,
+    config_seen_at = strftime('%s', 'now'),
+    config_missing = 0,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?;
+
+-- name: SetProjectConfigMissing :exec
+UPDATE projects
+SET config_missing = 1,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?;
+
+-- name: SetProjectBackupSchedule :one
+UPDATE projects
+SET backup_interval_hours = ?,
+    backup_retention = ?,
+    updated_at = strftime('%s', 'now')
+WHERE id = ?
+RETURNING *;
+
+-- name: ListProjectsWithBackupSchedule :many
+SELECT * FROM projects
+WHERE backup_interval_hours > 0 AND backup_config IS NOT NULL;
+
 -- name: DeleteProject :execrows
 DELETE FROM projects WHERE id = ?;
 
@@ -70,3 +133,9 @@ SELECT COUNT(*) FROM projects;
 
 -- name: CountProjectsByNode :one
 SELECT COUNT(*) FROM projects WHERE node_id = ?;
+
+-- name: FailCloningProjects :exec
+UPDATE projects
+SET status = 'error',
+    updated_at = strftime('%s', 'now')
+WHERE status = 'cloning';
@@ -6,4 +6,11 @@ VALUES (?, ?, ?, ?, ?);
 select timestamp, value from system_stats ss 
 where node_id = ? and stat_type = ?
 and cpu_id = ?
-and timestamp >= strftime('%s', 'now') - ?;
\ No newline at end of file
+and timestamp >= strftime('%s', 'now') - ?;
+
+-- name: GetSystemStatWindow :many
+select timestamp, cast(avg(value) as real) as value from system_stats ss
+where node_id = ? and stat_type = ?
+and timestamp >= ? and timestamp < ?
+group by timestamp
+order by timestamp;
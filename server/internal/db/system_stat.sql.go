@@ -10,6 +10,54 @@ import (
 	"database/sql"
 )
 
+const getSystemStatWindow = `-- name: GetSystemStatWindow :many
+select timestamp, cast(avg(value) as real) as value from system_stats ss
+where node_id = ? and stat_type = ?
+and timestamp >= ? and timestamp < ?
+group by timestamp
+order by timestamp
+`
+
+type GetSystemStatWindowParams struct {
+	NodeID      int64  `json:"node_id"`
+	StatType    string `json:"stat_type"`
+	Timestamp   int64  `json:"timestamp"`
+	Timestamp_2 int64  `json:"timestamp_2"`
+}
+
+type GetSystemStatWindowRow struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+func (q *Queries) GetSystemStatWindow(ctx context.Context, arg GetSystemStatWindowParams) ([]GetSystemStatWindowRow, error) {
+	rows, err := q.query(ctx, q.getSystemStatWindowStmt, getSystemStatWindow,
+		arg.NodeID,
+		arg.StatType,
+		arg.Timestamp,
+		arg.Timestamp_2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSystemStatWindowRow
+	for rows.Next() {
+		var i GetSystemStatWindowRow
+		if err := rows.Scan(&i.Timestamp, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSystemStats = `-- name: GetSystemStats :many
 select timestamp, value from system_stats ss 
 where node_id = ? and stat_type = ?
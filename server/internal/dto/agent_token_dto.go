@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+type AgentTokenCreateDto struct {
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	NodeID    int32  `json:"node_id"`
+	ExpiresIn int32  `json:"expires_in"` // in hours, 0 means the token never expires
+}
+
+type AgentTokenDto struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	NodeID     int32      `json:"node_id,omitempty"`
+	Token      string     `json:"token,omitempty"` // only returned once, on creation
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (a *AgentTokenDto) Convert(row *db.AgentToken) {
+	a.ID = int32(row.ID)
+	a.Name = row.Name
+	a.NodeID = int32(row.NodeID.Int64)
+	a.ExpiresAt = unixToTimePtr(row.ExpiresAt.Int64, row.ExpiresAt.Valid)
+	a.RevokedAt = unixToTimePtr(row.RevokedAt.Int64, row.RevokedAt.Valid)
+	a.LastUsedAt = unixToTimePtr(row.LastUsedAt.Int64, row.LastUsedAt.Valid)
+	a.CreatedAt = time.Unix(row.CreatedAt, 0)
+}
+
+func unixToTimePtr(ts int64, valid bool) *time.Time {
+	if !valid {
+		return nil
+	}
+	t := time.Unix(ts, 0)
+	return &t
+}
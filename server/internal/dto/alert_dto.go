@@ -6,11 +6,12 @@ type AlertDto struct {
 	Threshold        float64 `json:"threshold"`
 	NetReceThreshold float64 `json:"net_rece_threshold"`
 	NetSendThreshold float64 `json:"net_send_threshold"`
-	Duration         int32   `json:"duration"`
-	Email            string  `json:"email"`
-	Discord          string  `json:"discord"`
-	Slack            string  `json:"slack"`
+	Duration         int32   `json:"duration"`                                              // minutes the breach must be sustained, 0 fires on the first breaching sample
+	Aggregation      string  `json:"aggregation" binding:"omitempty,oneof=all avg max p95"` // how the window is reduced, defaults to all
+	ResendInterval   int32   `json:"resend_interval" binding:"min=0"`                       // minutes between reminders while firing, 0 for none
 	Enabled          bool    `json:"enabled"`
+	Mountpoint       string  `json:"mountpoint"`  // disk and disk_fill only, empty means any mount
+	ChannelIDs       []int64 `json:"channel_ids"` // notification channels
 }
 
 type AlertUpdateDto struct {
@@ -20,11 +21,12 @@ type AlertUpdateDto struct {
 	Threshold        float64 `json:"threshold"`
 	NetReceThreshold float64 `json:"net_rece_threshold"`
 	NetSendThreshold float64 `json:"net_send_threshold"`
-	Duration         int32   `json:"duration"`
-	Email            string  `json:"email"`
-	Discord          string  `json:"discord"`
-	Slack            string  `json:"slack"`
+	Duration         int32   `json:"duration"`                                              // minutes the breach must be sustained, 0 fires on the first breaching sample
+	Aggregation      string  `json:"aggregation" binding:"omitempty,oneof=all avg max p95"` // how the window is reduced, defaults to all
+	ResendInterval   int32   `json:"resend_interval" binding:"min=0"`                       // minutes between reminders while firing, 0 for none
 	Enabled          bool    `json:"enabled"`
+	Mountpoint       string  `json:"mountpoint"`  // disk and disk_fill only, empty means any mount
+	ChannelIDs       []int64 `json:"channel_ids"` // replaces the rule's notification channels, omit to keep them
 }
 
 // export const AlertSchema = z.object({
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// AlertHistoryQuery filters GET /alerts/history, empty fields match everything.
+// From and To are RFC 3339 times.
+type AlertHistoryQuery struct {
+	NodeID  int64     `form:"node_id"`
+	AlertID int64     `form:"alert_id"`
+	Metric  string    `form:"metric"`
+	State   string    `form:"state" binding:"omitempty,oneof=ok pending firing resolved"`
+	From    time.Time `form:"from"`
+	To      time.Time `form:"to"`
+	Limit   int64     `form:"limit" binding:"min=0,max=500"`
+	Offset  int64     `form:"offset" binding:"min=0"`
+}
+
+// AlertDeliveryDto is how one notification channel took an alert event
+type AlertDeliveryDto struct {
+	Channel   string `json:"channel"`
+	ChannelID int64  `json:"channel_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Status    string `json:"status"` // sent or failed
+	Error     string `json:"error,omitempty"`
+}
+
+type AlertEventDto struct {
+	ID         int64              `json:"id"`
+	AlertID    int64              `json:"alert_id"`
+	NodeID     int64              `json:"node_id"`
+	Metric     string             `json:"metric"`
+	Value      string             `json:"value"`
+	Threshold  string             `json:"threshold"`
+	FromState  string             `json:"from_state"`
+	ToState    string             `json:"to_state"`
+	BreachedAt *time.Time         `json:"breached_at,omitempty"`
+	Deliveries []AlertDeliveryDto `json:"deliveries"` // empty when the transition sent nothing
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// ConvertToAlertEventDto converts a db.AlertEvent to AlertEventDto
+func ConvertToAlertEventDto(e *db.AlertEvent) *AlertEventDto {
+	event := &AlertEventDto{
+		ID:         e.ID,
+		AlertID:    e.AlertID,
+		NodeID:     e.NodeID,
+		Metric:     e.Metric,
+		Value:      e.Value.String,
+		Threshold:  e.Threshold.String,
+		FromState:  e.FromState,
+		ToState:    e.ToState,
+		Deliveries: []AlertDeliveryDto{},
+		CreatedAt:  time.Unix(e.CreatedAt, 0),
+	}
+	if e.BreachedAt.Valid {
+		breachedAt := time.Unix(e.BreachedAt.Int64, 0)
+		event.BreachedAt = &breachedAt
+	}
+	if e.Deliveries.Valid {
+		json.Unmarshal([]byte(e.Deliveries.String), &event.Deliveries)
+	}
+	return event
+}
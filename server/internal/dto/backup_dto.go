@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// BackupScheduleRequest sets how often a project is backed up and how many backups are kept
+type BackupScheduleRequest struct {
+	IntervalHours int32 `json:"interval_hours" binding:"min=0,max=8760"` // 0 turns scheduled backups off
+	Retention     int32 `json:"retention" binding:"required,min=1,max=365"`
+}
+
+// RestoreBackupRequest represents the request to restore a backup onto its project
+type RestoreBackupRequest struct {
+	Database bool `json:"database"` // also import the SQL dump
+}
+
+// BackupDto is a backup in the catalog
+type BackupDto struct {
+	ID          int64      `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	NodeID      int32      `json:"node_id"`
+	Status      string     `json:"status"`
+	TriggerType string     `json:"trigger_type"`
+	TriggeredBy *int64     `json:"triggered_by,omitempty"`
+	FileName    string     `json:"file_name"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Sha256      string     `json:"sha256,omitempty"`
+	Contents    []string   `json:"contents"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ConvertToBackupDto converts a db.Backup to BackupDto
+func ConvertToBackupDto(b *db.Backup) *BackupDto {
+	return &BackupDto{
+		ID:          b.ID,
+		ProjectID:   b.ProjectID,
+		NodeID:      int32(b.NodeID),
+		Status:      b.Status,
+		TriggerType: b.TriggerType,
+		TriggeredBy: nullInt64Ptr(b.TriggeredBy),
+		FileName:    b.FileName,
+		SizeBytes:   b.SizeBytes,
+		Sha256:      b.Sha256.String,
+		Contents:    ParseStringList(b.Contents),
+		Error:       b.Error.String,
+		StartedAt:   time.Unix(b.StartedAt, 0),
+		FinishedAt:  unixToTimePtr(b.FinishedAt.Int64, b.FinishedAt.Valid),
+	}
+}
+
+// BackupRestoreDto is one restore of a backup
+type BackupRestoreDto struct {
+	ID              int64      `json:"id"`
+	BackupID        int64      `json:"backup_id"`
+	ProjectID       string     `json:"project_id"`
+	Status          string     `json:"status"`
+	RestoreDatabase bool       `json:"restore_database"`
+	TriggeredBy     *int64     `json:"triggered_by,omitempty"`
+	Restored        []string   `json:"restored"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// ConvertToBackupRestoreDto converts a db.BackupRestore to BackupRestoreDto
+func ConvertToBackupRestoreDto(r *db.BackupRestore) *BackupRestoreDto {
+	return &BackupRestoreDto{
+		ID:              r.ID,
+		BackupID:        r.BackupID,
+		ProjectID:       r.ProjectID,
+		Status:          r.Status,
+		RestoreDatabase: r.RestoreDatabase == 1,
+		TriggeredBy:     nullInt64Ptr(r.TriggeredBy),
+		Restored:        ParseStringList(r.Restored),
+		Error:           r.Error.String,
+		StartedAt:       time.Unix(r.StartedAt, 0),
+		FinishedAt:      unixToTimePtr(r.FinishedAt.Int64, r.FinishedAt.Valid),
+	}
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+type AgentCertificateIssueDto struct {
+	ValidDays int32 `json:"valid_days"`
+}
+
+type AgentCertificateDto struct {
+	ID          int32      `json:"id"`
+	NodeID      int32      `json:"node_id"`
+	Serial      string     `json:"serial"`
+	Fingerprint string     `json:"fingerprint"`
+	NotAfter    time.Time  `json:"not_after"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (a *AgentCertificateDto) Convert(row *db.AgentCertificate) {
+	a.ID = int32(row.ID)
+	a.NodeID = int32(row.NodeID)
+	a.Serial = row.Serial
+	a.Fingerprint = row.Fingerprint
+	a.NotAfter = time.Unix(row.NotAfter, 0)
+	a.RevokedAt = unixToTimePtr(row.RevokedAt.Int64, row.RevokedAt.Valid)
+	a.CreatedAt = time.Unix(row.CreatedAt, 0)
+}
+
+// AgentCertificateIssuedDto is returned once when a certificate is issued,
+// the private key is not stored on the server.
+type AgentCertificateIssuedDto struct {
+	AgentCertificateDto
+	CertPEM string `json:"cert_pem"`
+	KeyPEM  string `json:"key_pem"`
+	CaPEM   string `json:"ca_pem"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "encoding/json"
+
+type NodeCommandDto struct {
+	Type    string            `json:"type" binding:"required"`
+	Args    map[string]string `json:"args"`
+	Timeout int32             `json:"timeout"` // seconds to wait for the agent reply
+}
+
+type NodeCommandResultDto struct {
+	CorrelationId string          `json:"correlation_id"`
+	Ok            bool            `json:"ok"`
+	Output        string          `json:"output,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	Data          json.RawMessage `json:"data,omitempty"`
+}
+
+// ExecCommandDto is the first message sent on the exec WebSocket
+type ExecCommandDto struct {
+	Command string `json:"command" binding:"required"`
+	Timeout int32  `json:"timeout"` // seconds, defaults to 60
+}
+
+func (e *ExecCommandDto) FromBytes(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+// ExecStartedDto is sent on the exec WebSocket once the command was dispatched
+type ExecStartedDto struct {
+	Type        string `json:"type"`
+	ExecutionID int64  `json:"execution_id"`
+	Output      string `json:"output,omitempty"`
+}
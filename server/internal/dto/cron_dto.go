@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// CronJobRequest creates or updates a cron job. The schedule is a five field
+// cron expression in UTC, or a macro such as @daily.
+type CronJobRequest struct {
+	NodeID     int32   `json:"node_id" binding:"required"`
+	Name       string  `json:"name" binding:"required,max=255"`
+	Schedule   string  `json:"schedule" binding:"required"`
+	Command    string  `json:"command" binding:"required"`
+	WorkingDir string  `json:"working_dir"`
+	User       string  `json:"user"`        // system user the command runs as, empty for the agent's user
+	Enabled    *bool   `json:"enabled"`     // defaults to true
+	ChannelIDs []int64 `json:"channel_ids"` // notification channels of failed and missed runs, omit on update to keep them
+}
+
+// CronJobDto is a cron job with the outcome of its last run
+type CronJobDto struct {
+	ID         int64      `json:"id"`
+	NodeID     int32      `json:"node_id"`
+	Name       string     `json:"name"`
+	Schedule   string     `json:"schedule"`
+	Command    string     `json:"command"`
+	WorkingDir string     `json:"working_dir"`
+	User       string     `json:"user"`
+	Enabled    bool       `json:"enabled"`
+	ChannelIDs []int64    `json:"channel_ids"`
+	LastStatus string     `json:"last_status,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ConvertToCronJobDto converts a db.CronJob to CronJobDto
+func ConvertToCronJobDto(j *db.CronJob) *CronJobDto {
+	return &CronJobDto{
+		ID:         j.ID,
+		NodeID:     int32(j.NodeID),
+		Name:       j.Name,
+		Schedule:   j.Schedule,
+		Command:    j.Command,
+		WorkingDir: j.WorkingDir.String,
+		User:       j.RunAsUser.String,
+		Enabled:    j.Enabled == 1,
+		LastStatus: j.LastStatus.String,
+		LastRunAt:  unixToTimePtr(j.LastRunAt.Int64, j.LastRunAt.Valid),
+		CreatedAt:  time.Unix(j.CreatedAt, 0),
+		UpdatedAt:  time.Unix(j.UpdatedAt, 0),
+	}
+}
+
+// CronRunDto is one run of a cron job
+type CronRunDto struct {
+	ID          int64      `json:"id"`
+	CronJobID   int64      `json:"cron_job_id"`
+	NodeID      int32      `json:"node_id"`
+	Status      string     `json:"status"`
+	ExitCode    *int64     `json:"exit_code,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Output      string     `json:"output"`
+	Error       string     `json:"error,omitempty"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ConvertToCronRunDto converts a db.CronRun to CronRunDto
+func ConvertToCronRunDto(r *db.CronRun) *CronRunDto {
+	return &CronRunDto{
+		ID:          r.ID,
+		CronJobID:   r.CronJobID,
+		NodeID:      int32(r.NodeID),
+		Status:      r.Status,
+		ExitCode:    nullInt64Ptr(r.ExitCode),
+		DurationMs:  r.DurationMs,
+		Output:      r.Output.String,
+		Error:       r.Error.String,
+		ScheduledAt: time.Unix(r.ScheduledAt, 0),
+		StartedAt:   unixToTimePtr(r.StartedAt.Int64, r.StartedAt.Valid),
+		FinishedAt:  unixToTimePtr(r.FinishedAt.Int64, r.FinishedAt.Valid),
+	}
+}
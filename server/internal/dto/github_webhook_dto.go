@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// GitHubPushEvent holds the parts of a GitHub push payload used to match projects
+type GitHubPushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// GitHubWebhookEventDto is one logged outcome of a webhook delivery
+type GitHubWebhookEventDto struct {
+	ID           int64     `json:"id"`
+	DeliveryID   string    `json:"delivery_id"`
+	Event        string    `json:"event"`
+	Repository   string    `json:"repository"`
+	Branch       string    `json:"branch"`
+	CommitSHA    string    `json:"commit_sha"`
+	ProjectID    string    `json:"project_id,omitempty"`
+	DeploymentID *int64    `json:"deployment_id,omitempty"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ConvertToGitHubWebhookEventDto converts a db.GithubWebhookEvent to GitHubWebhookEventDto
+func ConvertToGitHubWebhookEventDto(e *db.GithubWebhookEvent) *GitHubWebhookEventDto {
+	return &GitHubWebhookEventDto{
+		ID:           e.ID,
+		DeliveryID:   e.DeliveryID.String,
+		Event:        e.Event,
+		Repository:   e.Repository.String,
+		Branch:       e.Branch.String,
+		CommitSHA:    e.CommitSha.String,
+		ProjectID:    e.ProjectID.String,
+		DeploymentID: nullInt64Ptr(e.DeploymentID),
+		Status:       e.Status,
+		Reason:       e.Reason.String,
+		CreatedAt:    time.Unix(e.CreatedAt, 0),
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// LogTailQuery selects a log file and the lines to return from it. Grep keeps only
+// matching lines, as a substring or, with Regex, a regular expression.
+type LogTailQuery struct {
+	File       string `form:"file" binding:"required"`
+	Lines      int    `form:"lines" binding:"min=0,max=5000"`
+	Grep       string `form:"grep"`
+	Regex      bool   `form:"regex"`
+	IgnoreCase bool   `form:"ignore_case"`
+}
+
+// LogTailDto is the end of a log file
+type LogTailDto struct {
+	File  string   `json:"file"`
+	Lines []string `json:"lines"`
+}
+
+// LogEventDto is a message of the log WebSocket
+type LogEventDto struct {
+	Type  string   `json:"type"` // lines, end or error
+	Lines []string `json:"lines,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// NodeLogFileRequest allow-lists a log file on a node
+type NodeLogFileRequest struct {
+	Path string `json:"path" binding:"required"`
+}
+
+type NodeLogFileDto struct {
+	ID        int64     `json:"id"`
+	NodeID    int32     `json:"node_id"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConvertToNodeLogFileDto converts a db.NodeLogFile to NodeLogFileDto
+func ConvertToNodeLogFileDto(f *db.NodeLogFile) *NodeLogFileDto {
+	return &NodeLogFileDto{
+		ID:        f.ID,
+		NodeID:    int32(f.NodeID),
+		Path:      f.Path,
+		CreatedAt: time.Unix(f.CreatedAt, 0),
+	}
+}
@@ -2,27 +2,34 @@ package dto
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 	"github.com/sanda0/vps_pilot/internal/utils"
 )
 
 type NodeWithSysInfoDto struct {
-	ID              int32   `json:"id"`
-	Name            string  `json:"name"`
-	Ip              string  `json:"ip"`
-	Os              string  `json:"os"`
-	Platform        string  `json:"platform"`
-	PlatformVersion string  `json:"platform_version"`
-	KernelVersion   string  `json:"kernel_version"`
-	Cpus            int32   `json:"cpus"`
-	TotalMemory     float64 `json:"total_memory"`
+	ID              int32      `json:"id"`
+	Name            string     `json:"name"`
+	Ip              string     `json:"ip"`
+	MachineID       string     `json:"machine_id"`
+	Status          string     `json:"status"`
+	LastSeen        *time.Time `json:"last_seen"`
+	Os              string     `json:"os"`
+	Platform        string     `json:"platform"`
+	PlatformVersion string     `json:"platform_version"`
+	KernelVersion   string     `json:"kernel_version"`
+	Cpus            int32      `json:"cpus"`
+	TotalMemory     float64    `json:"total_memory"`
 }
 
 func (n *NodeWithSysInfoDto) Convert(row *db.GetNodesWithSysInfoRow) {
 	n.ID = int32(row.ID)
 	n.Name = row.Name.String
 	n.Ip = row.Ip
+	n.MachineID = row.MachineID.String
+	n.Status = row.Status
+	n.LastSeen = unixToTimePtr(row.LastSeenAt.Int64, row.LastSeenAt.Valid)
 	n.Os = row.Os.String
 	n.Platform = row.Platform.String
 	n.PlatformVersion = row.PlatformVersion.String
@@ -37,11 +44,14 @@ type NodeNameUpdateDto struct {
 }
 
 type NodeDto struct {
-	ID     int32   `json:"id"`
-	Name   string  `json:"name"`
-	Ip     string  `json:"ip"`
-	Memory float64 `json:"memory"`
-	Cpus   int32   `json:"cpus"`
+	ID        int32      `json:"id"`
+	Name      string     `json:"name"`
+	Ip        string     `json:"ip"`
+	MachineID string     `json:"machine_id"`
+	Status    string     `json:"status"`
+	LastSeen  *time.Time `json:"last_seen"`
+	Memory    float64    `json:"memory"`
+	Cpus      int32      `json:"cpus"`
 }
 
 type SystemStatQueryDto struct {
@@ -56,6 +66,7 @@ type SystemStatResponseDto struct {
 	Cpu       []map[string]interface{} `json:"cpu"`
 	Mem       []db.GetSystemStatsRow   `json:"mem"`
 	Net       []db.GetNetStatsRow      `json:"net"`
+	Disks     []db.GetDiskStatsRow     `json:"disks"`
 }
 
 func (s *SystemStatResponseDto) ToBytes() ([]byte, error) {
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// NotificationChannelRequest creates or updates a notification channel. The shape of
+// Config depends on Type, for example {"webhook_url": "..."} for discord and slack
+// or {"to": "ops@example.com"} for email.
+type NotificationChannelRequest struct {
+	Name   string          `json:"name" binding:"required,max=255"`
+	Type   string          `json:"type" binding:"required"`
+	Config json.RawMessage `json:"config" binding:"required"`
+}
+
+type NotificationChannelDto struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Config    json.RawMessage `json:"config"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ConvertToNotificationChannelDto converts a db.NotificationChannel to NotificationChannelDto
+func ConvertToNotificationChannelDto(c *db.NotificationChannel) *NotificationChannelDto {
+	return &NotificationChannelDto{
+		ID:        c.ID,
+		Name:      c.Name,
+		Type:      c.Type,
+		Config:    json.RawMessage(c.Config),
+		CreatedAt: time.Unix(c.CreatedAt, 0),
+		UpdatedAt: time.Unix(c.UpdatedAt, 0),
+	}
+}
+
+// WebhookDeliveryQuery pages GET /notification-channels/:id/deliveries
+type WebhookDeliveryQuery struct {
+	Limit  int64 `form:"limit" binding:"min=0,max=500"`
+	Offset int64 `form:"offset" binding:"min=0"`
+}
+
+// WebhookDeliveryDto is one HTTP attempt of a channel, StatusCode is 0 when no
+// response came back
+type WebhookDeliveryDto struct {
+	ID         int64     `json:"id"`
+	ChannelID  int64     `json:"channel_id"`
+	Attempt    int64     `json:"attempt"`
+	URL        string    `json:"url"`
+	StatusCode int64     `json:"status_code"`
+	Error      string    `json:"error,omitempty"`
+	Response   string    `json:"response,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ConvertToWebhookDeliveryDto converts a db.WebhookDelivery to WebhookDeliveryDto
+func ConvertToWebhookDeliveryDto(d *db.WebhookDelivery) *WebhookDeliveryDto {
+	return &WebhookDeliveryDto{
+		ID:         d.ID,
+		ChannelID:  d.ChannelID,
+		Attempt:    d.Attempt,
+		URL:        d.Url,
+		StatusCode: d.StatusCode.Int64,
+		Error:      d.Error.String,
+		Response:   d.Response.String,
+		DurationMs: d.DurationMs,
+		CreatedAt:  time.Unix(d.CreatedAt, 0),
+	}
+}
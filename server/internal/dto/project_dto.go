@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/sanda0/vps_pilot/internal/db"
@@ -8,39 +10,60 @@ import (
 
 // CreateProjectRequest represents the request to create a new project
 type CreateProjectRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	NodeID      int32  `json:"node_id" binding:"required"`
-	RepoURL     string `json:"repo_url" binding:"omitempty,url"`
-	Branch      string `json:"branch" binding:"required"`
-	DeployPath  string `json:"deploy_path" binding:"required,min=1"`
+	Name         string   `json:"name" binding:"required,min=1,max=100"`
+	Description  string   `json:"description" binding:"max=500"`
+	NodeID       int32    `json:"node_id" binding:"required"`
+	RepoURL      string   `json:"repo_url" binding:"omitempty,url"`
+	Branch       string   `json:"branch" binding:"required"`
+	DeployPath   string   `json:"deploy_path" binding:"required,min=1"`
+	BuildSteps   []string `json:"build_steps"`
+	KeepReleases int32    `json:"keep_releases" binding:"omitempty,min=1,max=50"`
+	AutoDeploy   bool     `json:"auto_deploy"`
 }
 
 // UpdateProjectRequest represents the request to update a project
 type UpdateProjectRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	RepoURL     string `json:"repo_url" binding:"omitempty,url"`
-	Branch      string `json:"branch" binding:"required"`
-	DeployPath  string `json:"deploy_path" binding:"required,min=1"`
-	Status      string `json:"status" binding:"omitempty,oneof=inactive cloning active error"`
+	Name         string   `json:"name" binding:"required,min=1,max=100"`
+	Description  string   `json:"description" binding:"max=500"`
+	RepoURL      string   `json:"repo_url" binding:"omitempty,url"`
+	Branch       string   `json:"branch" binding:"required"`
+	DeployPath   string   `json:"deploy_path" binding:"required,min=1"`
+	Status       string   `json:"status" binding:"omitempty,oneof=inactive cloning active error"`
+	BuildSteps   []string `json:"build_steps"`
+	KeepReleases int32    `json:"keep_releases" binding:"omitempty,min=1,max=50"`
+	AutoDeploy   bool     `json:"auto_deploy"`
 }
 
 // ProjectResponse represents a project with additional node information
 type ProjectResponse struct {
-	ID             string     `json:"id"`
-	Name           string     `json:"name"`
-	Description    string     `json:"description"`
-	NodeID         int32      `json:"node_id"`
-	NodeName       string     `json:"node_name,omitempty"`
-	NodeIP         string     `json:"node_ip,omitempty"`
-	RepoURL        string     `json:"repo_url"`
-	Branch         string     `json:"branch"`
-	DeployPath     string     `json:"deploy_path"`
-	Status         string     `json:"status"`
-	LastDeployedAt *time.Time `json:"last_deployed_at,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID                  string              `json:"id"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description"`
+	NodeID              int32               `json:"node_id"`
+	NodeName            string              `json:"node_name,omitempty"`
+	NodeIP              string              `json:"node_ip,omitempty"`
+	RepoURL             string              `json:"repo_url"`
+	Branch              string              `json:"branch"`
+	DeployPath          string              `json:"deploy_path"`
+	Status              string              `json:"status"`
+	BuildSteps          []string            `json:"build_steps"`
+	KeepReleases        int32               `json:"keep_releases"`
+	CurrentDeploymentID *int64              `json:"current_deployment_id,omitempty"`
+	AutoDeploy          bool                `json:"auto_deploy"`
+	WebhookSecret       string              `json:"webhook_secret,omitempty"`
+	Source              string              `json:"source"`
+	Tech                []string            `json:"tech"`
+	Commands            []ProjectCommandDto `json:"commands"`
+	LogPaths            []string            `json:"log_paths"`
+	BackupConfig        json.RawMessage     `json:"backup_config,omitempty"`
+	ConfigPath          string              `json:"config_path,omitempty"`
+	ConfigSeenAt        *time.Time          `json:"config_seen_at,omitempty"`
+	ConfigMissing       bool                `json:"config_missing"`
+	BackupIntervalHours int32               `json:"backup_interval_hours"`
+	BackupRetention     int32               `json:"backup_retention"`
+	LastDeployedAt      *time.Time          `json:"last_deployed_at,omitempty"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
 }
 
 // ConvertToProjectResponse converts a db.Project to ProjectResponse
@@ -52,17 +75,32 @@ func ConvertToProjectResponse(p *db.Project) *ProjectResponse {
 	}
 
 	return &ProjectResponse{
-		ID:             p.ID,
-		Name:           p.Name,
-		Description:    p.Description.String,
-		NodeID:         int32(p.NodeID),
-		RepoURL:        p.RepoUrl.String,
-		Branch:         p.Branch.String,
-		DeployPath:     p.DeployPath,
-		Status:         p.Status.String,
-		LastDeployedAt: lastDeployed,
-		CreatedAt:      time.Unix(p.CreatedAt, 0),
-		UpdatedAt:      time.Unix(p.UpdatedAt, 0),
+		ID:                  p.ID,
+		Name:                p.Name,
+		Description:         p.Description.String,
+		NodeID:              int32(p.NodeID),
+		RepoURL:             p.RepoUrl.String,
+		Branch:              p.Branch.String,
+		DeployPath:          p.DeployPath,
+		Status:              p.Status.String,
+		BuildSteps:          ParseBuildSteps(p.BuildSteps),
+		KeepReleases:        int32(p.KeepReleases),
+		CurrentDeploymentID: nullInt64Ptr(p.CurrentDeploymentID),
+		AutoDeploy:          p.AutoDeploy == 1,
+		Source:              p.Source,
+		Tech:                ParseStringList(p.Tech),
+		Commands:            ParseProjectCommands(p.Commands),
+		LogPaths:            ParseStringList(p.LogPaths),
+		BackupConfig:        rawJSON(p.BackupConfig),
+		ConfigPath:          p.ConfigPath.String,
+		ConfigSeenAt:        unixToTimePtr(p.ConfigSeenAt.Int64, p.ConfigSeenAt.Valid),
+		ConfigMissing:       p.ConfigMissing == 1,
+		BackupIntervalHours: int32(p.BackupIntervalHours),
+		BackupRetention:     int32(p.BackupRetention),
+		WebhookSecret:       p.WebhookSecret.String,
+		LastDeployedAt:      lastDeployed,
+		CreatedAt:           time.Unix(p.CreatedAt, 0),
+		UpdatedAt:           time.Unix(p.UpdatedAt, 0),
 	}
 }
 
@@ -75,19 +113,34 @@ func ConvertToProjectWithNodeResponse(row *db.GetProjectWithNodeRow) *ProjectRes
 	}
 
 	return &ProjectResponse{
-		ID:             row.ID,
-		Name:           row.Name,
-		Description:    row.Description.String,
-		NodeID:         int32(row.NodeID),
-		NodeName:       row.NodeName.String,
-		NodeIP:         row.NodeIp.String,
-		RepoURL:        row.RepoUrl.String,
-		Branch:         row.Branch.String,
-		DeployPath:     row.DeployPath,
-		Status:         row.Status.String,
-		LastDeployedAt: lastDeployed,
-		CreatedAt:      time.Unix(row.CreatedAt, 0),
-		UpdatedAt:      time.Unix(row.UpdatedAt, 0),
+		ID:                  row.ID,
+		Name:                row.Name,
+		Description:         row.Description.String,
+		NodeID:              int32(row.NodeID),
+		NodeName:            row.NodeName.String,
+		NodeIP:              row.NodeIp.String,
+		RepoURL:             row.RepoUrl.String,
+		Branch:              row.Branch.String,
+		DeployPath:          row.DeployPath,
+		Status:              row.Status.String,
+		BuildSteps:          ParseBuildSteps(row.BuildSteps),
+		KeepReleases:        int32(row.KeepReleases),
+		CurrentDeploymentID: nullInt64Ptr(row.CurrentDeploymentID),
+		AutoDeploy:          row.AutoDeploy == 1,
+		Source:              row.Source,
+		Tech:                ParseStringList(row.Tech),
+		Commands:            ParseProjectCommands(row.Commands),
+		LogPaths:            ParseStringList(row.LogPaths),
+		BackupConfig:        rawJSON(row.BackupConfig),
+		ConfigPath:          row.ConfigPath.String,
+		ConfigSeenAt:        unixToTimePtr(row.ConfigSeenAt.Int64, row.ConfigSeenAt.Valid),
+		ConfigMissing:       row.ConfigMissing == 1,
+		BackupIntervalHours: int32(row.BackupIntervalHours),
+		BackupRetention:     int32(row.BackupRetention),
+		WebhookSecret:       row.WebhookSecret.String,
+		LastDeployedAt:      lastDeployed,
+		CreatedAt:           time.Unix(row.CreatedAt, 0),
+		UpdatedAt:           time.Unix(row.UpdatedAt, 0),
 	}
 }
 
@@ -102,20 +155,151 @@ func ConvertToProjectListResponse(rows []db.ListProjectsWithNodesRow) []*Project
 		}
 
 		projects[i] = &ProjectResponse{
-			ID:             row.ID,
-			Name:           row.Name,
-			Description:    row.Description.String,
-			NodeID:         int32(row.NodeID),
-			NodeName:       row.NodeName.String,
-			NodeIP:         row.NodeIp.String,
-			RepoURL:        row.RepoUrl.String,
-			Branch:         row.Branch.String,
-			DeployPath:     row.DeployPath,
-			Status:         row.Status.String,
-			LastDeployedAt: lastDeployed,
-			CreatedAt:      time.Unix(row.CreatedAt, 0),
-			UpdatedAt:      time.Unix(row.UpdatedAt, 0),
+			ID:                  row.ID,
+			Name:                row.Name,
+			Description:         row.Description.String,
+			NodeID:              int32(row.NodeID),
+			NodeName:            row.NodeName.String,
+			NodeIP:              row.NodeIp.String,
+			RepoURL:             row.RepoUrl.String,
+			Branch:              row.Branch.String,
+			DeployPath:          row.DeployPath,
+			Status:              row.Status.String,
+			BuildSteps:          ParseBuildSteps(row.BuildSteps),
+			KeepReleases:        int32(row.KeepReleases),
+			CurrentDeploymentID: nullInt64Ptr(row.CurrentDeploymentID),
+			AutoDeploy:          row.AutoDeploy == 1,
+			Source:              row.Source,
+			Tech:                ParseStringList(row.Tech),
+			Commands:            ParseProjectCommands(row.Commands),
+			LogPaths:            ParseStringList(row.LogPaths),
+			BackupConfig:        rawJSON(row.BackupConfig),
+			ConfigPath:          row.ConfigPath.String,
+			ConfigSeenAt:        unixToTimePtr(row.ConfigSeenAt.Int64, row.ConfigSeenAt.Valid),
+			ConfigMissing:       row.ConfigMissing == 1,
+			BackupIntervalHours: int32(row.BackupIntervalHours),
+			BackupRetention:     int32(row.BackupRetention),
+			LastDeployedAt:      lastDeployed,
+			CreatedAt:           time.Unix(row.CreatedAt, 0),
+			UpdatedAt:           time.Unix(row.UpdatedAt, 0),
 		}
 	}
 	return projects
 }
+
+// ParseBuildSteps decodes the JSON array of build commands stored on a project
+func ParseBuildSteps(raw sql.NullString) []string {
+	steps := []string{}
+	if raw.Valid && raw.String != "" {
+		if err := json.Unmarshal([]byte(raw.String), &steps); err != nil {
+			return []string{}
+		}
+	}
+	return steps
+}
+
+// EncodeBuildSteps encodes build commands for storage, dropping blank steps
+func EncodeBuildSteps(steps []string) sql.NullString {
+	cleaned := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step != "" {
+			cleaned = append(cleaned, step)
+		}
+	}
+	if len(cleaned) == 0 {
+		return sql.NullString{}
+	}
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// ProjectCommandDto is a command declared in the project's config.vpspilot.json
+type ProjectCommandDto struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// ParseStringList decodes a JSON array of strings stored on a project
+func ParseStringList(raw sql.NullString) []string {
+	list := []string{}
+	if raw.Valid && raw.String != "" {
+		if err := json.Unmarshal([]byte(raw.String), &list); err != nil {
+			return []string{}
+		}
+	}
+	return list
+}
+
+// ParseProjectCommands decodes the commands stored on a project
+func ParseProjectCommands(raw sql.NullString) []ProjectCommandDto {
+	commands := []ProjectCommandDto{}
+	if raw.Valid && raw.String != "" {
+		if err := json.Unmarshal([]byte(raw.String), &commands); err != nil {
+			return []ProjectCommandDto{}
+		}
+	}
+	return commands
+}
+
+func rawJSON(raw sql.NullString) json.RawMessage {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	return json.RawMessage(raw.String)
+}
+
+// DeploymentResponse represents a single deployment of a project
+type DeploymentResponse struct {
+	ID            int64      `json:"id"`
+	ProjectID     string     `json:"project_id"`
+	NodeID        int32      `json:"node_id"`
+	Branch        string     `json:"branch"`
+	CommitSHA     string     `json:"commit_sha"`
+	Status        string     `json:"status"`
+	Logs          string     `json:"logs,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	TriggeredBy   *int64     `json:"triggered_by,omitempty"`
+	RollbackOf    *int64     `json:"rollback_of,omitempty"`
+	ReleasePath   string     `json:"release_path,omitempty"`
+	ReleasePruned bool       `json:"release_pruned"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ConvertToDeploymentResponse converts a db.Deployment to DeploymentResponse
+func ConvertToDeploymentResponse(d *db.Deployment) *DeploymentResponse {
+	return &DeploymentResponse{
+		ID:            d.ID,
+		ProjectID:     d.ProjectID,
+		NodeID:        int32(d.NodeID),
+		Branch:        d.Branch.String,
+		CommitSHA:     d.CommitSha.String,
+		Status:        d.Status,
+		Logs:          d.Logs,
+		Error:         d.Error.String,
+		TriggeredBy:   nullInt64Ptr(d.TriggeredBy),
+		RollbackOf:    nullInt64Ptr(d.RollbackOf),
+		ReleasePath:   d.ReleasePath.String,
+		ReleasePruned: d.ReleasePruned == 1,
+		StartedAt:     unixToTimePtr(d.StartedAt.Int64, d.StartedAt.Valid),
+		FinishedAt:    unixToTimePtr(d.FinishedAt.Int64, d.FinishedAt.Valid),
+		CreatedAt:     time.Unix(d.CreatedAt, 0),
+	}
+}
+
+// RollbackRequest selects the deployment to roll back to. The previous
+// successful deployment is used when DeploymentID is not set.
+type RollbackRequest struct {
+	DeploymentID int64 `json:"deployment_id"`
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type AgentTokenHandler interface {
+	CreateToken(c *gin.Context)
+	GetTokens(c *gin.Context)
+	RevokeToken(c *gin.Context)
+}
+
+type agentTokenHandler struct {
+	agentTokenService services.AgentTokenService
+}
+
+// CreateToken implements AgentTokenHandler.
+func (a *agentTokenHandler) CreateToken(c *gin.Context) {
+	form := dto.AgentTokenCreateDto{}
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := a.agentTokenService.CreateToken(form)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": token})
+}
+
+// GetTokens implements AgentTokenHandler.
+func (a *agentTokenHandler) GetTokens(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	tokens, err := a.agentTokenService.GetTokens(int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": tokens})
+}
+
+// RevokeToken implements AgentTokenHandler.
+func (a *agentTokenHandler) RevokeToken(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	err = a.agentTokenService.RevokeToken(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": "Token revoked"})
+}
+
+func NewAgentTokenHandler(agentTokenService services.AgentTokenService) AgentTokenHandler {
+	return &agentTokenHandler{
+		agentTokenService: agentTokenService,
+	}
+}
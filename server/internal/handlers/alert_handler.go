@@ -16,6 +16,9 @@ type AlertHandler interface {
 	DeleteAlert(c *gin.Context)
 	ActivateAlert(c *gin.Context)
 	DeactivateAlert(c *gin.Context)
+	GetAlertState(c *gin.Context)
+	GetAlertHistory(c *gin.Context)
+	GetAlertChannels(c *gin.Context)
 }
 
 type alertHandler struct {
@@ -127,6 +130,51 @@ func (a *alertHandler) UpdateAlert(c *gin.Context) {
 	c.JSON(200, gin.H{"data": alert})
 }
 
+// GetAlertState implements AlertHandler.
+func (a *alertHandler) GetAlertState(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	state, err := a.alertService.GetAlertState(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": state})
+}
+
+// GetAlertHistory implements AlertHandler.
+func (a *alertHandler) GetAlertHistory(c *gin.Context) {
+	query := dto.AlertHistoryQuery{}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	events, err := a.alertService.GetAlertHistory(query)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": events})
+}
+
+// GetAlertChannels implements AlertHandler.
+func (a *alertHandler) GetAlertChannels(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	channels, err := a.alertService.GetAlertChannels(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": channels})
+}
+
 func NewAlertHandler(alertService services.AlertService) AlertHandler {
 	return &alertHandler{
 		alertService: alertService,
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type BackupHandler interface {
+	CreateBackup(c *gin.Context)
+	ListBackups(c *gin.Context)
+	SetSchedule(c *gin.Context)
+	GetBackup(c *gin.Context)
+	DownloadBackup(c *gin.Context)
+	DeleteBackup(c *gin.Context)
+	RestoreBackup(c *gin.Context)
+	ListRestores(c *gin.Context)
+}
+
+type backupHandler struct {
+	backupService services.BackupService
+}
+
+// CreateBackup handles POST /api/projects/:id/backups
+func (h *backupHandler) CreateBackup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	backup, err := h.backupService.CreateBackup(c.Param("id"), userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start backup",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, backup)
+}
+
+// ListBackups handles GET /api/projects/:id/backups
+func (h *backupHandler) ListBackups(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	backups, err := h.backupService.GetBackups(c.Param("id"), int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list backups",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   backups,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// SetSchedule handles PUT /api/projects/:id/backup-schedule
+func (h *backupHandler) SetSchedule(c *gin.Context) {
+	var req dto.BackupScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	project, err := h.backupService.SetSchedule(c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "project not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Project not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update backup schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// GetBackup handles GET /api/backups/:id
+func (h *backupHandler) GetBackup(c *gin.Context) {
+	id, ok := backupID(c)
+	if !ok {
+		return
+	}
+
+	backup, err := h.backupService.GetBackup(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Backup not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, backup)
+}
+
+// DownloadBackup handles GET /api/backups/:id/download
+func (h *backupHandler) DownloadBackup(c *gin.Context) {
+	id, ok := backupID(c)
+	if !ok {
+		return
+	}
+
+	path, name, err := h.backupService.GetBackupFile(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Backup not available",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.FileAttachment(path, name)
+}
+
+// DeleteBackup handles DELETE /api/backups/:id
+func (h *backupHandler) DeleteBackup(c *gin.Context) {
+	id, ok := backupID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.backupService.DeleteBackup(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to delete backup",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Backup deleted successfully",
+	})
+}
+
+// RestoreBackup handles POST /api/backups/:id/restore
+func (h *backupHandler) RestoreBackup(c *gin.Context) {
+	id, ok := backupID(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	var req dto.RestoreBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	restore, err := h.backupService.RestoreBackup(id, userId, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start restore",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, restore)
+}
+
+// ListRestores handles GET /api/backups/:id/restores
+func (h *backupHandler) ListRestores(c *gin.Context) {
+	id, ok := backupID(c)
+	if !ok {
+		return
+	}
+
+	restores, err := h.backupService.GetRestores(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list restores",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": restores,
+	})
+}
+
+func backupID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid backup ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func NewBackupHandler(backupService services.BackupService) BackupHandler {
+	return &backupHandler{
+		backupService: backupService,
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type CertificateHandler interface {
+	GetCACertificate(c *gin.Context)
+	IssueNodeCertificate(c *gin.Context)
+	GetNodeCertificates(c *gin.Context)
+	RevokeCertificate(c *gin.Context)
+}
+
+type certificateHandler struct {
+	certificateService services.CertificateService
+}
+
+// GetCACertificate implements CertificateHandler.
+func (h *certificateHandler) GetCACertificate(c *gin.Context) {
+	caPEM, err := h.certificateService.GetCACertificate()
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": gin.H{"ca_pem": caPEM}})
+}
+
+// IssueNodeCertificate implements CertificateHandler.
+func (h *certificateHandler) IssueNodeCertificate(c *gin.Context) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	form := dto.AgentCertificateIssueDto{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&form); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	cert, err := h.certificateService.IssueNodeCertificate(int32(nodeId), form.ValidDays)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": cert})
+}
+
+// GetNodeCertificates implements CertificateHandler.
+func (h *certificateHandler) GetNodeCertificates(c *gin.Context) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	certs, err := h.certificateService.GetNodeCertificates(int32(nodeId))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": certs})
+}
+
+// RevokeCertificate implements CertificateHandler.
+func (h *certificateHandler) RevokeCertificate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	err = h.certificateService.RevokeCertificate(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": "Certificate revoked"})
+}
+
+func NewCertificateHandler(certificateService services.CertificateService) CertificateHandler {
+	return &certificateHandler{
+		certificateService: certificateService,
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type CommandHandler interface {
+	SendCommand(c *gin.Context)
+	ExecWSHandler(c *gin.Context)
+	AttachExecutionWSHandler(c *gin.Context)
+	GetExecutions(c *gin.Context)
+	GetExecution(c *gin.Context)
+	RunProjectCommand(c *gin.Context)
+	GetProjectCommandRuns(c *gin.Context)
+}
+
+type commandHandler struct {
+	commandService services.CommandService
+}
+
+// SendCommand implements CommandHandler.
+func (h *commandHandler) SendCommand(c *gin.Context) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	form := dto.NodeCommandDto{}
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.commandService.SendCommand(int32(nodeId), form)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": result})
+}
+
+var execUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkDashboardOrigin,
+}
+
+// ExecWSHandler implements CommandHandler.
+// The client sends one ExecCommandDto and receives the output as ExecEvent messages.
+func (h *commandHandler) ExecWSHandler(c *gin.Context) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	conn, err := execUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	form := dto.ExecCommandDto{}
+	if err := form.FromBytes(message); err != nil {
+		conn.WriteJSON(gin.H{"type": "error", "error": err.Error()})
+		return
+	}
+	executionId, err := h.commandService.StartExecution(int32(nodeId), userId, form)
+	if err != nil {
+		conn.WriteJSON(gin.H{"type": "error", "error": err.Error()})
+		return
+	}
+	h.streamExecution(conn, executionId)
+}
+
+// AttachExecutionWSHandler implements CommandHandler.
+// It streams a running execution from where it is, or replays a finished one.
+func (h *commandHandler) AttachExecutionWSHandler(c *gin.Context) {
+	executionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	conn, err := execUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer conn.Close()
+	h.streamExecution(conn, executionId)
+}
+
+func (h *commandHandler) streamExecution(conn *websocket.Conn, executionId int64) {
+	output, events, cancel, err := h.commandService.AttachExecution(executionId)
+	if err != nil {
+		conn.WriteJSON(gin.H{"type": "error", "error": err.Error()})
+		return
+	}
+	defer cancel()
+
+	// stop streaming when the browser goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = conn.WriteJSON(dto.ExecStartedDto{
+		Type:        "started",
+		ExecutionID: executionId,
+		Output:      output,
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	}
+}
+
+// GetExecutions implements CommandHandler.
+func (h *commandHandler) GetExecutions(c *gin.Context) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	executions, err := h.commandService.GetExecutions(int32(nodeId), int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": executions})
+}
+
+// GetExecution implements CommandHandler.
+func (h *commandHandler) GetExecution(c *gin.Context) {
+	executionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	execution, err := h.commandService.GetExecution(executionId)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": execution})
+}
+
+// RunProjectCommand implements CommandHandler.
+// The output is streamed from /executions/:id/ws with the returned execution id.
+func (h *commandHandler) RunProjectCommand(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	executionId, err := h.commandService.RunProjectCommand(c.Param("id"), c.Param("name"), userId)
+	if errors.Is(err, services.ErrProjectCommandRunning) {
+		c.JSON(409, gin.H{"error": err.Error(), "execution_id": executionId})
+		return
+	}
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(202, gin.H{"data": gin.H{"execution_id": executionId}})
+}
+
+// GetProjectCommandRuns implements CommandHandler.
+func (h *commandHandler) GetProjectCommandRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	executions, err := h.commandService.GetProjectCommandRuns(c.Param("id"), int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": executions})
+}
+
+func NewCommandHandler(commandService services.CommandService) CommandHandler {
+	return &commandHandler{
+		commandService: commandService,
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type CronHandler interface {
+	CreateCronJob(c *gin.Context)
+	ListCronJobs(c *gin.Context)
+	GetCronJob(c *gin.Context)
+	UpdateCronJob(c *gin.Context)
+	DeleteCronJob(c *gin.Context)
+	ListCronRuns(c *gin.Context)
+}
+
+type cronHandler struct {
+	cronService services.CronService
+}
+
+// CreateCronJob handles POST /api/cron
+func (h *cronHandler) CreateCronJob(c *gin.Context) {
+	var req dto.CronJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, err := h.cronService.CreateCronJob(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to create cron job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, job)
+}
+
+// ListCronJobs handles GET /api/cron, optionally filtered with ?node_id=
+func (h *cronHandler) ListCronJobs(c *gin.Context) {
+	limit, offset := cronPagination(c)
+
+	nodeId, err := strconv.Atoi(c.DefaultQuery("node_id", "0"))
+	if err != nil || nodeId < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid node ID",
+		})
+		return
+	}
+
+	jobs, err := h.cronService.GetCronJobs(int32(nodeId), int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list cron jobs",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   jobs,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetCronJob handles GET /api/cron/:id
+func (h *cronHandler) GetCronJob(c *gin.Context) {
+	id, ok := cronJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.cronService.GetCronJob(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Cron job not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// UpdateCronJob handles PUT /api/cron/:id
+func (h *cronHandler) UpdateCronJob(c *gin.Context) {
+	id, ok := cronJobID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CronJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, err := h.cronService.UpdateCronJob(id, req)
+	if err != nil {
+		if err.Error() == "cron job not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Cron job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to update cron job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DeleteCronJob handles DELETE /api/cron/:id
+func (h *cronHandler) DeleteCronJob(c *gin.Context) {
+	id, ok := cronJobID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cronService.DeleteCronJob(id); err != nil {
+		if err.Error() == "cron job not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Cron job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete cron job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cron job deleted successfully",
+	})
+}
+
+// ListCronRuns handles GET /api/cron/:id/runs
+func (h *cronHandler) ListCronRuns(c *gin.Context) {
+	id, ok := cronJobID(c)
+	if !ok {
+		return
+	}
+	limit, offset := cronPagination(c)
+
+	runs, err := h.cronService.GetCronRuns(id, int32(limit), int32(offset))
+	if err != nil {
+		if err.Error() == "cron job not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Cron job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list cron runs",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   runs,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func cronJobID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid cron job ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func cronPagination(c *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func NewCronHandler(cronService services.CronService) CronHandler {
+	return &cronHandler{
+		cronService: cronService,
+	}
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sanda0/vps_pilot/internal/services"
@@ -15,12 +17,18 @@ type GitHubHandler interface {
 	GetRepos(c *gin.Context)
 	GetStatus(c *gin.Context)
 	DeleteToken(c *gin.Context)
+	Webhook(c *gin.Context)
+	GetWebhookEvents(c *gin.Context)
 }
 
 type githubHandler struct {
-	userService services.UserService
+	userService    services.UserService
+	webhookService services.GitHubWebhookService
 }
 
+// maxWebhookPayload is the largest payload GitHub sends
+const maxWebhookPayload = 25 << 20
+
 type GitHubRepo struct {
 	ID            int64  `json:"id"`
 	Name          string `json:"name"`
@@ -34,9 +42,10 @@ type GitHubRepo struct {
 	UpdatedAt     string `json:"updated_at"`
 }
 
-func NewGitHubHandler(userService services.UserService) GitHubHandler {
+func NewGitHubHandler(userService services.UserService, webhookService services.GitHubWebhookService) GitHubHandler {
 	return &githubHandler{
-		userService: userService,
+		userService:    userService,
+		webhookService: webhookService,
 	}
 }
 
@@ -165,6 +174,67 @@ func (h *githubHandler) DeleteToken(c *gin.Context) {
 	})
 }
 
+// Webhook receives GitHub deliveries. It is public, each delivery is
+// authenticated with the X-Hub-Signature-256 of the matching project's secret.
+func (h *githubHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookPayload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read payload",
+		})
+		return
+	}
+
+	events, err := h.webhookService.HandleDelivery(
+		c.GetHeader("X-GitHub-Event"),
+		c.GetHeader("X-GitHub-Delivery"),
+		c.GetHeader("X-Hub-Signature-256"),
+		body,
+	)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid signature",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to handle webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+	})
+}
+
+// GetWebhookEvents lists logged webhook deliveries, optionally for one project
+func (h *githubHandler) GetWebhookEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	events, err := h.webhookService.GetEvents(c.Query("project_id"), int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get webhook events",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+	})
+}
+
 // fetchRepos fetches repositories from GitHub API
 func (h *githubHandler) fetchRepos(token string) ([]GitHubRepo, error) {
 	client := &http.Client{}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type LogHandler interface {
+	ListProjectLogFiles(c *gin.Context)
+	TailProjectLog(c *gin.Context)
+	FollowProjectLogWSHandler(c *gin.Context)
+	ListNodeLogFiles(c *gin.Context)
+	AddNodeLogFile(c *gin.Context)
+	DeleteNodeLogFile(c *gin.Context)
+	TailNodeLog(c *gin.Context)
+	FollowNodeLogWSHandler(c *gin.Context)
+}
+
+type logHandler struct {
+	logService services.LogService
+}
+
+// ListProjectLogFiles handles GET /api/projects/:id/logs
+func (h *logHandler) ListProjectLogFiles(c *gin.Context) {
+	files, err := h.logService.GetProjectLogFiles(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Project not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": files,
+	})
+}
+
+// TailProjectLog handles GET /api/projects/:id/logs/tail?file=&lines=&grep=
+func (h *logHandler) TailProjectLog(c *gin.Context) {
+	var query dto.LogTailQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tail, err := h.logService.TailProjectLog(c.Param("id"), query)
+	if err != nil {
+		logError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tail)
+}
+
+// FollowProjectLogWSHandler handles GET /api/projects/:id/logs/ws?file=&lines=&grep=
+func (h *logHandler) FollowProjectLogWSHandler(c *gin.Context) {
+	projectId := c.Param("id")
+	streamLog(c, func(ctx context.Context, query dto.LogTailQuery, onLines func([]string) error) error {
+		return h.logService.FollowProjectLog(ctx, projectId, query, onLines)
+	})
+}
+
+// ListNodeLogFiles handles GET /api/nodes/:id/log-files
+func (h *logHandler) ListNodeLogFiles(c *gin.Context) {
+	nodeId, ok := logNodeID(c)
+	if !ok {
+		return
+	}
+
+	files, err := h.logService.GetNodeLogFiles(nodeId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list log files",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": files,
+	})
+}
+
+// AddNodeLogFile handles POST /api/nodes/:id/log-files
+func (h *logHandler) AddNodeLogFile(c *gin.Context) {
+	nodeId, ok := logNodeID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.NodeLogFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	file, err := h.logService.AddNodeLogFile(nodeId, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to add log file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, file)
+}
+
+// DeleteNodeLogFile handles DELETE /api/nodes/:id/log-files/:fileId
+func (h *logHandler) DeleteNodeLogFile(c *gin.Context) {
+	nodeId, ok := logNodeID(c)
+	if !ok {
+		return
+	}
+	fileId, err := strconv.ParseInt(c.Param("fileId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid log file ID",
+		})
+		return
+	}
+
+	if err := h.logService.DeleteNodeLogFile(nodeId, fileId); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete log file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Log file removed successfully",
+	})
+}
+
+// TailNodeLog handles GET /api/nodes/:id/logs/tail?file=&lines=&grep=
+func (h *logHandler) TailNodeLog(c *gin.Context) {
+	nodeId, ok := logNodeID(c)
+	if !ok {
+		return
+	}
+	var query dto.LogTailQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tail, err := h.logService.TailNodeLog(nodeId, query)
+	if err != nil {
+		logError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tail)
+}
+
+// FollowNodeLogWSHandler handles GET /api/nodes/:id/logs/ws?file=&lines=&grep=
+func (h *logHandler) FollowNodeLogWSHandler(c *gin.Context) {
+	nodeId, ok := logNodeID(c)
+	if !ok {
+		return
+	}
+	streamLog(c, func(ctx context.Context, query dto.LogTailQuery, onLines func([]string) error) error {
+		return h.logService.FollowNodeLog(ctx, nodeId, query, onLines)
+	})
+}
+
+var logUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkDashboardOrigin,
+}
+
+// streamLog upgrades the request and sends matching lines as LogEventDto messages
+// until the browser goes away or the agent stops reading the file
+func streamLog(c *gin.Context, follow func(ctx context.Context, query dto.LogTailQuery, onLines func([]string) error) error) {
+	var query dto.LogTailQuery
+	queryErr := c.ShouldBindQuery(&query)
+
+	conn, err := logUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	if queryErr != nil {
+		conn.WriteJSON(dto.LogEventDto{Type: "error", Error: queryErr.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = follow(ctx, query, func(lines []string) error {
+		return conn.WriteJSON(dto.LogEventDto{Type: "lines", Lines: lines})
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	end := dto.LogEventDto{Type: "end"}
+	if err != nil {
+		end.Type = "error"
+		end.Error = err.Error()
+	}
+	conn.WriteJSON(end)
+}
+
+func logError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, services.ErrLogFileNotAllowed) {
+		status = http.StatusForbidden
+	} else if err.Error() == "project not found" {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{
+		"error":   "Failed to read log file",
+		"details": err.Error(),
+	})
+}
+
+func logNodeID(c *gin.Context) (int32, bool) {
+	nodeId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid node ID",
+		})
+		return 0, false
+	}
+	return int32(nodeId), true
+}
+
+func NewLogHandler(logService services.LogService) LogHandler {
+	return &logHandler{
+		logService: logService,
+	}
+}
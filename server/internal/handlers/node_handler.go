@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -16,6 +17,11 @@ type NodeHandler interface {
 	GetNodes(c *gin.Context)
 	UpdateName(c *gin.Context)
 	GetNode(c *gin.Context)
+	GetNodeIPHistory(c *gin.Context)
+	GetNodeSysInfoHistory(c *gin.Context)
+	ScanProjects(c *gin.Context)
+	GetNodeDisks(c *gin.Context)
+	GetNodeDiskStats(c *gin.Context)
 	SystemStatWSHandler(c *gin.Context)
 }
 
@@ -87,16 +93,106 @@ func (n *nodeHandler) GetNode(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	var lastSeen *time.Time
+	if node.LastSeenAt.Valid {
+		t := time.Unix(node.LastSeenAt.Int64, 0)
+		lastSeen = &t
+	}
 	c.JSON(200, gin.H{"data": dto.NodeDto{
-		ID:     int32(node.ID),
-		Name:   node.Name.String,
-		Ip:     node.Ip,
-		Memory: node.TotalMemory.Float64,
-		Cpus:   int32(node.Cpus.Int64),
+		ID:        int32(node.ID),
+		Name:      node.Name.String,
+		Ip:        node.Ip,
+		MachineID: node.MachineID.String,
+		Status:    node.Status,
+		LastSeen:  lastSeen,
+		Memory:    node.TotalMemory.Float64,
+		Cpus:      int32(node.Cpus.Int64),
 	}})
 
 }
 
+func (n *nodeHandler) GetNodeDisks(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	disks, err := n.nodeService.GetNodeDisks(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": disks})
+}
+
+// GetNodeDiskStats returns the per-mount disk series, time_range is in seconds (default 1 hour)
+func (n *nodeHandler) GetNodeDiskStats(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	timeRangeStr := c.DefaultQuery("time_range", "3600")
+	timeRange, err := strconv.ParseInt(timeRangeStr, 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	stats, err := n.nodeService.GetNodeDiskStats(int32(id), timeRange)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": stats})
+}
+
+func (n *nodeHandler) GetNodeSysInfoHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	history, err := n.nodeService.GetNodeSysInfoHistory(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": history})
+}
+
+func (n *nodeHandler) ScanProjects(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	err = n.nodeService.ScanProjects(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": "scan requested"})
+}
+
+func (n *nodeHandler) GetNodeIPHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	history, err := n.nodeService.GetNodeIPHistory(int32(id))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": history})
+}
+
 // UpdateName implements NodeHandler.
 func (n *nodeHandler) UpdateName(c *gin.Context) {
 	form := dto.NodeNameUpdateDto{}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+type NotificationChannelHandler interface {
+	CreateChannel(c *gin.Context)
+	ListChannels(c *gin.Context)
+	GetChannel(c *gin.Context)
+	UpdateChannel(c *gin.Context)
+	DeleteChannel(c *gin.Context)
+	TestChannel(c *gin.Context)
+	ListChannelTypes(c *gin.Context)
+	ListDeliveries(c *gin.Context)
+}
+
+type notificationChannelHandler struct {
+	channelService services.NotificationChannelService
+}
+
+// CreateChannel handles POST /api/notification-channels
+func (h *notificationChannelHandler) CreateChannel(c *gin.Context) {
+	var req dto.NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.channelService.CreateChannel(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to create channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, channel)
+}
+
+// ListChannels handles GET /api/notification-channels
+func (h *notificationChannelHandler) ListChannels(c *gin.Context) {
+	channels, err := h.channelService.GetChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list channels",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": channels,
+	})
+}
+
+// GetChannel handles GET /api/notification-channels/:id
+func (h *notificationChannelHandler) GetChannel(c *gin.Context) {
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+
+	channel, err := h.channelService.GetChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Channel not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+// UpdateChannel handles PUT /api/notification-channels/:id
+func (h *notificationChannelHandler) UpdateChannel(c *gin.Context) {
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.channelService.UpdateChannel(id, req)
+	if err != nil {
+		if err.Error() == "channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Channel not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to update channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+// DeleteChannel handles DELETE /api/notification-channels/:id
+func (h *notificationChannelHandler) DeleteChannel(c *gin.Context) {
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.channelService.DeleteChannel(id); err != nil {
+		if err.Error() == "channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Channel not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to delete channel",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Channel deleted successfully",
+	})
+}
+
+// TestChannel handles POST /api/notification-channels/:id/test
+func (h *notificationChannelHandler) TestChannel(c *gin.Context) {
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.channelService.TestChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Channel not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// ListChannelTypes handles GET /api/notification-channels/types
+func (h *notificationChannelHandler) ListChannelTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.channelService.GetChannelTypes(),
+	})
+}
+
+// ListDeliveries handles GET /api/notification-channels/:id/deliveries
+func (h *notificationChannelHandler) ListDeliveries(c *gin.Context) {
+	id, ok := channelID(c)
+	if !ok {
+		return
+	}
+
+	var query dto.WebhookDeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	deliveries, err := h.channelService.GetDeliveries(id, query)
+	if err != nil {
+		if err.Error() == "channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Channel not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list deliveries",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": deliveries,
+	})
+}
+
+func channelID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid channel ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func NewNotificationChannelHandler(channelService services.NotificationChannelService) NotificationChannelHandler {
+	return &notificationChannelHandler{
+		channelService: channelService,
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -16,6 +18,11 @@ type ProjectHandler interface {
 	ListProjectsByNode(c *gin.Context)
 	UpdateProject(c *gin.Context)
 	DeleteProject(c *gin.Context)
+	RotateWebhookSecret(c *gin.Context)
+	DeployProject(c *gin.Context)
+	RollbackProject(c *gin.Context)
+	ListDeployments(c *gin.Context)
+	GetDeployment(c *gin.Context)
 }
 
 type projectHandler struct {
@@ -41,6 +48,13 @@ func (h *projectHandler) CreateProject(c *gin.Context) {
 	}
 
 	project, err := h.projectService.CreateProject(&req)
+	if errors.Is(err, services.ErrInvalidDeployPath) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create project",
@@ -183,6 +197,13 @@ func (h *projectHandler) UpdateProject(c *gin.Context) {
 	}
 
 	project, err := h.projectService.UpdateProject(id, &req)
+	if errors.Is(err, services.ErrInvalidDeployPath) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		if err.Error() == "project not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -223,3 +244,141 @@ func (h *projectHandler) DeleteProject(c *gin.Context) {
 		"message": "Project deleted successfully",
 	})
 }
+
+// RotateWebhookSecret handles POST /api/projects/:id/webhook-secret
+func (h *projectHandler) RotateWebhookSecret(c *gin.Context) {
+	id := c.Param("id")
+
+	project, err := h.projectService.RotateWebhookSecret(id)
+	if err != nil {
+		if err.Error() == "project not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Project not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to rotate webhook secret",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// DeployProject handles POST /api/projects/:id/deploy
+func (h *projectHandler) DeployProject(c *gin.Context) {
+	id := c.Param("id")
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	deployment, err := h.projectService.DeployProject(id, userId)
+	if err != nil {
+		if err.Error() == "project not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Project not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start deployment",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deployment)
+}
+
+// RollbackProject handles POST /api/projects/:id/rollback
+func (h *projectHandler) RollbackProject(c *gin.Context) {
+	id := c.Param("id")
+	userID, _ := c.Get("user_id")
+	userId, _ := userID.(int32)
+
+	var req dto.RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	deployment, err := h.projectService.RollbackProject(id, req.DeploymentID, userId)
+	if err != nil {
+		if err.Error() == "project not found" || err.Error() == "deployment not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to start rollback",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deployment)
+}
+
+// ListDeployments handles GET /api/projects/:id/deployments
+func (h *projectHandler) ListDeployments(c *gin.Context) {
+	id := c.Param("id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	deployments, err := h.projectService.ListDeployments(id, int32(limit), int32(offset))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list deployments",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   deployments,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetDeployment handles GET /api/deployments/:id
+func (h *projectHandler) GetDeployment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid deployment ID",
+		})
+		return
+	}
+
+	deployment, err := h.projectService.GetDeployment(id)
+	if err != nil {
+		if err.Error() == "deployment not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Deployment not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get deployment",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// checkDashboardOrigin accepts WebSocket upgrades from the dashboard only. That is
+// the server's own host, which serves the embedded dashboard, or an origin listed in
+// DASHBOARD_ORIGINS (separated by ","), such as the dev server. Requests without an
+// Origin header do not come from a browser and are accepted.
+func checkDashboardOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range strings.Split(os.Getenv("DASHBOARD_ORIGINS"), ",") {
+		if allowed = strings.TrimRight(strings.TrimSpace(allowed), "/"); allowed != "" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+type AgentTokenService interface {
+	CreateToken(form dto.AgentTokenCreateDto) (*dto.AgentTokenDto, error)
+	GetTokens(limit int32, offset int32) ([]dto.AgentTokenDto, error)
+	RevokeToken(tokenId int32) error
+}
+
+type agentTokenService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// CreateToken implements AgentTokenService.
+func (a *agentTokenService) CreateToken(form dto.AgentTokenCreateDto) (*dto.AgentTokenDto, error) {
+	if form.NodeID != 0 {
+		if _, err := a.repo.Queries.GetNode(a.ctx, int64(form.NodeID)); err != nil {
+			return nil, fmt.Errorf("node not found: %w", err)
+		}
+	}
+
+	token, err := utils.GenerateAgentToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	var expiresAt sql.NullInt64
+	if form.ExpiresIn > 0 {
+		expiresAt = sql.NullInt64{
+			Int64: time.Now().Add(time.Duration(form.ExpiresIn) * time.Hour).Unix(),
+			Valid: true,
+		}
+	}
+
+	row, err := a.repo.Queries.CreateAgentToken(a.ctx, db.CreateAgentTokenParams{
+		Name:      form.Name,
+		TokenHash: utils.HashAgentToken(token),
+		NodeID: sql.NullInt64{
+			Int64: int64(form.NodeID),
+			Valid: form.NodeID != 0,
+		},
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	result := &dto.AgentTokenDto{}
+	result.Convert(&row)
+	result.Token = token
+	return result, nil
+}
+
+// GetTokens implements AgentTokenService.
+func (a *agentTokenService) GetTokens(limit int32, offset int32) ([]dto.AgentTokenDto, error) {
+	rows, err := a.repo.Queries.ListAgentTokens(a.ctx, db.ListAgentTokensParams{
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []dto.AgentTokenDto{}
+	for _, row := range rows {
+		token := dto.AgentTokenDto{}
+		token.Convert(&row)
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// RevokeToken implements AgentTokenService.
+func (a *agentTokenService) RevokeToken(tokenId int32) error {
+	rowsAffected, err := a.repo.Queries.RevokeAgentToken(a.ctx, int64(tokenId))
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("token not found or already revoked")
+	}
+	tcpserver.DisconnectAgentToken(int64(tokenId))
+	return nil
+}
+
+func NewAgentTokenService(ctx context.Context, repo *db.Repo) AgentTokenService {
+	return &agentTokenService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
 )
 
 type AlertService interface {
@@ -16,6 +18,9 @@ type AlertService interface {
 	DeleteAlert(alertId int32) error
 	ActivateAlert(alertId int32) error
 	DeactivateAlert(alertId int32) error
+	GetAlertState(alertId int32) (*db.AlertState, error)
+	GetAlertHistory(query dto.AlertHistoryQuery) ([]*dto.AlertEventDto, error)
+	GetAlertChannels(alertId int32) ([]*dto.NotificationChannelDto, error)
 }
 
 type alertService struct {
@@ -40,6 +45,9 @@ func (a *alertService) CreateAlert(dto dto.AlertDto) (*db.Alert, error) {
 	// } else if dto.Metric == "net" {
 	// 	metric = "net"
 	// }
+	if err := checkChannels(a.ctx, a.repo, dto.ChannelIDs); err != nil {
+		return nil, err
+	}
 
 	alert, err := a.repo.Queries.CreateAlert(a.ctx, db.CreateAlertParams{
 		NodeID:   int64(dto.NodeID),
@@ -57,32 +65,48 @@ func (a *alertService) CreateAlert(dto dto.AlertDto) (*db.Alert, error) {
 			Float64: dto.NetSendThreshold,
 			Valid:   true,
 		},
-		Email: sql.NullString{String: dto.Email, Valid: true},
 		IsActive: sql.NullInt64{
 			Int64: boolToInt64(dto.Enabled),
 			Valid: true,
 		},
-		SlackWebhook:   sql.NullString{String: dto.Slack, Valid: true},
-		DiscordWebhook: sql.NullString{String: dto.Discord, Valid: true},
+		Mountpoint:     sql.NullString{String: dto.Mountpoint, Valid: dto.Mountpoint != ""},
+		Aggregation:    alertAggregation(dto.Aggregation),
+		ResendInterval: int64(dto.ResendInterval),
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := a.setChannels(alert.ID, dto.ChannelIDs); err != nil {
+		return nil, err
+	}
 	return &alert, nil
 }
 
 // DeactivateAlert implements AlertService.
 func (a *alertService) DeactivateAlert(alertId int32) error {
-	err := a.repo.Queries.DeactivateAlert(a.ctx, int64(alertId))
+	alert, err := a.repo.Queries.GetAlert(a.ctx, int64(alertId))
 	if err != nil {
 		return err
 	}
-	return nil
+	err = a.repo.Queries.DeactivateAlert(a.ctx, int64(alertId))
+	if err != nil {
+		return err
+	}
+	// an incident of a disabled rule would never resolve
+	return tcpserver.AlertEvaluator(a.repo).Resolve(a.ctx, alert, "Rule disabled")
 }
 
 // DeleteAlert implements AlertService.
 func (a *alertService) DeleteAlert(alertId int32) error {
-	err := a.repo.Queries.DeleteAlert(a.ctx, int64(alertId))
+	alert, err := a.repo.Queries.GetAlert(a.ctx, int64(alertId))
+	if err != nil {
+		return err
+	}
+	// resolve while the rule still has its channels
+	if err := tcpserver.AlertEvaluator(a.repo).Resolve(a.ctx, alert, "Rule deleted"); err != nil {
+		return err
+	}
+	err = a.repo.Queries.DeleteAlert(a.ctx, int64(alertId))
 	if err != nil {
 		return err
 	}
@@ -114,6 +138,21 @@ func (a *alertService) GetAlerts(nodeId int32, limit int32, offset int32) ([]db.
 
 // UpdateAlert implements AlertService.
 func (a *alertService) UpdateAlert(dto dto.AlertUpdateDto) (*db.Alert, error) {
+	existing, err := a.repo.Queries.GetAlert(a.ctx, int64(dto.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkChannels(a.ctx, a.repo, dto.ChannelIDs); err != nil {
+		return nil, err
+	}
+
+	// the state belongs to what the rule watched, a threshold change resolves on the next sample instead.
+	// The incident is resolved before the update, on the node and channels it fired on.
+	if existing.NodeID != int64(dto.NodeID) || existing.Metric != dto.Metric || !dto.Enabled {
+		if err := tcpserver.AlertEvaluator(a.repo).Resolve(a.ctx, existing, "Rule changed"); err != nil {
+			return nil, err
+		}
+	}
 
 	alert, err := a.repo.Queries.UpdateAlert(a.ctx, db.UpdateAlertParams{
 		ID:       int64(dto.ID),
@@ -132,20 +171,120 @@ func (a *alertService) UpdateAlert(dto dto.AlertUpdateDto) (*db.Alert, error) {
 			Float64: dto.NetSendThreshold,
 			Valid:   true,
 		},
-		Email: sql.NullString{String: dto.Email, Valid: true},
 		IsActive: sql.NullInt64{
 			Int64: boolToInt64(dto.Enabled),
 			Valid: true,
 		},
-		SlackWebhook:   sql.NullString{String: dto.Slack, Valid: true},
-		DiscordWebhook: sql.NullString{String: dto.Discord, Valid: true},
+		Mountpoint:     sql.NullString{String: dto.Mountpoint, Valid: dto.Mountpoint != ""},
+		Aggregation:    alertAggregation(dto.Aggregation),
+		ResendInterval: int64(dto.ResendInterval),
 	})
 	if err != nil {
 		return nil, err
 	}
+	if dto.ChannelIDs != nil {
+		if err := a.setChannels(alert.ID, dto.ChannelIDs); err != nil {
+			return nil, err
+		}
+	}
 	return &alert, nil
 }
 
+// GetAlertState implements AlertService.
+// Rules that never breached are reported as ok.
+func (a *alertService) GetAlertState(alertId int32) (*db.AlertState, error) {
+	if _, err := a.repo.Queries.GetAlert(a.ctx, int64(alertId)); err != nil {
+		return nil, err
+	}
+	state, err := tcpserver.AlertEvaluator(a.repo).State(a.ctx, int64(alertId))
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// GetAlertHistory implements AlertService.
+// Events are returned newest first, 50 at a time unless a limit is given.
+func (a *alertService) GetAlertHistory(query dto.AlertHistoryQuery) ([]*dto.AlertEventDto, error) {
+	params := db.ListAlertEventsParams{
+		NodeID:  query.NodeID,
+		AlertID: query.AlertID,
+		Metric:  query.Metric,
+		State:   query.State,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}
+	if params.Limit == 0 {
+		params.Limit = 50
+	}
+	if !query.From.IsZero() {
+		params.From = query.From.Unix()
+	}
+	if !query.To.IsZero() {
+		params.To = query.To.Unix()
+	}
+
+	events, err := a.repo.Queries.ListAlertEvents(a.ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.AlertEventDto, len(events))
+	for i, event := range events {
+		dtos[i] = dto.ConvertToAlertEventDto(&event)
+	}
+	return dtos, nil
+}
+
+// GetAlertChannels implements AlertService.
+func (a *alertService) GetAlertChannels(alertId int32) ([]*dto.NotificationChannelDto, error) {
+	if _, err := a.repo.Queries.GetAlert(a.ctx, int64(alertId)); err != nil {
+		return nil, err
+	}
+	channels, err := a.repo.Queries.ListAlertChannels(a.ctx, int64(alertId))
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.NotificationChannelDto, len(channels))
+	for i, channel := range channels {
+		dtos[i] = dto.ConvertToNotificationChannelDto(&channel)
+	}
+	return dtos, nil
+}
+
+// checkChannels fails on the first channel that does not exist
+func checkChannels(ctx context.Context, repo *db.Repo, channelIds []int64) error {
+	for _, id := range channelIds {
+		if _, err := repo.Queries.GetNotificationChannel(ctx, id); err != nil {
+			return fmt.Errorf("notification channel %d not found", id)
+		}
+	}
+	return nil
+}
+
+// setChannels replaces the notification channels of a rule
+func (a *alertService) setChannels(alertId int64, channelIds []int64) error {
+	tx, err := a.repo.OperationalDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := a.repo.Queries.WithTx(tx)
+	if err := queries.ClearAlertChannels(a.ctx, alertId); err != nil {
+		return err
+	}
+	for _, id := range channelIds {
+		err := queries.AddAlertChannel(a.ctx, db.AddAlertChannelParams{
+			AlertID:   alertId,
+			ChannelID: id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func NewAlertService(ctx context.Context, repo *db.Repo) AlertService {
 	return &alertService{
 		repo: repo,
@@ -153,6 +292,14 @@ func NewAlertService(ctx context.Context, repo *db.Repo) AlertService {
 	}
 }
 
+// alertAggregation defaults to requiring every sample in the window to breach
+func alertAggregation(aggregation string) string {
+	if aggregation == "" {
+		return "all"
+	}
+	return aggregation
+}
+
 // Helper function to convert bool to int64 for SQLite
 func boolToInt64(b bool) int64 {
 	if b {
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+const backupSchedulerInterval = time.Minute
+
+// busyBackupProjects holds the projects with a backup or restore in progress
+var (
+	busyBackupProjects   = make(map[string]bool)
+	busyBackupProjectsMu sync.Mutex
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type BackupService interface {
+	CreateBackup(projectId string, userId int32) (*dto.BackupDto, error)
+	GetBackups(projectId string, limit int32, offset int32) ([]*dto.BackupDto, error)
+	GetBackup(id int64) (*dto.BackupDto, error)
+	GetBackupFile(id int64) (string, string, error)
+	DeleteBackup(id int64) error
+	RestoreBackup(id int64, userId int32, form dto.RestoreBackupRequest) (*dto.BackupRestoreDto, error)
+	GetRestores(backupId int64) ([]*dto.BackupRestoreDto, error)
+	SetSchedule(projectId string, form dto.BackupScheduleRequest) (*dto.ProjectResponse, error)
+}
+
+type backupService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// BackupDir is where backup archives are stored, BACKUP_DIR or ./data/backups
+func BackupDir() string {
+	if dir := os.Getenv("BACKUP_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("data", "backups")
+}
+
+// CreateBackup implements BackupService.
+// The backup runs in the background, poll GetBackup for the outcome.
+func (b *backupService) CreateBackup(projectId string, userId int32) (*dto.BackupDto, error) {
+	project, err := b.repo.Queries.GetProject(b.ctx, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	backup, err := startBackup(b.ctx, b.repo, project, "manual", userId)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertToBackupDto(backup), nil
+}
+
+// GetBackups implements BackupService.
+func (b *backupService) GetBackups(projectId string, limit int32, offset int32) ([]*dto.BackupDto, error) {
+	backups, err := b.repo.Queries.ListBackupsByProject(b.ctx, db.ListBackupsByProjectParams{
+		ProjectID: projectId,
+		Limit:     int64(limit),
+		Offset:    int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.BackupDto, len(backups))
+	for i, backup := range backups {
+		dtos[i] = dto.ConvertToBackupDto(&backup)
+	}
+	return dtos, nil
+}
+
+// GetBackup implements BackupService.
+func (b *backupService) GetBackup(id int64) (*dto.BackupDto, error) {
+	backup, err := b.repo.Queries.GetBackup(b.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("backup not found")
+	}
+	return dto.ConvertToBackupDto(&backup), nil
+}
+
+// GetBackupFile implements BackupService.
+// It returns the path of the archive and the file name to download it as.
+func (b *backupService) GetBackupFile(id int64) (string, string, error) {
+	backup, err := b.repo.Queries.GetBackup(b.ctx, id)
+	if err != nil {
+		return "", "", fmt.Errorf("backup not found")
+	}
+	if backup.Status != "succeeded" {
+		return "", "", fmt.Errorf("backup is %s", backup.Status)
+	}
+	if _, err := os.Stat(backup.FilePath); err != nil {
+		return "", "", fmt.Errorf("backup file is missing: %w", err)
+	}
+	return backup.FilePath, backup.FileName, nil
+}
+
+// DeleteBackup implements BackupService.
+func (b *backupService) DeleteBackup(id int64) error {
+	backup, err := b.repo.Queries.GetBackup(b.ctx, id)
+	if err != nil {
+		return fmt.Errorf("backup not found")
+	}
+	if backup.Status == "running" {
+		return fmt.Errorf("backup is still running")
+	}
+	return removeBackup(b.ctx, b.repo, backup)
+}
+
+// RestoreBackup implements BackupService.
+// The archive is sent to the project's node and unpacked into the project directory.
+func (b *backupService) RestoreBackup(id int64, userId int32, form dto.RestoreBackupRequest) (*dto.BackupRestoreDto, error) {
+	backup, err := b.repo.Queries.GetBackup(b.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("backup not found")
+	}
+	if backup.Status != "succeeded" {
+		return nil, fmt.Errorf("only successful backups can be restored")
+	}
+	project, err := b.repo.Queries.GetProject(b.ctx, backup.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	config, err := projectBackupConfig(project)
+	if err != nil {
+		return nil, err
+	}
+	if !tcpserver.IsNodeConnected(int32(project.NodeID)) {
+		return nil, tcpserver.ErrNodeNotConnected
+	}
+	file, err := os.Open(backup.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("backup file is missing: %w", err)
+	}
+	if !lockBackupProject(project.ID) {
+		file.Close()
+		return nil, fmt.Errorf("a backup or restore is already running for this project")
+	}
+
+	restore, err := b.repo.Queries.CreateBackupRestore(b.ctx, db.CreateBackupRestoreParams{
+		BackupID:        backup.ID,
+		ProjectID:       project.ID,
+		RestoreDatabase: boolToInt64(form.Database),
+		TriggeredBy:     sql.NullInt64{Int64: int64(userId), Valid: userId != 0},
+	})
+	if err != nil {
+		file.Close()
+		unlockBackupProject(project.ID)
+		return nil, fmt.Errorf("failed to record restore: %w", err)
+	}
+
+	go func() {
+		defer unlockBackupProject(project.ID)
+		defer file.Close()
+
+		result, err := tcpserver.RunRestore(b.ctx, int32(project.NodeID), tcpserver.RestoreRequest{
+			Dir:             projectDir(project),
+			Config:          config,
+			Size:            backup.SizeBytes,
+			Sha256:          backup.Sha256.String,
+			RestoreDatabase: form.Database,
+		}, file)
+
+		params := db.FinishBackupRestoreParams{Status: "succeeded", ID: restore.ID}
+		if result != nil {
+			params.Restored = encodeJSONList(result.Restored)
+		}
+		if err != nil {
+			params.Status = "failed"
+			params.Error = sql.NullString{String: err.Error(), Valid: true}
+		}
+		if err := b.repo.Queries.FinishBackupRestore(b.ctx, params); err != nil {
+			fmt.Println("Error finishing restore", err)
+		}
+	}()
+
+	return dto.ConvertToBackupRestoreDto(&restore), nil
+}
+
+// GetRestores implements BackupService.
+func (b *backupService) GetRestores(backupId int64) ([]*dto.BackupRestoreDto, error) {
+	restores, err := b.repo.Queries.ListBackupRestores(b.ctx, backupId)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.BackupRestoreDto, len(restores))
+	for i, restore := range restores {
+		dtos[i] = dto.ConvertToBackupRestoreDto(&restore)
+	}
+	return dtos, nil
+}
+
+// SetSchedule implements BackupService.
+func (b *backupService) SetSchedule(projectId string, form dto.BackupScheduleRequest) (*dto.ProjectResponse, error) {
+	project, err := b.repo.Queries.SetProjectBackupSchedule(b.ctx, db.SetProjectBackupScheduleParams{
+		BackupIntervalHours: int64(form.IntervalHours),
+		BackupRetention:     int64(form.Retention),
+		ID:                  projectId,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+	return dto.ConvertToProjectResponse(&project), nil
+}
+
+// RecoverInterruptedBackups fails backups and restores that were cut off by a server restart
+func RecoverInterruptedBackups(ctx context.Context, repo *db.Repo) {
+	if err := repo.Queries.FailUnfinishedBackups(ctx); err != nil {
+		fmt.Println("Error failing unfinished backups:", err)
+	}
+	if err := repo.Queries.FailUnfinishedBackupRestores(ctx); err != nil {
+		fmt.Println("Error failing unfinished restores:", err)
+	}
+}
+
+// RunBackupScheduler backs up projects whose backup_interval_hours has passed since their last backup
+func RunBackupScheduler(ctx context.Context, repo *db.Repo) {
+	ticker := time.NewTicker(backupSchedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runScheduledBackups(ctx, repo)
+		}
+	}
+}
+
+func runScheduledBackups(ctx context.Context, repo *db.Repo) {
+	projects, err := repo.Queries.ListProjectsWithBackupSchedule(ctx)
+	if err != nil {
+		fmt.Println("Error listing scheduled backups:", err)
+		return
+	}
+	for _, project := range projects {
+		latest, err := repo.Queries.GetLatestBackup(ctx, project.ID)
+		if err == nil && time.Since(time.Unix(latest.StartedAt, 0)) < time.Duration(project.BackupIntervalHours)*time.Hour {
+			continue
+		}
+		if !tcpserver.IsNodeConnected(int32(project.NodeID)) {
+			// retried on the next tick once the node is back
+			continue
+		}
+		if _, err := startBackup(ctx, repo, project, "scheduled", 0); err != nil {
+			fmt.Println("Error starting scheduled backup for", project.Name, err)
+		}
+	}
+}
+
+func startBackup(ctx context.Context, repo *db.Repo, project db.Project, trigger string, userId int32) (*db.Backup, error) {
+	config, err := projectBackupConfig(project)
+	if err != nil {
+		return nil, err
+	}
+	if !tcpserver.IsNodeConnected(int32(project.NodeID)) {
+		return nil, tcpserver.ErrNodeNotConnected
+	}
+
+	name := unsafeFileNameChars.ReplaceAllString(strings.TrimSuffix(config.ZipFileName, ".zip"), "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		name = "backup"
+	}
+	fileName := fmt.Sprintf("%s-%s.zip", name, time.Now().UTC().Format("20060102-150405"))
+	dir := filepath.Join(BackupDir(), project.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if !lockBackupProject(project.ID) {
+		return nil, fmt.Errorf("a backup or restore is already running for this project")
+	}
+	backup, err := repo.Queries.CreateBackup(ctx, db.CreateBackupParams{
+		ProjectID:   project.ID,
+		NodeID:      project.NodeID,
+		TriggerType: trigger,
+		TriggeredBy: sql.NullInt64{Int64: int64(userId), Valid: userId != 0},
+		FileName:    fileName,
+		FilePath:    filepath.Join(dir, fileName),
+	})
+	if err != nil {
+		unlockBackupProject(project.ID)
+		return nil, fmt.Errorf("failed to record backup: %w", err)
+	}
+
+	go func() {
+		defer unlockBackupProject(project.ID)
+		runBackup(ctx, repo, project, backup, config)
+	}()
+	return &backup, nil
+}
+
+// runBackup receives the archive into a .part file that is renamed once it is complete
+func runBackup(ctx context.Context, repo *db.Repo, project db.Project, backup db.Backup, config tcpserver.BackupConfig) {
+	partPath := backup.FilePath + ".part"
+	params := db.FinishBackupParams{Status: "failed", ID: backup.ID}
+
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	var result *tcpserver.BackupResult
+	if err == nil {
+		result, err = tcpserver.RunBackup(ctx, int32(project.NodeID), tcpserver.BackupRequest{
+			Dir:    projectDir(project),
+			Config: config,
+		}, file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err == nil {
+		err = os.Rename(partPath, backup.FilePath)
+	}
+
+	if err != nil {
+		os.Remove(partPath)
+		params.Error = sql.NullString{String: err.Error(), Valid: true}
+	} else {
+		params.Status = "succeeded"
+		params.SizeBytes = result.Size
+		params.Sha256 = sql.NullString{String: result.Sha256, Valid: true}
+		params.Contents = encodeJSONList(result.Contents)
+	}
+	if _, err := repo.Queries.FinishBackup(ctx, params); err != nil {
+		fmt.Println("Error finishing backup", err)
+		return
+	}
+
+	if params.Status == "succeeded" {
+		pruneBackups(ctx, repo, project)
+	}
+}
+
+// pruneBackups removes successful backups beyond the project's backup_retention
+func pruneBackups(ctx context.Context, repo *db.Repo, project db.Project) {
+	backups, err := repo.Queries.ListSucceededBackups(ctx, project.ID)
+	if err != nil {
+		fmt.Println("Error listing backups", err)
+		return
+	}
+	for i, backup := range backups {
+		if int64(i) < project.BackupRetention {
+			continue
+		}
+		if err := removeBackup(ctx, repo, backup); err != nil {
+			fmt.Println("Error removing old backup", err)
+		}
+	}
+}
+
+func removeBackup(ctx context.Context, repo *db.Repo, backup db.Backup) error {
+	if err := os.Remove(backup.FilePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove backup file: %w", err)
+	}
+	return repo.Queries.DeleteBackup(ctx, backup.ID)
+}
+
+func projectBackupConfig(project db.Project) (tcpserver.BackupConfig, error) {
+	config := tcpserver.BackupConfig{}
+	if !project.BackupConfig.Valid || project.BackupConfig.String == "" {
+		return config, fmt.Errorf("project has no backups section in config.vpspilot.json")
+	}
+	if err := json.Unmarshal([]byte(project.BackupConfig.String), &config); err != nil {
+		return config, fmt.Errorf("invalid backup config: %w", err)
+	}
+	return config, nil
+}
+
+// projectDir is the directory project files live in, the live release for projects deployed with releases
+func projectDir(project db.Project) string {
+	if project.CurrentDeploymentID.Valid {
+		return strings.TrimRight(project.DeployPath, "/") + "/current"
+	}
+	return project.DeployPath
+}
+
+func lockBackupProject(projectId string) bool {
+	busyBackupProjectsMu.Lock()
+	defer busyBackupProjectsMu.Unlock()
+	if busyBackupProjects[projectId] {
+		return false
+	}
+	busyBackupProjects[projectId] = true
+	return true
+}
+
+func unlockBackupProject(projectId string) {
+	busyBackupProjectsMu.Lock()
+	delete(busyBackupProjects, projectId)
+	busyBackupProjectsMu.Unlock()
+}
+
+func encodeJSONList(list []string) sql.NullString {
+	if list == nil {
+		return sql.NullString{}
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+func NewBackupService(ctx context.Context, repo *db.Repo) BackupService {
+	return &backupService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+const defaultAgentCertificateDays = 365
+
+type CertificateService interface {
+	GetCACertificate() (string, error)
+	IssueNodeCertificate(nodeId int32, validDays int32) (*dto.AgentCertificateIssuedDto, error)
+	GetNodeCertificates(nodeId int32) ([]dto.AgentCertificateDto, error)
+	RevokeCertificate(certId int32) error
+}
+
+type certificateService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// GetCACertificate implements CertificateService.
+func (c *certificateService) GetCACertificate() (string, error) {
+	ca, err := tcpserver.LoadCertificateAuthority(c.ctx, c.repo)
+	if err != nil {
+		return "", err
+	}
+	return ca.CertPem, nil
+}
+
+// IssueNodeCertificate implements CertificateService.
+func (c *certificateService) IssueNodeCertificate(nodeId int32, validDays int32) (*dto.AgentCertificateIssuedDto, error) {
+	if _, err := c.repo.Queries.GetNode(c.ctx, int64(nodeId)); err != nil {
+		return nil, fmt.Errorf("node not found: %w", err)
+	}
+	if validDays <= 0 {
+		validDays = defaultAgentCertificateDays
+	}
+
+	issued, cert, err := tcpserver.IssueAgentCertificate(c.ctx, c.repo, int64(nodeId), time.Duration(validDays)*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := c.GetCACertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.AgentCertificateIssuedDto{
+		CertPEM: string(issued.CertPEM),
+		KeyPEM:  string(issued.KeyPEM),
+		CaPEM:   caPEM,
+	}
+	result.Convert(cert)
+	return result, nil
+}
+
+// GetNodeCertificates implements CertificateService.
+func (c *certificateService) GetNodeCertificates(nodeId int32) ([]dto.AgentCertificateDto, error) {
+	rows, err := c.repo.Queries.ListAgentCertificatesByNode(c.ctx, int64(nodeId))
+	if err != nil {
+		return nil, err
+	}
+
+	certs := []dto.AgentCertificateDto{}
+	for _, row := range rows {
+		cert := dto.AgentCertificateDto{}
+		cert.Convert(&row)
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// RevokeCertificate implements CertificateService.
+func (c *certificateService) RevokeCertificate(certId int32) error {
+	rowsAffected, err := c.repo.Queries.RevokeAgentCertificate(c.ctx, int64(certId))
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("certificate not found or already revoked")
+	}
+	tcpserver.DisconnectAgentCertificate(int64(certId))
+	return nil
+}
+
+func NewCertificateService(ctx context.Context, repo *db.Repo) CertificateService {
+	return &certificateService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
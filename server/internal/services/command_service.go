@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+const (
+	maxCommandTimeout     = 300  // seconds
+	maxExecTimeout        = 3600 // seconds
+	projectCommandTimeout = 15 * time.Minute
+)
+
+// ErrProjectCommandRunning is returned when the same project command is started twice
+var ErrProjectCommandRunning = errors.New("command is already running for this project")
+
+// runningProjectCommands maps project id + command name to the running execution
+var (
+	runningProjectCommands   = make(map[string]int64)
+	runningProjectCommandsMu sync.Mutex
+)
+
+type CommandService interface {
+	SendCommand(nodeId int32, form dto.NodeCommandDto) (*dto.NodeCommandResultDto, error)
+	StartExecution(nodeId int32, userId int32, form dto.ExecCommandDto) (int64, error)
+	AttachExecution(executionId int64) (string, <-chan tcpserver.ExecEvent, func(), error)
+	GetExecutions(nodeId int32, limit int32, offset int32) ([]db.CommandExecution, error)
+	GetExecution(executionId int64) (*db.CommandExecution, error)
+	RunProjectCommand(projectId string, name string, userId int32) (int64, error)
+	GetProjectCommandRuns(projectId string, limit int32, offset int32) ([]db.CommandExecution, error)
+}
+
+type commandService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// SendCommand implements CommandService.
+func (c *commandService) SendCommand(nodeId int32, form dto.NodeCommandDto) (*dto.NodeCommandResultDto, error) {
+	if _, err := c.repo.Queries.GetNode(c.ctx, int64(nodeId)); err != nil {
+		return nil, fmt.Errorf("node not found: %w", err)
+	}
+	if form.Timeout > maxCommandTimeout {
+		return nil, fmt.Errorf("timeout must be at most %d seconds", maxCommandTimeout)
+	}
+
+	correlationId, result, err := tcpserver.SendCommand(c.ctx, nodeId, tcpserver.Command{
+		Type: form.Type,
+		Args: form.Args,
+	}, time.Duration(form.Timeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.NodeCommandResultDto{
+		CorrelationId: correlationId,
+		Ok:            result.Ok,
+		Output:        result.Output,
+		Error:         result.Error,
+		Data:          result.Data,
+	}, nil
+}
+
+// StartExecution implements CommandService.
+func (c *commandService) StartExecution(nodeId int32, userId int32, form dto.ExecCommandDto) (int64, error) {
+	command := strings.TrimSpace(form.Command)
+	if command == "" {
+		return 0, fmt.Errorf("command is required")
+	}
+	if !tcpserver.ExecAllowed(command) {
+		return 0, tcpserver.ErrCommandNotAllowed
+	}
+	if form.Timeout > maxExecTimeout {
+		return 0, fmt.Errorf("timeout must be at most %d seconds", maxExecTimeout)
+	}
+	if _, err := c.repo.Queries.GetNode(c.ctx, int64(nodeId)); err != nil {
+		return 0, fmt.Errorf("node not found: %w", err)
+	}
+
+	execution, err := tcpserver.StartExecution(c.ctx, c.repo, nodeId, tcpserver.ExecOptions{
+		Command: command,
+		Timeout: time.Duration(form.Timeout) * time.Second,
+		UserId:  userId,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return execution.ID, nil
+}
+
+// AttachExecution implements CommandService.
+func (c *commandService) AttachExecution(executionId int64) (string, <-chan tcpserver.ExecEvent, func(), error) {
+	if execution, ok := tcpserver.GetRunningExecution(executionId); ok {
+		output, events, cancel := execution.Subscribe()
+		return output, events, cancel, nil
+	}
+
+	// finished executions are replayed from the history
+	row, err := c.repo.Queries.GetCommandExecution(c.ctx, executionId)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("execution not found: %w", err)
+	}
+	final := tcpserver.ExecEvent{
+		Type:       "exit",
+		Status:     row.Status,
+		DurationMs: row.DurationMs.Int64,
+		Error:      row.Error.String,
+	}
+	if row.ExitCode.Valid {
+		exitCode := int(row.ExitCode.Int64)
+		final.ExitCode = &exitCode
+	}
+	events := make(chan tcpserver.ExecEvent, 1)
+	events <- final
+	close(events)
+	return row.Output, events, func() {}, nil
+}
+
+// GetExecutions implements CommandService.
+func (c *commandService) GetExecutions(nodeId int32, limit int32, offset int32) ([]db.CommandExecution, error) {
+	executions, err := c.repo.Queries.ListCommandExecutionsByNode(c.ctx, db.ListCommandExecutionsByNodeParams{
+		NodeID: int64(nodeId),
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+// GetExecution implements CommandService.
+func (c *commandService) GetExecution(executionId int64) (*db.CommandExecution, error) {
+	execution, err := c.repo.Queries.GetCommandExecution(c.ctx, executionId)
+	if err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// RunProjectCommand implements CommandService.
+// Only commands declared in the project's config.vpspilot.json can run, in the live
+// release when the project is deployed with releases and in deploy_path otherwise.
+func (c *commandService) RunProjectCommand(projectId string, name string, userId int32) (int64, error) {
+	project, err := c.repo.Queries.GetProject(c.ctx, projectId)
+	if err != nil {
+		return 0, fmt.Errorf("project not found")
+	}
+
+	command := ""
+	for _, declared := range dto.ParseProjectCommands(project.Commands) {
+		if declared.Name == name {
+			command = declared.Command
+			break
+		}
+	}
+	if command == "" {
+		return 0, fmt.Errorf("command %q is not declared in the project config", name)
+	}
+
+	key := project.ID + "\x00" + name
+	runningProjectCommandsMu.Lock()
+	defer runningProjectCommandsMu.Unlock()
+	if executionId, ok := runningProjectCommands[key]; ok {
+		return executionId, ErrProjectCommandRunning
+	}
+
+	execution, err := tcpserver.StartExecution(c.ctx, c.repo, int32(project.NodeID), tcpserver.ExecOptions{
+		Command:     command,
+		Dir:         projectDir(project),
+		Timeout:     projectCommandTimeout,
+		UserId:      userId,
+		ProjectID:   project.ID,
+		CommandName: name,
+	})
+	if err != nil {
+		return 0, err
+	}
+	runningProjectCommands[key] = execution.ID
+
+	go func() {
+		execution.Wait()
+		runningProjectCommandsMu.Lock()
+		delete(runningProjectCommands, key)
+		runningProjectCommandsMu.Unlock()
+	}()
+	return execution.ID, nil
+}
+
+// GetProjectCommandRuns implements CommandService.
+func (c *commandService) GetProjectCommandRuns(projectId string, limit int32, offset int32) ([]db.CommandExecution, error) {
+	executions, err := c.repo.Queries.ListCommandExecutionsByProject(c.ctx, db.ListCommandExecutionsByProjectParams{
+		ProjectID: sql.NullString{String: projectId, Valid: true},
+		Limit:     int64(limit),
+		Offset:    int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+func NewCommandService(ctx context.Context, repo *db.Repo) CommandService {
+	return &commandService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
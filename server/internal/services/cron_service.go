@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+var systemUserName = regexp.MustCompile(`^[a-z_][a-z0-9_.-]*\$?$`)
+
+type CronService interface {
+	CreateCronJob(form dto.CronJobRequest) (*dto.CronJobDto, error)
+	UpdateCronJob(id int64, form dto.CronJobRequest) (*dto.CronJobDto, error)
+	GetCronJob(id int64) (*dto.CronJobDto, error)
+	GetCronJobs(nodeId int32, limit int32, offset int32) ([]*dto.CronJobDto, error)
+	DeleteCronJob(id int64) error
+	GetCronRuns(id int64, limit int32, offset int32) ([]*dto.CronRunDto, error)
+}
+
+type cronService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// CreateCronJob implements CronService.
+func (c *cronService) CreateCronJob(form dto.CronJobRequest) (*dto.CronJobDto, error) {
+	if _, err := c.repo.Queries.GetNode(c.ctx, int64(form.NodeID)); err != nil {
+		return nil, fmt.Errorf("node not found")
+	}
+	if err := validateCronJob(&form); err != nil {
+		return nil, err
+	}
+	if err := checkChannels(c.ctx, c.repo, form.ChannelIDs); err != nil {
+		return nil, err
+	}
+
+	job, err := c.repo.Queries.CreateCronJob(c.ctx, db.CreateCronJobParams{
+		NodeID:     int64(form.NodeID),
+		Name:       form.Name,
+		Schedule:   form.Schedule,
+		Command:    form.Command,
+		WorkingDir: sql.NullString{String: form.WorkingDir, Valid: form.WorkingDir != ""},
+		RunAsUser:  sql.NullString{String: form.User, Valid: form.User != ""},
+		Enabled:    boolToInt64(cronJobEnabled(form)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cron job: %w", err)
+	}
+	if err := c.setChannels(job.ID, form.ChannelIDs); err != nil {
+		return nil, err
+	}
+
+	c.syncNode(job.NodeID)
+	return c.convertCronJob(&job)
+}
+
+// UpdateCronJob implements CronService.
+func (c *cronService) UpdateCronJob(id int64, form dto.CronJobRequest) (*dto.CronJobDto, error) {
+	existing, err := c.repo.Queries.GetCronJob(c.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("cron job not found")
+	}
+	if existing.NodeID != int64(form.NodeID) {
+		return nil, fmt.Errorf("a cron job cannot be moved to another node")
+	}
+	if err := validateCronJob(&form); err != nil {
+		return nil, err
+	}
+	if err := checkChannels(c.ctx, c.repo, form.ChannelIDs); err != nil {
+		return nil, err
+	}
+
+	enabled := cronJobEnabled(form)
+	// a new or re-enabled schedule starts counting from now, runs it never had are not missed
+	lastScheduledAt := existing.LastScheduledAt
+	if form.Schedule != existing.Schedule || (enabled && existing.Enabled == 0) {
+		lastScheduledAt = sql.NullInt64{Int64: time.Now().Unix(), Valid: true}
+	}
+
+	job, err := c.repo.Queries.UpdateCronJob(c.ctx, db.UpdateCronJobParams{
+		Name:            form.Name,
+		Schedule:        form.Schedule,
+		Command:         form.Command,
+		WorkingDir:      sql.NullString{String: form.WorkingDir, Valid: form.WorkingDir != ""},
+		RunAsUser:       sql.NullString{String: form.User, Valid: form.User != ""},
+		Enabled:         boolToInt64(enabled),
+		LastScheduledAt: lastScheduledAt,
+		ID:              id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update cron job: %w", err)
+	}
+	if form.ChannelIDs != nil {
+		if err := c.setChannels(job.ID, form.ChannelIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	c.syncNode(job.NodeID)
+	return c.convertCronJob(&job)
+}
+
+// GetCronJob implements CronService.
+func (c *cronService) GetCronJob(id int64) (*dto.CronJobDto, error) {
+	job, err := c.repo.Queries.GetCronJob(c.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("cron job not found")
+	}
+	return c.convertCronJob(&job)
+}
+
+// GetCronJobs implements CronService.
+// A nodeId of 0 lists the jobs of every node.
+func (c *cronService) GetCronJobs(nodeId int32, limit int32, offset int32) ([]*dto.CronJobDto, error) {
+	var jobs []db.CronJob
+	var err error
+	if nodeId > 0 {
+		jobs, err = c.repo.Queries.ListCronJobsByNode(c.ctx, db.ListCronJobsByNodeParams{
+			NodeID: int64(nodeId),
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	} else {
+		jobs, err = c.repo.Queries.ListCronJobs(c.ctx, db.ListCronJobsParams{
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.CronJobDto, len(jobs))
+	for i, job := range jobs {
+		if dtos[i], err = c.convertCronJob(&job); err != nil {
+			return nil, err
+		}
+	}
+	return dtos, nil
+}
+
+// DeleteCronJob implements CronService.
+func (c *cronService) DeleteCronJob(id int64) error {
+	job, err := c.repo.Queries.GetCronJob(c.ctx, id)
+	if err != nil {
+		return fmt.Errorf("cron job not found")
+	}
+	if _, err := c.repo.Queries.DeleteCronJob(c.ctx, id); err != nil {
+		return fmt.Errorf("failed to delete cron job: %w", err)
+	}
+
+	c.syncNode(job.NodeID)
+	return nil
+}
+
+// GetCronRuns implements CronService.
+func (c *cronService) GetCronRuns(id int64, limit int32, offset int32) ([]*dto.CronRunDto, error) {
+	if _, err := c.repo.Queries.GetCronJob(c.ctx, id); err != nil {
+		return nil, fmt.Errorf("cron job not found")
+	}
+	runs, err := c.repo.Queries.ListCronRuns(c.ctx, db.ListCronRunsParams{
+		CronJobID: id,
+		Limit:     int64(limit),
+		Offset:    int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.CronRunDto, len(runs))
+	for i, run := range runs {
+		dtos[i] = dto.ConvertToCronRunDto(&run)
+	}
+	return dtos, nil
+}
+
+// syncNode pushes the node's jobs to its agent. A failed sync is retried when
+// the agent reconnects, so it does not fail the request.
+func (c *cronService) syncNode(nodeId int64) {
+	if err := tcpserver.SyncCronJobs(c.ctx, c.repo, int32(nodeId)); err != nil {
+		fmt.Println("Error syncing cron jobs to node", nodeId, err)
+	}
+}
+
+func validateCronJob(form *dto.CronJobRequest) error {
+	form.Schedule = strings.TrimSpace(form.Schedule)
+	if _, err := utils.ParseCronSchedule(form.Schedule); err != nil {
+		return err
+	}
+	if strings.TrimSpace(form.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if form.WorkingDir != "" && !path.IsAbs(form.WorkingDir) {
+		return fmt.Errorf("working_dir must be an absolute path")
+	}
+	if form.User != "" && !systemUserName.MatchString(form.User) {
+		return fmt.Errorf("invalid user %q", form.User)
+	}
+	return nil
+}
+
+func cronJobEnabled(form dto.CronJobRequest) bool {
+	return form.Enabled == nil || *form.Enabled
+}
+
+// setChannels replaces the notification channels of a job
+func (c *cronService) setChannels(jobId int64, channelIds []int64) error {
+	tx, err := c.repo.OperationalDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := c.repo.Queries.WithTx(tx)
+	if err := queries.ClearCronJobChannels(c.ctx, jobId); err != nil {
+		return err
+	}
+	for _, id := range channelIds {
+		err := queries.AddCronJobChannel(c.ctx, db.AddCronJobChannelParams{
+			CronJobID: jobId,
+			ChannelID: id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// convertCronJob adds the channels and the next schedule time to the job
+func (c *cronService) convertCronJob(job *db.CronJob) (*dto.CronJobDto, error) {
+	jobDto := dto.ConvertToCronJobDto(job)
+	channels, err := c.repo.Queries.ListCronJobChannels(c.ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	jobDto.ChannelIDs = make([]int64, len(channels))
+	for i, channel := range channels {
+		jobDto.ChannelIDs[i] = channel.ID
+	}
+	if job.Enabled == 1 {
+		if schedule, err := utils.ParseCronSchedule(job.Schedule); err == nil {
+			if next := schedule.Next(time.Now().UTC()); !next.IsZero() {
+				jobDto.NextRunAt = &next
+			}
+		}
+	}
+	return jobDto, nil
+}
+
+func NewCronService(ctx context.Context, repo *db.Repo) CronService {
+	return &cronService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
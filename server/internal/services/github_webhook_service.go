@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+// ErrInvalidWebhookSignature is returned when no project's secret matches the delivery signature
+var ErrInvalidWebhookSignature = errors.New("signature does not match any project secret")
+
+type GitHubWebhookService interface {
+	HandleDelivery(event, deliveryID, signature string, body []byte) ([]*dto.GitHubWebhookEventDto, error)
+	GetEvents(projectID string, limit, offset int32) ([]*dto.GitHubWebhookEventDto, error)
+}
+
+type gitHubWebhookService struct {
+	repo           *db.Repo
+	ctx            context.Context
+	projectService ProjectService
+}
+
+// HandleDelivery implements GitHubWebhookService.
+// A push deploys every project on the pushed repository and branch that has auto_deploy
+// on and whose secret verifies the signature. Every other verified outcome is logged
+// with a reason.
+func (s *gitHubWebhookService) HandleDelivery(event, deliveryID, signature string, body []byte) ([]*dto.GitHubWebhookEventDto, error) {
+	var push dto.GitHubPushEvent
+	if err := json.Unmarshal(body, &push); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	base := db.CreateGitHubWebhookEventParams{
+		DeliveryID: sql.NullString{String: deliveryID, Valid: deliveryID != ""},
+		Event:      event,
+		Repository: sql.NullString{String: push.Repository.FullName, Valid: push.Repository.FullName != ""},
+		CommitSha:  sql.NullString{String: push.After, Valid: push.After != ""},
+		Status:     "ignored",
+	}
+	branch, isBranch := strings.CutPrefix(push.Ref, "refs/heads/")
+	base.Branch = sql.NullString{String: branch, Valid: isBranch}
+
+	projects, err := s.repo.Queries.ListProjectsWithRepo(s.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	candidates := []db.Project{}
+	for _, project := range projects {
+		if repoMatches(project.RepoUrl.String, push) {
+			candidates = append(candidates, project)
+		}
+	}
+
+	// only projects whose secret signed this delivery may act on it. Deliveries no
+	// secret verifies are not logged, anyone can send them.
+	verified := []db.Project{}
+	for _, project := range candidates {
+		if utils.VerifyGitHubSignature(project.WebhookSecret.String, body, signature) {
+			verified = append(verified, project)
+		}
+	}
+	if len(verified) == 0 {
+		if len(candidates) == 0 {
+			fmt.Printf("Rejecting webhook delivery %s, no project uses repository %q\n", deliveryID, push.Repository.FullName)
+		} else {
+			fmt.Printf("Rejecting webhook delivery %s for %q, invalid signature\n", deliveryID, push.Repository.FullName)
+		}
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	events := []*dto.GitHubWebhookEventDto{}
+	for _, project := range verified {
+		params := base
+		params.ProjectID = sql.NullString{String: project.ID, Valid: true}
+
+		reason := ""
+		switch {
+		case event != "push":
+			reason = fmt.Sprintf("%s events do not trigger deploys", event)
+		case !isBranch:
+			reason = fmt.Sprintf("%s is not a branch", push.Ref)
+		case push.Deleted:
+			reason = "branch was deleted"
+		case branch != project.Branch.String:
+			reason = fmt.Sprintf("push to %s, project deploys %s", branch, project.Branch.String)
+		case project.AutoDeploy == 0:
+			reason = "auto deploy is off"
+		}
+
+		if reason == "" {
+			deployment, err := s.projectService.DeployProject(project.ID, 0)
+			if err != nil {
+				reason = err.Error()
+			} else {
+				params.Status = "deployed"
+				params.DeploymentID = sql.NullInt64{Int64: deployment.ID, Valid: true}
+			}
+		}
+
+		logged, err := s.logEvent(params, reason)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, logged)
+	}
+	return events, nil
+}
+
+// GetEvents implements GitHubWebhookService.
+func (s *gitHubWebhookService) GetEvents(projectID string, limit, offset int32) ([]*dto.GitHubWebhookEventDto, error) {
+	var events []db.GithubWebhookEvent
+	var err error
+	if projectID != "" {
+		events, err = s.repo.Queries.ListGitHubWebhookEventsByProject(s.ctx, db.ListGitHubWebhookEventsByProjectParams{
+			ProjectID: sql.NullString{String: projectID, Valid: true},
+			Limit:     int64(limit),
+			Offset:    int64(offset),
+		})
+	} else {
+		events, err = s.repo.Queries.ListGitHubWebhookEvents(s.ctx, db.ListGitHubWebhookEventsParams{
+			Limit:  int64(limit),
+			Offset: int64(offset),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.GitHubWebhookEventDto, len(events))
+	for i, event := range events {
+		dtos[i] = dto.ConvertToGitHubWebhookEventDto(&event)
+	}
+	return dtos, nil
+}
+
+func (s *gitHubWebhookService) logEvent(params db.CreateGitHubWebhookEventParams, reason string) (*dto.GitHubWebhookEventDto, error) {
+	params.Reason = sql.NullString{String: reason, Valid: reason != ""}
+	event, err := s.repo.Queries.CreateGitHubWebhookEvent(s.ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log webhook event: %w", err)
+	}
+	if params.Status == "ignored" {
+		fmt.Println("GitHub webhook ignored:", event.Repository.String, event.Branch.String, reason)
+	}
+	return dto.ConvertToGitHubWebhookEventDto(&event), nil
+}
+
+// repoMatches compares a project's repo_url with the repository of a delivery,
+// accepting https and ssh clone urls with or without the .git suffix
+func repoMatches(repoURL string, push dto.GitHubPushEvent) bool {
+	project := normalizeRepoURL(repoURL)
+	if project == "" {
+		return false
+	}
+	for _, url := range []string{push.Repository.CloneURL, push.Repository.SSHURL, push.Repository.HTMLURL} {
+		if url != "" && normalizeRepoURL(url) == project {
+			return true
+		}
+	}
+	return push.Repository.FullName != "" && project == "github.com/"+strings.ToLower(push.Repository.FullName)
+}
+
+func normalizeRepoURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://"} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	url = strings.TrimPrefix(url, "git@")
+	// user:token@ credentials in https urls
+	if at := strings.LastIndex(url, "@"); at != -1 {
+		url = url[at+1:]
+	}
+	url = strings.Replace(url, ":", "/", 1)
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	return url
+}
+
+func NewGitHubWebhookService(ctx context.Context, repo *db.Repo, projectService ProjectService) GitHubWebhookService {
+	return &gitHubWebhookService{
+		repo:           repo,
+		ctx:            ctx,
+		projectService: projectService,
+	}
+}
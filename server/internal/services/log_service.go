@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+const (
+	defaultLogLines = 100
+	// lines read from the agent to search when a grep filter is set
+	maxLogLines    = 5000
+	logTailTimeout = 30 * time.Second
+)
+
+// ErrLogFileNotAllowed is returned for files that are neither declared in a project's
+// config nor allow-listed for the node
+var ErrLogFileNotAllowed = errors.New("log file is not declared by a project or allow-listed for the node")
+
+type LogService interface {
+	GetProjectLogFiles(projectId string) ([]string, error)
+	TailProjectLog(projectId string, query dto.LogTailQuery) (*dto.LogTailDto, error)
+	FollowProjectLog(ctx context.Context, projectId string, query dto.LogTailQuery, onLines func([]string) error) error
+	GetNodeLogFiles(nodeId int32) ([]*dto.NodeLogFileDto, error)
+	AddNodeLogFile(nodeId int32, form dto.NodeLogFileRequest) (*dto.NodeLogFileDto, error)
+	DeleteNodeLogFile(nodeId int32, id int64) error
+	TailNodeLog(nodeId int32, query dto.LogTailQuery) (*dto.LogTailDto, error)
+	FollowNodeLog(ctx context.Context, nodeId int32, query dto.LogTailQuery, onLines func([]string) error) error
+}
+
+type logService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// GetProjectLogFiles implements LogService.
+// Relative entries of the config's logs array are resolved against the project directory.
+func (l *logService) GetProjectLogFiles(projectId string) ([]string, error) {
+	project, err := l.repo.Queries.GetProject(l.ctx, projectId)
+	if err != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	return projectLogFiles(project), nil
+}
+
+// TailProjectLog implements LogService.
+func (l *logService) TailProjectLog(projectId string, query dto.LogTailQuery) (*dto.LogTailDto, error) {
+	nodeId, file, err := l.projectLogFile(projectId, query.File)
+	if err != nil {
+		return nil, err
+	}
+	return tailLog(l.ctx, nodeId, file, query)
+}
+
+// FollowProjectLog implements LogService.
+func (l *logService) FollowProjectLog(ctx context.Context, projectId string, query dto.LogTailQuery, onLines func([]string) error) error {
+	nodeId, file, err := l.projectLogFile(projectId, query.File)
+	if err != nil {
+		return err
+	}
+	return followLog(ctx, nodeId, file, query, onLines)
+}
+
+// GetNodeLogFiles implements LogService.
+func (l *logService) GetNodeLogFiles(nodeId int32) ([]*dto.NodeLogFileDto, error) {
+	files, err := l.repo.Queries.ListNodeLogFiles(l.ctx, int64(nodeId))
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.NodeLogFileDto, len(files))
+	for i, file := range files {
+		dtos[i] = dto.ConvertToNodeLogFileDto(&file)
+	}
+	return dtos, nil
+}
+
+// AddNodeLogFile implements LogService.
+// The path may be a glob such as /var/log/nginx/*.log.
+func (l *logService) AddNodeLogFile(nodeId int32, form dto.NodeLogFileRequest) (*dto.NodeLogFileDto, error) {
+	if _, err := l.repo.Queries.GetNode(l.ctx, int64(nodeId)); err != nil {
+		return nil, fmt.Errorf("node not found")
+	}
+	if !path.IsAbs(form.Path) {
+		return nil, fmt.Errorf("path must be absolute")
+	}
+	if _, err := path.Match(form.Path, ""); err != nil {
+		return nil, fmt.Errorf("invalid path pattern: %w", err)
+	}
+
+	file, err := l.repo.Queries.CreateNodeLogFile(l.ctx, db.CreateNodeLogFileParams{
+		NodeID: int64(nodeId),
+		Path:   path.Clean(form.Path),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add log file: %w", err)
+	}
+	return dto.ConvertToNodeLogFileDto(&file), nil
+}
+
+// DeleteNodeLogFile implements LogService.
+func (l *logService) DeleteNodeLogFile(nodeId int32, id int64) error {
+	rows, err := l.repo.Queries.DeleteNodeLogFile(l.ctx, db.DeleteNodeLogFileParams{
+		ID:     id,
+		NodeID: int64(nodeId),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete log file: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("log file not found")
+	}
+	return nil
+}
+
+// TailNodeLog implements LogService.
+func (l *logService) TailNodeLog(nodeId int32, query dto.LogTailQuery) (*dto.LogTailDto, error) {
+	file, err := l.nodeLogFile(nodeId, query.File)
+	if err != nil {
+		return nil, err
+	}
+	return tailLog(l.ctx, nodeId, file, query)
+}
+
+// FollowNodeLog implements LogService.
+func (l *logService) FollowNodeLog(ctx context.Context, nodeId int32, query dto.LogTailQuery, onLines func([]string) error) error {
+	file, err := l.nodeLogFile(nodeId, query.File)
+	if err != nil {
+		return err
+	}
+	return followLog(ctx, nodeId, file, query, onLines)
+}
+
+// projectLogFile resolves the requested file against the project and checks it is declared
+func (l *logService) projectLogFile(projectId string, file string) (int32, string, error) {
+	project, err := l.repo.Queries.GetProject(l.ctx, projectId)
+	if err != nil {
+		return 0, "", fmt.Errorf("project not found")
+	}
+	file = resolveLogPath(projectDir(project), file)
+	if !logFileAllowed(projectLogFiles(project), file) {
+		return 0, "", ErrLogFileNotAllowed
+	}
+	return int32(project.NodeID), file, nil
+}
+
+// nodeLogFile checks the file against the node's allow-list and the logs of its projects
+func (l *logService) nodeLogFile(nodeId int32, file string) (string, error) {
+	if !path.IsAbs(file) {
+		return "", fmt.Errorf("file must be an absolute path")
+	}
+	file = path.Clean(file)
+
+	allowed, err := l.repo.Queries.ListNodeLogFiles(l.ctx, int64(nodeId))
+	if err != nil {
+		return "", err
+	}
+	patterns := make([]string, 0, len(allowed))
+	for _, f := range allowed {
+		patterns = append(patterns, f.Path)
+	}
+	projects, err := l.repo.Queries.ListNodeProjects(l.ctx, int64(nodeId))
+	if err != nil {
+		return "", err
+	}
+	for _, project := range projects {
+		patterns = append(patterns, projectLogFiles(project)...)
+	}
+
+	if !logFileAllowed(patterns, file) {
+		return "", ErrLogFileNotAllowed
+	}
+	return file, nil
+}
+
+func projectLogFiles(project db.Project) []string {
+	dir := projectDir(project)
+	var files []string
+	for _, entry := range dto.ParseStringList(project.LogPaths) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			files = append(files, resolveLogPath(dir, entry))
+		}
+	}
+	return files
+}
+
+func resolveLogPath(dir string, file string) string {
+	if path.IsAbs(file) {
+		return path.Clean(file)
+	}
+	return path.Join(dir, file)
+}
+
+// logFileAllowed matches the cleaned file against the allowed paths and globs
+func logFileAllowed(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if pattern == file {
+			return true
+		}
+		if matched, err := path.Match(pattern, file); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// newLogFilter returns nil when the query has no grep
+func newLogFilter(query dto.LogTailQuery) (*regexp.Regexp, error) {
+	if query.Grep == "" {
+		return nil, nil
+	}
+	pattern := query.Grep
+	if !query.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if query.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	filter, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep pattern: %w", err)
+	}
+	return filter, nil
+}
+
+func filterLogLines(filter *regexp.Regexp, lines []string) []string {
+	if filter == nil {
+		return lines
+	}
+	matched := lines[:0:0]
+	for _, line := range lines {
+		if filter.MatchString(line) {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+// tailLog returns the last query.Lines lines of the file. With a grep filter the
+// last maxLogLines lines are searched and the last query.Lines matches returned.
+func tailLog(ctx context.Context, nodeId int32, file string, query dto.LogTailQuery) (*dto.LogTailDto, error) {
+	filter, err := newLogFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	lines := query.Lines
+	if lines <= 0 {
+		lines = defaultLogLines
+	}
+	read := lines
+	if filter != nil {
+		read = maxLogLines
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, logTailTimeout)
+	defer cancel()
+	result := []string{}
+	err = tcpserver.TailLog(ctx, nodeId, tcpserver.LogTailRequest{Path: file, Lines: read}, func(chunk []string) error {
+		result = append(result, filterLogLines(filter, chunk)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) > lines {
+		result = result[len(result)-lines:]
+	}
+	return &dto.LogTailDto{File: file, Lines: result}, nil
+}
+
+// followLog streams the last query.Lines lines and everything appended afterwards,
+// until ctx is done or onLines fails
+func followLog(ctx context.Context, nodeId int32, file string, query dto.LogTailQuery, onLines func([]string) error) error {
+	filter, err := newLogFilter(query)
+	if err != nil {
+		return err
+	}
+	lines := query.Lines
+	if lines <= 0 {
+		lines = defaultLogLines
+	}
+
+	return tcpserver.TailLog(ctx, nodeId, tcpserver.LogTailRequest{Path: file, Lines: lines, Follow: true}, func(chunk []string) error {
+		if chunk = filterLogLines(filter, chunk); len(chunk) == 0 {
+			return nil
+		}
+		return onLines(chunk)
+	})
+}
+
+func NewLogService(ctx context.Context, repo *db.Repo) LogService {
+	return &logService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sanda0/vps_pilot/internal/db"
 	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
 )
 
 type NodeService interface {
@@ -14,6 +15,11 @@ type NodeService interface {
 	GetNodesWithSysInfo(search string, limit int32, page int32) ([]db.GetNodesWithSysInfoRow, error)
 	UpdateName(nodeId int32, name string) error
 	GetNode(nodeId int32) (db.GetNodeWithSysInfoRow, error)
+	GetNodeIPHistory(nodeId int32) ([]db.NodeIpHistory, error)
+	GetNodeSysInfoHistory(nodeId int32) ([]db.NodeSysInfoHistory, error)
+	GetNodeDisks(nodeId int32) ([]db.NodeDiskInfo, error)
+	GetNodeDiskStats(nodeId int32, timeRangeSeconds int64) ([]db.GetDiskStatsRow, error)
+	ScanProjects(nodeId int32) error
 	GetSystemStat(queryParams chan dto.NodeSystemStatRequestDto, result chan dto.SystemStatResponseDto)
 }
 
@@ -67,12 +73,22 @@ func (n *nodeService) GetSystemStat(queryParams chan dto.NodeSystemStatRequestDt
 			continue
 		}
 
+		diskStats, err := n.repo.TimeseriesQueries.GetDiskStats(n.ctx, db.GetDiskStatsParams{
+			NodeID:  int64(query.ID),
+			Column2: timeRangeSeconds,
+		})
+		if err != nil {
+			fmt.Println("Error getting disk stats", err)
+			continue
+		}
+
 		result <- dto.SystemStatResponseDto{
 			NodeID:    query.ID,
 			TimeRange: query.TimeRange,
 			Cpu:       cpuStats,
 			Mem:       memStat,
 			Net:       netStat,
+			Disks:     diskStats,
 		}
 	}
 
@@ -88,6 +104,50 @@ func (n *nodeService) GetNode(nodeId int32) (db.GetNodeWithSysInfoRow, error) {
 	return node, nil
 }
 
+// GetNodeIPHistory implements NodeService.
+func (n *nodeService) GetNodeIPHistory(nodeId int32) ([]db.NodeIpHistory, error) {
+	history, err := n.repo.Queries.GetNodeIPHistory(n.ctx, int64(nodeId))
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetNodeSysInfoHistory implements NodeService.
+func (n *nodeService) GetNodeSysInfoHistory(nodeId int32) ([]db.NodeSysInfoHistory, error) {
+	history, err := n.repo.Queries.GetNodeSysInfoHistory(n.ctx, int64(nodeId))
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ScanProjects implements NodeService.
+func (n *nodeService) ScanProjects(nodeId int32) error {
+	return tcpserver.RequestProjectScan(nodeId)
+}
+
+// GetNodeDisks implements NodeService.
+func (n *nodeService) GetNodeDisks(nodeId int32) ([]db.NodeDiskInfo, error) {
+	disks, err := n.repo.Queries.GetNodeDiskInfoByNodeID(n.ctx, int64(nodeId))
+	if err != nil {
+		return nil, err
+	}
+	return disks, nil
+}
+
+// GetNodeDiskStats implements NodeService.
+func (n *nodeService) GetNodeDiskStats(nodeId int32, timeRangeSeconds int64) ([]db.GetDiskStatsRow, error) {
+	stats, err := n.repo.TimeseriesQueries.GetDiskStats(n.ctx, db.GetDiskStatsParams{
+		NodeID:  int64(nodeId),
+		Column2: timeRangeSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 // UpdateName implements NodeService.
 func (n *nodeService) UpdateName(nodeId int32, name string) error {
 	err := n.repo.Queries.UpdateNodeName(n.ctx, db.UpdateNodeNameParams{
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+type NotificationChannelService interface {
+	CreateChannel(form dto.NotificationChannelRequest) (*dto.NotificationChannelDto, error)
+	UpdateChannel(id int64, form dto.NotificationChannelRequest) (*dto.NotificationChannelDto, error)
+	GetChannel(id int64) (*dto.NotificationChannelDto, error)
+	GetChannels() ([]*dto.NotificationChannelDto, error)
+	DeleteChannel(id int64) error
+	TestChannel(id int64) (*tcpserver.NotificationDelivery, error)
+	GetChannelTypes() []string
+	GetDeliveries(id int64, query dto.WebhookDeliveryQuery) ([]*dto.WebhookDeliveryDto, error)
+}
+
+type notificationChannelService struct {
+	repo *db.Repo
+	ctx  context.Context
+}
+
+// CreateChannel implements NotificationChannelService.
+func (n *notificationChannelService) CreateChannel(form dto.NotificationChannelRequest) (*dto.NotificationChannelDto, error) {
+	config, err := validateChannel(form)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := n.repo.Queries.CreateNotificationChannel(n.ctx, db.CreateNotificationChannelParams{
+		Name:   form.Name,
+		Type:   form.Type,
+		Config: config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel: %w", err)
+	}
+	return dto.ConvertToNotificationChannelDto(&channel), nil
+}
+
+// UpdateChannel implements NotificationChannelService.
+func (n *notificationChannelService) UpdateChannel(id int64, form dto.NotificationChannelRequest) (*dto.NotificationChannelDto, error) {
+	if _, err := n.repo.Queries.GetNotificationChannel(n.ctx, id); err != nil {
+		return nil, fmt.Errorf("channel not found")
+	}
+	config, err := validateChannel(form)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := n.repo.Queries.UpdateNotificationChannel(n.ctx, db.UpdateNotificationChannelParams{
+		Name:   form.Name,
+		Type:   form.Type,
+		Config: config,
+		ID:     id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update channel: %w", err)
+	}
+	return dto.ConvertToNotificationChannelDto(&channel), nil
+}
+
+// GetChannel implements NotificationChannelService.
+func (n *notificationChannelService) GetChannel(id int64) (*dto.NotificationChannelDto, error) {
+	channel, err := n.repo.Queries.GetNotificationChannel(n.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("channel not found")
+	}
+	return dto.ConvertToNotificationChannelDto(&channel), nil
+}
+
+// GetChannels implements NotificationChannelService.
+func (n *notificationChannelService) GetChannels() ([]*dto.NotificationChannelDto, error) {
+	channels, err := n.repo.Queries.ListNotificationChannels(n.ctx)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.NotificationChannelDto, len(channels))
+	for i, channel := range channels {
+		dtos[i] = dto.ConvertToNotificationChannelDto(&channel)
+	}
+	return dtos, nil
+}
+
+// DeleteChannel implements NotificationChannelService.
+// The channel is removed from every alert rule using it.
+func (n *notificationChannelService) DeleteChannel(id int64) error {
+	rows, err := n.repo.Queries.DeleteNotificationChannel(n.ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete channel: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("channel not found")
+	}
+	return nil
+}
+
+// TestChannel implements NotificationChannelService.
+// A sample alert is sent so the channel can be checked before rules use it.
+func (n *notificationChannelService) TestChannel(id int64) (*tcpserver.NotificationDelivery, error) {
+	channel, err := n.repo.Queries.GetNotificationChannel(n.ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("channel not found")
+	}
+	delivery := tcpserver.SendToChannel(n.ctx, n.repo, channel, tcpserver.AlertMsg{
+		NodeName:     "vps-pilot",
+		NodeIp:       "127.0.0.1",
+		Metric:       "Test",
+		Threshold:    "-",
+		CurrentValue: fmt.Sprintf("Test notification for channel %s", channel.Name),
+		Timestamp:    time.Now(),
+	})
+	return &delivery, nil
+}
+
+// GetChannelTypes implements NotificationChannelService.
+func (n *notificationChannelService) GetChannelTypes() []string {
+	return tcpserver.NotifierTypes()
+}
+
+// GetDeliveries implements NotificationChannelService.
+// Attempts are listed newest first, 50 unless the query sets a limit.
+func (n *notificationChannelService) GetDeliveries(id int64, query dto.WebhookDeliveryQuery) ([]*dto.WebhookDeliveryDto, error) {
+	if _, err := n.repo.Queries.GetNotificationChannel(n.ctx, id); err != nil {
+		return nil, fmt.Errorf("channel not found")
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	deliveries, err := n.repo.Queries.ListWebhookDeliveries(n.ctx, db.ListWebhookDeliveriesParams{
+		ChannelID: id,
+		Limit:     limit,
+		Offset:    query.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*dto.WebhookDeliveryDto, len(deliveries))
+	for i, delivery := range deliveries {
+		dtos[i] = dto.ConvertToWebhookDeliveryDto(&delivery)
+	}
+	return dtos, nil
+}
+
+// validateChannel checks the config against the channel type and returns it compacted
+func validateChannel(form dto.NotificationChannelRequest) (string, error) {
+	if _, err := tcpserver.NewNotifier(form.Type, form.Config); err != nil {
+		return "", err
+	}
+	var config bytes.Buffer
+	if err := json.Compact(&config, form.Config); err != nil {
+		return "", fmt.Errorf("invalid channel config: %w", err)
+	}
+	return config.String(), nil
+}
+
+func NewNotificationChannelService(ctx context.Context, repo *db.Repo) NotificationChannelService {
+	return &notificationChannelService{
+		repo: repo,
+		ctx:  ctx,
+	}
+}
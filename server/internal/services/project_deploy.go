@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+const (
+	deployStepTimeout   = 15 * time.Minute
+	defaultKeepReleases = 5
+)
+
+// deployMu makes the "is a deployment already running" check and the insert atomic
+var deployMu sync.Mutex
+
+// RecoverInterruptedDeployments fails deployments that were cut off by a server restart
+func RecoverInterruptedDeployments(ctx context.Context, repo *db.Repo) {
+	if err := repo.Queries.FailUnfinishedDeployments(ctx); err != nil {
+		fmt.Println("Error failing unfinished deployments:", err)
+	}
+	if err := repo.Queries.FailCloningProjects(ctx); err != nil {
+		fmt.Println("Error resetting project status:", err)
+	}
+}
+
+// DeployProject starts a deployment of the project on its node and returns right away.
+// The branch is built in a new release directory under deploy_path, which becomes
+// deploy_path/current once every build step has passed.
+func (s *projectService) DeployProject(id string, userId int32) (*dto.DeploymentResponse, error) {
+	project, err := s.getDeployableProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startDeployment(project, db.CreateDeploymentParams{
+		ProjectID:   project.ID,
+		NodeID:      project.NodeID,
+		Branch:      project.Branch,
+		TriggeredBy: sql.NullInt64{Int64: int64(userId), Valid: userId != 0},
+	}, nil)
+}
+
+// RollbackProject redeploys the commit of a previous successful deployment. When its
+// release directory is still on the node, current is switched back to it without a rebuild.
+// deploymentID 0 picks the latest successful deployment of a different commit.
+func (s *projectService) RollbackProject(id string, deploymentID int64, userId int32) (*dto.DeploymentResponse, error) {
+	project, err := s.getDeployableProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var target db.Deployment
+	if deploymentID != 0 {
+		target, err = s.repo.Queries.GetDeployment(s.ctx, deploymentID)
+		if err != nil || target.ProjectID != project.ID {
+			return nil, fmt.Errorf("deployment not found")
+		}
+		if target.Status != "succeeded" || !target.CommitSha.Valid {
+			return nil, fmt.Errorf("only successful deployments can be rolled back to")
+		}
+	} else {
+		target, err = s.previousDeployment(project)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.startDeployment(project, db.CreateDeploymentParams{
+		ProjectID:   project.ID,
+		NodeID:      project.NodeID,
+		Branch:      target.Branch,
+		CommitSha:   target.CommitSha,
+		TriggeredBy: sql.NullInt64{Int64: int64(userId), Valid: userId != 0},
+		RollbackOf:  sql.NullInt64{Int64: target.ID, Valid: true},
+	}, &target)
+}
+
+// previousDeployment finds the latest successful deployment whose commit differs from the live one
+func (s *projectService) previousDeployment(project db.Project) (db.Deployment, error) {
+	currentCommit := ""
+	if project.CurrentDeploymentID.Valid {
+		current, err := s.repo.Queries.GetDeployment(s.ctx, project.CurrentDeploymentID.Int64)
+		if err == nil {
+			currentCommit = current.CommitSha.String
+		}
+	}
+
+	deployments, err := s.repo.Queries.ListSucceededDeployments(s.ctx, db.ListSucceededDeploymentsParams{
+		ProjectID: project.ID,
+		Limit:     100,
+	})
+	if err != nil {
+		return db.Deployment{}, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, deployment := range deployments {
+		if deployment.CommitSha.String != currentCommit {
+			return deployment, nil
+		}
+	}
+	return db.Deployment{}, fmt.Errorf("no previous successful deployment to roll back to")
+}
+
+func (s *projectService) getDeployableProject(id string) (db.Project, error) {
+	project, err := s.repo.Queries.GetProject(s.ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return project, fmt.Errorf("project not found")
+		}
+		return project, fmt.Errorf("failed to get project: %w", err)
+	}
+	if !project.RepoUrl.Valid || project.RepoUrl.String == "" {
+		return project, fmt.Errorf("project has no repository url")
+	}
+	// projects saved before deploy paths were validated
+	if !utils.ValidDeployPath(project.DeployPath) {
+		return project, ErrInvalidDeployPath
+	}
+	if !tcpserver.IsNodeConnected(int32(project.NodeID)) {
+		return project, tcpserver.ErrNodeNotConnected
+	}
+	return project, nil
+}
+
+func (s *projectService) startDeployment(project db.Project, params db.CreateDeploymentParams, target *db.Deployment) (*dto.DeploymentResponse, error) {
+	deployMu.Lock()
+	defer deployMu.Unlock()
+	active, err := s.repo.Queries.CountActiveDeploymentsByProject(s.ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check deployments: %w", err)
+	}
+	if active > 0 {
+		return nil, fmt.Errorf("a deployment is already running for this project")
+	}
+
+	deployment, err := s.repo.Queries.CreateDeployment(s.ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	}
+
+	go s.runDeployment(project, deployment, target)
+
+	return dto.ConvertToDeploymentResponse(&deployment), nil
+}
+
+// ListDeployments returns the deployments of a project, newest first
+func (s *projectService) ListDeployments(projectID string, limit, offset int32) ([]*dto.DeploymentResponse, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	deployments, err := s.repo.Queries.ListDeploymentsByProject(s.ctx, db.ListDeploymentsByProjectParams{
+		ProjectID: projectID,
+		Limit:     int64(limit),
+		Offset:    int64(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	responses := make([]*dto.DeploymentResponse, len(deployments))
+	for i, deployment := range deployments {
+		responses[i] = dto.ConvertToDeploymentResponse(&deployment)
+		// logs can be large, they are returned by GetDeployment
+		responses[i].Logs = ""
+	}
+	return responses, nil
+}
+
+// GetDeployment returns a deployment with its logs
+func (s *projectService) GetDeployment(id int64) (*dto.DeploymentResponse, error) {
+	deployment, err := s.repo.Queries.GetDeployment(s.ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("deployment not found")
+		}
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	return dto.ConvertToDeploymentResponse(&deployment), nil
+}
+
+// deployRun collects the logs of a deployment while its steps run on the agent.
+//
+// deploy_path holds the git checkout in repo/, one directory per build in
+// releases/<deployment id>, and the current symlink to the live release.
+type deployRun struct {
+	s           *projectService
+	project     db.Project
+	deployment  db.Deployment
+	logs        strings.Builder
+	commitSHA   string
+	releasePath string
+}
+
+func (r *deployRun) path(elem ...string) string {
+	return strings.Join(append([]string{strings.TrimRight(r.project.DeployPath, "/")}, elem...), "/")
+}
+
+func (s *projectService) runDeployment(project db.Project, deployment db.Deployment, target *db.Deployment) {
+	run := &deployRun{
+		s:          s,
+		project:    project,
+		deployment: deployment,
+		commitSHA:  deployment.CommitSha.String,
+	}
+
+	if err := s.repo.Queries.StartDeployment(s.ctx, deployment.ID); err != nil {
+		fmt.Println("Error starting deployment", err)
+	}
+	s.setStatus(project.ID, "cloning")
+
+	err := run.execute(target)
+	status := "succeeded"
+	errMsg := sql.NullString{}
+	if err != nil {
+		status = "failed"
+		errMsg = sql.NullString{String: err.Error(), Valid: true}
+		fmt.Fprintf(&run.logs, "\n==> deployment failed: %s\n", err)
+	} else {
+		run.pruneReleases()
+	}
+
+	_, finishErr := s.repo.Queries.FinishDeployment(s.ctx, db.FinishDeploymentParams{
+		Status:    status,
+		CommitSha: sql.NullString{String: run.commitSHA, Valid: run.commitSHA != ""},
+		Logs:      run.logs.String(),
+		Error:     errMsg,
+		ID:        deployment.ID,
+	})
+	if finishErr != nil {
+		fmt.Println("Error finishing deployment", finishErr)
+	}
+
+	if err != nil {
+		s.setStatus(project.ID, "error")
+		return
+	}
+	s.setStatus(project.ID, "active")
+	err = s.repo.Queries.SetProjectCurrentDeployment(s.ctx, db.SetProjectCurrentDeploymentParams{
+		CurrentDeploymentID: sql.NullInt64{Int64: deployment.ID, Valid: true},
+		ID:                  project.ID,
+	})
+	if err != nil {
+		fmt.Println("Error updating current deployment", err)
+	}
+}
+
+func (s *projectService) setStatus(id string, status string) {
+	if _, err := s.UpdateProjectStatus(id, status); err != nil {
+		fmt.Println("Error updating project status", err)
+	}
+}
+
+func (r *deployRun) execute(target *db.Deployment) error {
+	if target != nil && target.ReleasePath.Valid && target.ReleasePruned == 0 {
+		release := utils.ShellQuote(target.ReleasePath.String)
+		if _, err := r.step("check release", fmt.Sprintf("test -d %s", release), ""); err == nil {
+			r.setRelease(target.ReleasePath.String)
+			return r.activate()
+		}
+		fmt.Fprintf(&r.logs, "==> release of deployment %d is gone, rebuilding %s\n", target.ID, r.commitSHA)
+	}
+
+	if err := r.build(); err != nil {
+		if r.releasePath != "" {
+			// a half built release is never activated
+			r.step("remove failed release", fmt.Sprintf("rm -rf %s", utils.ShellQuote(r.releasePath)), "")
+		}
+		return err
+	}
+	return r.activate()
+}
+
+// build updates the checkout, copies it to a new release directory and runs the build steps there
+func (r *deployRun) build() error {
+	repoDir := utils.ShellQuote(r.path("repo"))
+	branch := utils.ShellQuote(r.deployment.Branch.String)
+	ref := "origin/" + branch
+	if r.commitSHA != "" {
+		ref = utils.ShellQuote(r.commitSHA)
+	}
+
+	checkout := fmt.Sprintf(
+		"if [ -d %[1]s/.git ]; then git -C %[1]s remote set-url origin %[3]s && git -C %[1]s fetch origin %[2]s; "+
+			"else mkdir -p %[4]s && git clone --branch %[2]s %[3]s %[1]s; fi && git -C %[1]s checkout --force --detach %[5]s",
+		repoDir, branch, utils.ShellQuote(r.project.RepoUrl.String), utils.ShellQuote(r.path()), ref,
+	)
+	if _, err := r.step("checkout "+r.deployment.Branch.String, checkout, ""); err != nil {
+		return err
+	}
+
+	output, err := r.step("resolve commit", fmt.Sprintf("git -C %s rev-parse HEAD", repoDir), "")
+	if err != nil {
+		return err
+	}
+	r.commitSHA = strings.TrimSpace(output)
+
+	release := r.path("releases", fmt.Sprint(r.deployment.ID))
+	copyRelease := fmt.Sprintf("rm -rf %[1]s && mkdir -p %[2]s && cp -a %[3]s %[1]s",
+		utils.ShellQuote(release), utils.ShellQuote(r.path("releases")), repoDir)
+	if _, err := r.step("create release", copyRelease, ""); err != nil {
+		return err
+	}
+	r.setRelease(release)
+
+	for i, command := range dto.ParseBuildSteps(r.project.BuildSteps) {
+		if _, err := r.step(fmt.Sprintf("build step %d", i+1), command, release); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activate points deploy_path/current at the release. The symlink is replaced with a
+// rename so there is never a moment without a current release.
+func (r *deployRun) activate() error {
+	next := utils.ShellQuote(r.path(".current.tmp"))
+	command := fmt.Sprintf("ln -sfn %s %s && mv -Tf %s %s",
+		utils.ShellQuote(r.releasePath), next, next, utils.ShellQuote(r.path("current")))
+	_, err := r.step("activate release", command, "")
+	return err
+}
+
+func (r *deployRun) setRelease(path string) {
+	r.releasePath = path
+	err := r.s.repo.Queries.SetDeploymentRelease(r.s.ctx, db.SetDeploymentReleaseParams{
+		ReleasePath: sql.NullString{String: path, Valid: true},
+		ID:          r.deployment.ID,
+	})
+	if err != nil {
+		fmt.Println("Error storing deployment release", err)
+	}
+}
+
+// pruneReleases removes release directories beyond the project's keep_releases.
+// The live release is always kept. A failed prune does not fail the deployment.
+func (r *deployRun) pruneReleases() {
+	deployments, err := r.s.repo.Queries.ListReleasedDeployments(r.s.ctx, r.project.ID)
+	if err != nil {
+		fmt.Println("Error listing releases", err)
+		return
+	}
+
+	keep := r.project.KeepReleases
+	if keep <= 0 {
+		keep = defaultKeepReleases
+	}
+	seen := map[string]bool{r.releasePath: true}
+	kept := int64(1)
+	stale := []string{}
+	for _, deployment := range deployments {
+		path := deployment.ReleasePath.String
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if kept < keep {
+			kept++
+			continue
+		}
+		stale = append(stale, path)
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	quoted := make([]string, len(stale))
+	for i, path := range stale {
+		quoted[i] = utils.ShellQuote(path)
+	}
+	if _, err := r.step("prune releases", "rm -rf "+strings.Join(quoted, " "), ""); err != nil {
+		return
+	}
+	for _, path := range stale {
+		err := r.s.repo.Queries.MarkReleasePruned(r.s.ctx, db.MarkReleasePrunedParams{
+			ProjectID:   r.project.ID,
+			ReleasePath: sql.NullString{String: path, Valid: true},
+		})
+		if err != nil {
+			fmt.Println("Error marking release pruned", err)
+		}
+	}
+}
+
+// step runs one command on the node and appends its output to the deployment logs
+func (r *deployRun) step(name string, command string, dir string) (string, error) {
+	fmt.Fprintf(&r.logs, "==> %s\n$ %s\n", name, command)
+
+	execution, err := tcpserver.StartExecution(r.s.ctx, r.s.repo, int32(r.project.NodeID), tcpserver.ExecOptions{
+		Command: command,
+		Dir:     dir,
+		Timeout: deployStepTimeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	result := execution.Wait()
+	output := execution.Output()
+	r.logs.WriteString(output)
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		r.logs.WriteString("\n")
+	}
+
+	// keep the stored logs current so a running deployment can be followed
+	err = r.s.repo.Queries.UpdateDeploymentLogs(r.s.ctx, db.UpdateDeploymentLogsParams{
+		Logs:      r.logs.String(),
+		CommitSha: sql.NullString{String: r.commitSHA, Valid: r.commitSHA != ""},
+		ID:        r.deployment.ID,
+	})
+	if err != nil {
+		fmt.Println("Error updating deployment logs", err)
+	}
+
+	if result.Status != tcpserver.ExecStatusSucceeded {
+		if result.Error != "" {
+			return output, fmt.Errorf("%s %s: %s", name, result.Status, result.Error)
+		}
+		if result.ExitCode != nil {
+			return output, fmt.Errorf("%s exited with code %d", name, *result.ExitCode)
+		}
+		return output, fmt.Errorf("%s %s", name, result.Status)
+	}
+	return output, nil
+}
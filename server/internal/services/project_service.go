@@ -3,12 +3,18 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/utils"
 )
 
+// ErrInvalidDeployPath rejects a relative deploy path or the filesystem root, deployments
+// create and prune directories under it
+var ErrInvalidDeployPath = errors.New("deploy_path must be an absolute path other than /")
+
 type ProjectService interface {
 	CreateProject(req *dto.CreateProjectRequest) (*dto.ProjectResponse, error)
 	GetProject(id string) (*dto.ProjectResponse, error)
@@ -19,6 +25,11 @@ type ProjectService interface {
 	DeleteProject(id string) error
 	CountProjects() (int64, error)
 	CountProjectsByNode(nodeID int32) (int64, error)
+	RotateWebhookSecret(id string) (*dto.ProjectResponse, error)
+	DeployProject(id string, userId int32) (*dto.DeploymentResponse, error)
+	RollbackProject(id string, deploymentID int64, userId int32) (*dto.DeploymentResponse, error)
+	ListDeployments(projectID string, limit, offset int32) ([]*dto.DeploymentResponse, error)
+	GetDeployment(id int64) (*dto.DeploymentResponse, error)
 }
 
 type projectService struct {
@@ -35,6 +46,10 @@ func NewProjectService(repo *db.Repo, ctx context.Context) ProjectService {
 
 // CreateProject creates a new project
 func (s *projectService) CreateProject(req *dto.CreateProjectRequest) (*dto.ProjectResponse, error) {
+	if !utils.ValidDeployPath(req.DeployPath) {
+		return nil, ErrInvalidDeployPath
+	}
+
 	// Validate node exists
 	_, err := s.repo.Queries.GetNode(s.ctx, int64(req.NodeID))
 	if err != nil {
@@ -46,6 +61,15 @@ func (s *projectService) CreateProject(req *dto.CreateProjectRequest) (*dto.Proj
 		req.Branch = "main"
 	}
 
+	if req.KeepReleases == 0 {
+		req.KeepReleases = defaultKeepReleases
+	}
+
+	webhookSecret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
 	project, err := s.repo.Queries.CreateProject(s.ctx, db.CreateProjectParams{
 		Name: req.Name,
 		Description: sql.NullString{
@@ -66,6 +90,10 @@ func (s *projectService) CreateProject(req *dto.CreateProjectRequest) (*dto.Proj
 			String: "inactive",
 			Valid:  true,
 		},
+		BuildSteps:    dto.EncodeBuildSteps(req.BuildSteps),
+		KeepReleases:  int64(req.KeepReleases),
+		AutoDeploy:    boolToInt64(req.AutoDeploy),
+		WebhookSecret: sql.NullString{String: webhookSecret, Valid: true},
 	})
 
 	if err != nil {
@@ -139,8 +167,12 @@ func (s *projectService) ListProjectsByNode(nodeID int32, limit, offset int32) (
 
 // UpdateProject updates an existing project
 func (s *projectService) UpdateProject(id string, req *dto.UpdateProjectRequest) (*dto.ProjectResponse, error) {
+	if !utils.ValidDeployPath(req.DeployPath) {
+		return nil, ErrInvalidDeployPath
+	}
+
 	// Check if project exists
-	_, err := s.repo.Queries.GetProject(s.ctx, id)
+	existing, err := s.repo.Queries.GetProject(s.ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("project not found")
@@ -148,6 +180,11 @@ func (s *projectService) UpdateProject(id string, req *dto.UpdateProjectRequest)
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
 
+	keepReleases := int64(req.KeepReleases)
+	if keepReleases == 0 {
+		keepReleases = existing.KeepReleases
+	}
+
 	project, err := s.repo.Queries.UpdateProject(s.ctx, db.UpdateProjectParams{
 		Name: req.Name,
 		Description: sql.NullString{
@@ -167,7 +204,10 @@ func (s *projectService) UpdateProject(id string, req *dto.UpdateProjectRequest)
 			String: req.Status,
 			Valid:  req.Status != "",
 		},
-		ID: id,
+		BuildSteps:   dto.EncodeBuildSteps(req.BuildSteps),
+		KeepReleases: keepReleases,
+		AutoDeploy:   boolToInt64(req.AutoDeploy),
+		ID:           id,
 	})
 
 	if err != nil {
@@ -177,6 +217,27 @@ func (s *projectService) UpdateProject(id string, req *dto.UpdateProjectRequest)
 	return dto.ConvertToProjectResponse(&project), nil
 }
 
+// RotateWebhookSecret replaces the secret GitHub webhook deliveries are signed with
+func (s *projectService) RotateWebhookSecret(id string) (*dto.ProjectResponse, error) {
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	project, err := s.repo.Queries.SetProjectWebhookSecret(s.ctx, db.SetProjectWebhookSecretParams{
+		WebhookSecret: sql.NullString{String: secret, Valid: true},
+		ID:            id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	return dto.ConvertToProjectResponse(&project), nil
+}
+
 // UpdateProjectStatus updates only the status of a project
 func (s *projectService) UpdateProjectStatus(id, status string) (*dto.ProjectResponse, error) {
 	project, err := s.repo.Queries.UpdateProjectStatus(s.ctx, db.UpdateProjectStatusParams{
@@ -3,66 +3,47 @@ package tcpserver
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 )
 
-func CreateNode(ctx context.Context, repo *db.Repo, ip string, data []byte) (*db.Node, error) {
+// CreateNode creates the node of an agent enrolling with an unbound token. Nodes are
+// keyed on the machine id, so agents sharing an address (e.g. behind NAT) each get
+// their own node. An unbound token never takes over an existing node, the machine
+// id is reported by the agent and can be spoofed. Existing nodes, including those
+// enrolled before machine ids existed, reconnect with a token created for them.
+func CreateNode(ctx context.Context, repo *db.Repo, machineId string, ip string) (*db.Node, error) {
 
-	node, err := repo.Queries.GetNodeByIP(ctx, ip)
-	if err == nil {
-		return &node, nil
+	if machineId != "" {
+		node, err := repo.Queries.GetNodeByMachineID(ctx, sql.NullString{String: machineId, Valid: true})
+		if err == nil {
+			fmt.Println("Refusing enrollment with the machine id of node", node.ID)
+			return nil, ErrNodeAlreadyEnrolled
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
 	}
 
-	node, err = repo.Queries.CreateNode(ctx, db.CreateNodeParams{
+	node, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{
 		Name: sql.NullString{
 			String: "node-" + ip,
 			Valid:  true,
 		},
 		Ip: ip,
+		MachineID: sql.NullString{
+			String: machineId,
+			Valid:  machineId != "",
+		},
 	})
 	if err != nil {
 		fmt.Println("Error creating node", err)
+		return nil, err
 	}
 	fmt.Println("Node created", node)
-	sysInfo := SystemInfo{}
-	err = sysInfo.FromBytes(data)
-	if err != nil {
-		fmt.Println("Error unmarshalling system info", err)
-	}
-	sysInfoDb, err := repo.Queries.AddNodeSysInfo(ctx, db.AddNodeSysInfoParams{
-		NodeID: node.ID,
-		Os: sql.NullString{
-			String: sysInfo.OS,
-			Valid:  true,
-		},
-		Platform: sql.NullString{
-			String: sysInfo.Platform,
-			Valid:  true,
-		},
-		PlatformVersion: sql.NullString{
-			String: sysInfo.PlatformVersion,
-			Valid:  true,
-		},
-		KernelVersion: sql.NullString{
-			String: sysInfo.KernelVersion,
-			Valid:  true,
-		},
-		Cpus: sql.NullInt64{
-			Int64: int64(sysInfo.CPUs),
-			Valid: true,
-		},
-		TotalMemory: sql.NullFloat64{
-			Float64: float64(sysInfo.TotalMemory),
-			Valid:   true,
-		},
-	})
-	if err != nil {
-		fmt.Println("Error adding node sys info", err)
-	}
-	fmt.Println("Node sys info added", sysInfoDb)
 	return &node, nil
 }
 
@@ -116,6 +97,39 @@ func StoreSystemStats(ctx context.Context, repo *db.Repo, statChan chan Msg) {
 			continue
 		}
 
+		// Insert aggregate disk stat
+		err = repo.TimeseriesQueries.WithTx(tx).InsertDiskStat(ctx, db.InsertDiskStatParams{
+			Timestamp:   now,
+			NodeID:      int64(msg.NodeId),
+			Mountpoint:  AllMountsDiskMountpoint,
+			UsedPercent: sysStat.DiskUsage,
+		})
+		if err != nil {
+			fmt.Println("Error inserting disk stat:", err)
+			tx.Rollback()
+			continue
+		}
+
+		// Insert per-mount disk stats
+		for _, disk := range sysStat.Disks {
+			err = repo.TimeseriesQueries.WithTx(tx).InsertDiskStat(ctx, db.InsertDiskStatParams{
+				Timestamp:   now,
+				NodeID:      int64(msg.NodeId),
+				Mountpoint:  disk.Mountpoint,
+				Total:       int64(disk.Total),
+				Used:        int64(disk.Used),
+				UsedPercent: disk.UsedPercent(),
+			})
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			fmt.Println("Error inserting mount disk stat:", err)
+			tx.Rollback()
+			continue
+		}
+
 		// Insert network stats
 		err = repo.TimeseriesQueries.WithTx(tx).InsertNetStats(ctx, db.InsertNetStatsParams{
 			Timestamp: now,
@@ -133,5 +147,63 @@ func StoreSystemStats(ctx context.Context, repo *db.Repo, statChan chan Msg) {
 		if err := tx.Commit(); err != nil {
 			fmt.Println("Error committing transaction:", err)
 		}
+
+		if len(sysStat.Disks) > 0 && time.Since(lastDiskInventorySync[msg.NodeId]) >= diskInventorySyncInterval {
+			lastDiskInventorySync[msg.NodeId] = time.Now()
+			if err := SyncDiskInventory(ctx, repo, msg.NodeId, sysStat.Disks); err != nil {
+				fmt.Println("Error syncing disk inventory:", err)
+			}
+		}
 	}
 }
+
+// diskInventorySyncInterval limits how often the mount inventory is rewritten from sys_stat messages
+const diskInventorySyncInterval = time.Minute
+
+// lastDiskInventorySync is only touched by the StoreSystemStats goroutine
+var lastDiskInventorySync = make(map[int32]time.Time)
+
+// SyncDiskInventory upserts the mounts reported by the agent and removes the ones that disappeared
+func SyncDiskInventory(ctx context.Context, repo *db.Repo, nodeId int32, disks []Disk) error {
+	tx, err := repo.OperationalDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	queries := repo.Queries.WithTx(tx)
+
+	syncedAt := time.Now().Unix()
+	for _, disk := range disks {
+		_, err := queries.UpdateNodeDiskInfo(ctx, db.UpdateNodeDiskInfoParams{
+			Device:     sql.NullString{String: disk.Device, Valid: true},
+			Fstype:     sql.NullString{String: disk.Fstype, Valid: true},
+			Total:      sql.NullFloat64{Float64: float64(disk.Total), Valid: true},
+			Used:       sql.NullFloat64{Float64: float64(disk.Used), Valid: true},
+			NodeID:     int64(nodeId),
+			MountPoint: sql.NullString{String: disk.Mountpoint, Valid: true},
+		})
+		if err == sql.ErrNoRows {
+			_, err = queries.AddNodeDiskInfo(ctx, db.AddNodeDiskInfoParams{
+				NodeID:     int64(nodeId),
+				Device:     sql.NullString{String: disk.Device, Valid: true},
+				MountPoint: sql.NullString{String: disk.Mountpoint, Valid: true},
+				Fstype:     sql.NullString{String: disk.Fstype, Valid: true},
+				Total:      sql.NullFloat64{Float64: float64(disk.Total), Valid: true},
+				Used:       sql.NullFloat64{Float64: float64(disk.Used), Valid: true},
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store mount %s: %w", disk.Mountpoint, err)
+		}
+	}
+
+	err = queries.DeleteStaleNodeDiskInfo(ctx, db.DeleteStaleNodeDiskInfoParams{
+		NodeID:    int64(nodeId),
+		UpdatedAt: syncedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove unmounted disks: %w", err)
+	}
+
+	return tx.Commit()
+}
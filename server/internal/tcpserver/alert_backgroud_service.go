@@ -3,18 +3,14 @@ package tcpserver
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 )
 
-var lastAlertSentTime map[int32]time.Time
-
 func MontiorAlerts(ctx context.Context, repo *db.Repo, monitorChan chan Msg) {
 	fmt.Println("Monitoring alerts...")
-	if lastAlertSentTime == nil {
-		lastAlertSentTime = make(map[int32]time.Time)
-	}
 	for {
 		msg := <-monitorChan
 
@@ -26,13 +22,46 @@ func MontiorAlerts(ctx context.Context, repo *db.Repo, monitorChan chan Msg) {
 				fmt.Println("Error decoding sys_stat", err)
 				continue
 			}
-			go checkCpuUsage(ctx, repo, msg.NodeId, average(sysStat.CPUUsage))
-			go checkMemoryUsage(ctx, repo, msg.NodeId, sysStat.MemUsage)
-			go checkNetworkUsage(ctx, repo, msg.NodeId, float64(sysStat.NetSentPS), float64(sysStat.NetRecvPS))
+			// the sample time is taken on arrival, the checks run concurrently and the
+			// evaluator drops samples older than the last one it saw
+			go CheckSystemStat(ctx, repo, msg.NodeId, sysStat, time.Now())
+		}
+
+		if msg.Msg == "node_status" {
+			var event NodeStatusEvent
+			err := event.FromBytes(msg.Data)
+			if err != nil {
+				fmt.Println("Error decoding node_status", err)
+				continue
+			}
+			go checkNodeStatus(ctx, repo, event, time.Now())
 		}
 	}
 }
 
+// CheckSystemStat evaluates every alert rule of the node against one sys_stat sample
+// taken at sampledAt and returns once all rules are evaluated
+func CheckSystemStat(ctx context.Context, repo *db.Repo, nodeId int32, sysStat SystemStat, sampledAt time.Time) {
+	checks := []func(){
+		func() { checkCpuUsage(ctx, repo, nodeId, average(sysStat.CPUUsage), sampledAt) },
+		func() { checkMemoryUsage(ctx, repo, nodeId, sysStat.MemUsage, sampledAt) },
+		func() {
+			checkNetworkUsage(ctx, repo, nodeId, float64(sysStat.NetSentPS), float64(sysStat.NetRecvPS), sampledAt)
+		},
+		func() { checkDiskUsage(ctx, repo, nodeId, sysStat, sampledAt) },
+		func() { checkDiskFill(ctx, repo, nodeId, sysStat, sampledAt) },
+	}
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check func()) {
+			defer wg.Done()
+			check()
+		}(check)
+	}
+	wg.Wait()
+}
+
 func average(nums []float64) float64 {
 	if len(nums) == 0 {
 		return 0 // avoid division by zero
@@ -46,31 +75,7 @@ func average(nums []float64) float64 {
 	return sum / float64(len(nums))
 }
 
-// sendAlertNotifications sends alert to all configured notification channels
-func sendAlertNotifications(alert db.GetActiveAlertsByNodeAndMetricRow, alertMsg AlertMsg) {
-	// Send Discord alert if webhook is configured
-	if alert.DiscordWebhook.String != "" {
-		if err := SendDiscordAlert(alert.DiscordWebhook.String, alertMsg); err != nil {
-			fmt.Printf("Failed to send Discord alert: %v\n", err)
-		}
-	}
-
-	// Send Email alert if email is configured
-	if alert.Email.String != "" {
-		if err := SendEmailAlert(alert.Email.String, alertMsg); err != nil {
-			fmt.Printf("Failed to send email alert: %v\n", err)
-		}
-	}
-
-	// Send Slack alert if webhook is configured
-	if alert.SlackWebhook.String != "" {
-		if err := SendSlackAlert(alert.SlackWebhook.String, alertMsg); err != nil {
-			fmt.Printf("Failed to send Slack alert: %v\n", err)
-		}
-	}
-}
-
-func checkCpuUsage(ctx context.Context, repo *db.Repo, nodeId int32, cpuAvg float64) {
+func checkCpuUsage(ctx context.Context, repo *db.Repo, nodeId int32, cpuAvg float64, now time.Time) {
 	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
 		NodeID: int64(nodeId),
 		Metric: "cpu",
@@ -85,31 +90,23 @@ func checkCpuUsage(ctx context.Context, repo *db.Repo, nodeId int32, cpuAvg floa
 	}
 	fmt.Println("Active alerts found", len(alerts))
 	for _, alert := range alerts {
-		if cpuAvg > alert.Threshold.Float64 {
+		samples := systemStatWindow(ctx, repo, alert, "cpu", cpuAvg, now)
+		value, breached, pendingFor := windowBreach(alert, samples, alert.Threshold.Float64, now)
+		if breached {
 			fmt.Println("Cpu usage exceeded threshold for alert", int32(alert.ID))
-			lastSendTime, ok := lastAlertSentTime[int32(alert.ID)]
-			if ok {
-				if time.Since(lastSendTime).Minutes() < float64(alert.Duration) {
-					fmt.Println("Alert already sent within last", alert.Duration, "minutes")
-					continue
-				}
-			}
-
-			lastAlertSentTime[int32(alert.ID)] = time.Now()
-			// send notifications to all configured channels
-			sendAlertNotifications(alert, AlertMsg{
-				NodeName:     alert.NodeName.String,
-				NodeIp:       alert.NodeIp,
-				Metric:       "CPU",
-				Threshold:    fmt.Sprintf("%.2f%%", alert.Threshold.Float64),
-				CurrentValue: fmt.Sprintf("%.2f%%", cpuAvg),
-				Timestamp:    time.Now(),
-			})
 		}
+		evaluateAlert(ctx, repo, alert, breached, pendingFor, AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "CPU",
+			Threshold:    fmt.Sprintf("%.2f%%%s", alert.Threshold.Float64, alertWindowLabel(alert)),
+			CurrentValue: fmt.Sprintf("%.2f%%", value),
+			Timestamp:    now,
+		})
 	}
 }
 
-func checkMemoryUsage(ctx context.Context, repo *db.Repo, nodeId int32, memUsage float64) {
+func checkMemoryUsage(ctx context.Context, repo *db.Repo, nodeId int32, memUsage float64, now time.Time) {
 	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
 		NodeID: int64(nodeId),
 		Metric: "mem",
@@ -124,31 +121,23 @@ func checkMemoryUsage(ctx context.Context, repo *db.Repo, nodeId int32, memUsage
 	}
 	fmt.Println("Active alerts found", len(alerts))
 	for _, alert := range alerts {
-		if memUsage > alert.Threshold.Float64 {
+		samples := systemStatWindow(ctx, repo, alert, "mem", memUsage, now)
+		value, breached, pendingFor := windowBreach(alert, samples, alert.Threshold.Float64, now)
+		if breached {
 			fmt.Println("Memory usage exceeded threshold for alert", int32(alert.ID))
-			lastSendTime, ok := lastAlertSentTime[int32(alert.ID)]
-			if ok {
-				if time.Since(lastSendTime).Minutes() < float64(alert.Duration) {
-					fmt.Println("Alert already sent within last", alert.Duration, "minutes")
-					continue
-				}
-			}
-
-			lastAlertSentTime[int32(alert.ID)] = time.Now()
-			// send notifications to all configured channels
-			sendAlertNotifications(alert, AlertMsg{
-				NodeName:     alert.NodeName.String,
-				NodeIp:       alert.NodeIp,
-				Metric:       "Memory",
-				Threshold:    fmt.Sprintf("%.2f%%", alert.Threshold.Float64),
-				CurrentValue: fmt.Sprintf("%.2f%%", memUsage),
-				Timestamp:    time.Now(),
-			})
 		}
+		evaluateAlert(ctx, repo, alert, breached, pendingFor, AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "Memory",
+			Threshold:    fmt.Sprintf("%.2f%%%s", alert.Threshold.Float64, alertWindowLabel(alert)),
+			CurrentValue: fmt.Sprintf("%.2f%%", value),
+			Timestamp:    now,
+		})
 	}
 }
 
-func checkNetworkUsage(ctx context.Context, repo *db.Repo, nodeId int32, netSend float64, netRecv float64) {
+func checkNetworkUsage(ctx context.Context, repo *db.Repo, nodeId int32, netSend float64, netRecv float64, now time.Time) {
 	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
 		NodeID: int64(nodeId),
 		Metric: "net",
@@ -163,26 +152,65 @@ func checkNetworkUsage(ctx context.Context, repo *db.Repo, nodeId int32, netSend
 	}
 	fmt.Println("Active alerts found", len(alerts))
 	for _, alert := range alerts {
-		if netSend > alert.Threshold.Float64 || netRecv > alert.Threshold.Float64 {
+		sent, recv := netStatWindow(ctx, repo, alert, netSend, netRecv, now)
+		sentValue, sentBreached, sentPendingFor := windowBreach(alert, sent, alert.Threshold.Float64, now)
+		recvValue, recvBreached, recvPendingFor := windowBreach(alert, recv, alert.Threshold.Float64, now)
+		breached := sentBreached || recvBreached
+		pendingFor := max(sentPendingFor, recvPendingFor)
+		if breached {
 			fmt.Println("Network usage exceeded threshold for alert", int32(alert.ID))
-			lastSendTime, ok := lastAlertSentTime[int32(alert.ID)]
-			if ok {
-				if time.Since(lastSendTime).Minutes() < float64(alert.Duration) {
-					fmt.Println(" net Alert already sent within last", alert.Duration, "minutes")
-					continue
-				}
-			}
+		}
+		evaluateAlert(ctx, repo, alert, breached, pendingFor, AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "Network",
+			Threshold:    fmt.Sprintf("Send: %.2f%%, Recv: %.2f%%%s", alert.NetSendThreshold.Float64, alert.NetReceThreshold.Float64, alertWindowLabel(alert)),
+			CurrentValue: fmt.Sprintf("Send: %.2f%%, Recv: %.2f%%", sentValue, recvValue),
+			Timestamp:    now,
+		})
+	}
+}
 
-			lastAlertSentTime[int32(alert.ID)] = time.Now()
-			// send notifications to all configured channels
-			sendAlertNotifications(alert, AlertMsg{
-				NodeName:     alert.NodeName.String,
-				NodeIp:       alert.NodeIp,
-				Metric:       "Network",
-				Threshold:    fmt.Sprintf("Send: %.2f%%, Recv: %.2f%%", alert.NetSendThreshold.Float64, alert.NetReceThreshold.Float64),
-				CurrentValue: fmt.Sprintf("Send: %.2f%%, Recv: %.2f%%", netSend, netRecv),
-				Timestamp:    time.Now(),
-			})
+// checkNodeStatus fires "status" alerts when a node goes offline and resolves them when it comes back
+func checkNodeStatus(ctx context.Context, repo *db.Repo, event NodeStatusEvent, now time.Time) {
+	down := event.Current == NodeStatusOffline
+	recovered := event.Current == NodeStatusOnline && event.Previous == NodeStatusOffline
+	if !down && !recovered {
+		return
+	}
+
+	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
+		NodeID: int64(event.NodeID),
+		Metric: "status",
+	})
+	if err != nil {
+		fmt.Println("Error getting active alerts", err)
+		return
+	}
+	if len(alerts) == 0 {
+		fmt.Println("No active alerts found")
+		return
+	}
+
+	lastSeen := "never"
+	if event.LastSeen > 0 {
+		lastSeen = time.Unix(event.LastSeen, 0).Format(time.RFC1123)
+	}
+	_, offlineTimeout := NodeStatusTimeouts()
+	for _, alert := range alerts {
+		currentValue := fmt.Sprintf("Offline (last seen %s)", lastSeen)
+		if recovered {
+			currentValue = "Back online"
 		}
+		fmt.Println("Node", event.NodeID, "is", event.Current, "for alert", int32(alert.ID))
+		// the offline timeout already is the pending period
+		evaluateAlert(ctx, repo, alert, down, 0, AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "Node Status",
+			Threshold:    fmt.Sprintf("No heartbeat for %s", offlineTimeout),
+			CurrentValue: currentValue,
+			Timestamp:    now,
+		})
 	}
 }
@@ -0,0 +1,328 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// Evaluator owns the state of every alert rule. Samples of one rule are evaluated
+// one at a time and in order, different rules in parallel. States are kept in
+// memory and written through to the alert_states table, a restarted server picks
+// them up from there on the first sample of each rule.
+type Evaluator struct {
+	repo *db.Repo
+
+	// Notify sends the notifications of a transition to the channels the rule had when
+	// it happened, sendAlertNotifications by default
+	Notify func(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, channels []db.NotificationChannel, msg AlertMsg) []NotificationDelivery
+
+	mu    sync.Mutex
+	rules map[int64]*ruleState
+}
+
+type ruleState struct {
+	mu          sync.Mutex
+	loaded      bool
+	state       db.AlertState
+	evaluatedAt time.Time // timestamp of the last sample, older samples are dropped
+
+	// notifications of the rule go out one at a time in the order of its transitions,
+	// so a resolve never overtakes the trigger it closes
+	outboxMu sync.Mutex
+	outbox   []notification
+	sending  bool
+}
+
+// notification is a transition waiting to be sent
+type notification struct {
+	eventId  int64
+	alert    db.GetActiveAlertsByNodeAndMetricRow
+	channels []db.NotificationChannel
+	msg      AlertMsg
+}
+
+var (
+	alertEvaluator     *Evaluator
+	alertEvaluatorOnce sync.Once
+)
+
+// AlertEvaluator returns the evaluator shared by the alert monitor and the services
+func AlertEvaluator(repo *db.Repo) *Evaluator {
+	alertEvaluatorOnce.Do(func() {
+		alertEvaluator = NewEvaluator(repo)
+	})
+	return alertEvaluator
+}
+
+func NewEvaluator(repo *db.Repo) *Evaluator {
+	return &Evaluator{
+		repo: repo,
+		Notify: func(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, channels []db.NotificationChannel, msg AlertMsg) []NotificationDelivery {
+			return sendAlertNotifications(ctx, repo, channels, msg)
+		},
+		rules: make(map[int64]*ruleState),
+	}
+}
+
+// rule returns the state holder of a rule, creating it on first use
+func (e *Evaluator) rule(alertId int64) *ruleState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rule, ok := e.rules[alertId]
+	if !ok {
+		rule = &ruleState{}
+		e.rules[alertId] = rule
+	}
+	return rule
+}
+
+// load reads the stored state of the rule unless it is already in memory.
+// The caller holds rule.mu.
+func (e *Evaluator) load(ctx context.Context, alertId int64, rule *ruleState) error {
+	if rule.loaded {
+		return nil
+	}
+	state, err := e.repo.Queries.GetAlertState(ctx, alertId)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	state.AlertID = alertId
+	if state.State == "" {
+		state.State = AlertStateOk
+	}
+	rule.state = state
+	rule.loaded = true
+	return nil
+}
+
+// State returns the current state of a rule, ok for rules that never breached
+func (e *Evaluator) State(ctx context.Context, alertId int64) (db.AlertState, error) {
+	rule := e.rule(alertId)
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+	if err := e.load(ctx, alertId, rule); err != nil {
+		return db.AlertState{}, err
+	}
+	return rule.state, nil
+}
+
+// Reset forgets the state of a rule, its next breach starts a new incident
+func (e *Evaluator) Reset(ctx context.Context, alertId int64) error {
+	rule := e.rule(alertId)
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+	return e.reset(ctx, alertId, rule)
+}
+
+// reset forgets the state of a rule. The caller holds rule.mu.
+func (e *Evaluator) reset(ctx context.Context, alertId int64, rule *ruleState) error {
+	if err := e.repo.Queries.DeleteAlertState(ctx, alertId); err != nil {
+		return err
+	}
+	rule.loaded = false
+	rule.state = db.AlertState{}
+	return nil
+}
+
+// Resolve ends the incident of a rule that is disabled, deleted or moved to
+// another node or metric, then forgets its state like Reset. A firing rule is
+// resolved with reason as its value, so its channels get the recovery message and
+// incident tools close the incident. Call it before the rule or its channel links
+// are changed, the notification goes to the channels the rule has now.
+func (e *Evaluator) Resolve(ctx context.Context, alert db.Alert, reason string) error {
+	rule := e.rule(alert.ID)
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+	if err := e.load(ctx, alert.ID, rule); err != nil {
+		return fmt.Errorf("failed to load alert state: %w", err)
+	}
+	state := rule.state
+	if state.State != AlertStateFiring {
+		return e.reset(ctx, alert.ID, rule)
+	}
+
+	node, err := e.repo.Queries.GetNode(ctx, alert.NodeID)
+	if err != nil {
+		return fmt.Errorf("failed to get node of alert: %w", err)
+	}
+	row := db.GetActiveAlertsByNodeAndMetricRow{
+		ID:       alert.ID,
+		NodeID:   alert.NodeID,
+		Metric:   alert.Metric,
+		NodeName: node.Name,
+		NodeIp:   node.Ip,
+	}
+	now := time.Now()
+	msg := AlertMsg{
+		NodeName:     node.Name.String,
+		NodeIp:       node.Ip,
+		Metric:       alert.Metric,
+		Threshold:    "-",
+		CurrentValue: reason,
+		Timestamp:    now,
+		Resolved:     true,
+		Lasted:       now.Sub(time.Unix(state.BreachedAt.Int64, 0)),
+		AlertID:      alert.ID,
+		NodeID:       alert.NodeID,
+	}
+	fmt.Println("Alert", alert.ID, "is now", AlertStateResolved, "("+reason+")")
+	eventId := e.recordEvent(ctx, row, state.State, AlertStateResolved, state.Value, state.BreachedAt, msg)
+	e.enqueue(ctx, rule, e.newNotification(ctx, eventId, row, msg))
+	return e.reset(ctx, alert.ID, rule)
+}
+
+// Evaluate applies one evaluation of the rule, stores a changed state and
+// notifies the rule's channels when it starts firing or resolves. While it keeps
+// firing the notification is repeated every resend_interval minutes. msg carries
+// the observed value, breached or not. It returns the rule's state afterwards.
+func (e *Evaluator) Evaluate(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, breached bool, pendingFor time.Duration, msg AlertMsg) (db.AlertState, error) {
+	rule := e.rule(alert.ID)
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+
+	if err := e.load(ctx, alert.ID, rule); err != nil {
+		return db.AlertState{}, fmt.Errorf("failed to load alert state: %w", err)
+	}
+	state := rule.state
+	now := msg.Timestamp
+	msg.AlertID, msg.NodeID = alert.ID, alert.NodeID
+	// samples are checked in their own goroutines and can arrive out of order
+	if now.Before(rule.evaluatedAt) {
+		return state, nil
+	}
+	rule.evaluatedAt = now
+
+	next := NextAlertState(state, breached, now, pendingFor)
+	remind := next.State == AlertStateFiring && state.State == AlertStateFiring && resendDue(alert, state, now)
+	if next.State == state.State && !remind {
+		return state, nil
+	}
+	if next.State == AlertStateFiring || next.State == AlertStateResolved {
+		next.NotifiedAt = sql.NullInt64{Int64: now.Unix(), Valid: true}
+	}
+	next.Value = sql.NullString{String: msg.CurrentValue, Valid: msg.CurrentValue != ""}
+	err := e.repo.Queries.SaveAlertState(ctx, db.SaveAlertStateParams{
+		AlertID:    next.AlertID,
+		State:      next.State,
+		Value:      next.Value,
+		BreachedAt: next.BreachedAt,
+		FiredAt:    next.FiredAt,
+		ResolvedAt: next.ResolvedAt,
+		NotifiedAt: next.NotifiedAt,
+	})
+	if err != nil {
+		return state, fmt.Errorf("failed to save alert state: %w", err)
+	}
+	next.UpdatedAt = now.Unix()
+	rule.state = next
+	if remind {
+		fmt.Println("Alert", alert.ID, "is still firing")
+	} else {
+		fmt.Println("Alert", alert.ID, "is now", next.State)
+	}
+
+	eventId := e.recordEvent(ctx, alert, state.State, next.State, next.Value, next.BreachedAt, msg)
+
+	switch next.State {
+	case AlertStateFiring:
+		e.enqueue(ctx, rule, e.newNotification(ctx, eventId, alert, msg))
+	case AlertStateResolved:
+		msg.Resolved = true
+		msg.Lasted = now.Sub(time.Unix(next.BreachedAt.Int64, 0))
+		e.enqueue(ctx, rule, e.newNotification(ctx, eventId, alert, msg))
+	}
+	return next, nil
+}
+
+// recordEvent stores a transition in the alert history and returns its id, 0 when
+// it could not be stored
+func (e *Evaluator) recordEvent(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, from string, to string, value sql.NullString, breachedAt sql.NullInt64, msg AlertMsg) int64 {
+	event, err := e.repo.Queries.CreateAlertEvent(ctx, db.CreateAlertEventParams{
+		AlertID:    alert.ID,
+		NodeID:     alert.NodeID,
+		Metric:     alert.Metric,
+		Value:      value,
+		Threshold:  sql.NullString{String: msg.Threshold, Valid: msg.Threshold != ""},
+		FromState:  from,
+		ToState:    to,
+		BreachedAt: breachedAt,
+		CreatedAt:  msg.Timestamp.Unix(),
+	})
+	if err != nil {
+		fmt.Println("Error recording alert event", err)
+		return 0
+	}
+	return event.ID
+}
+
+// newNotification prepares the notification of a transition. The rule's channels
+// are read now, a later change of its links does not redirect it.
+func (e *Evaluator) newNotification(ctx context.Context, eventId int64, alert db.GetActiveAlertsByNodeAndMetricRow, msg AlertMsg) notification {
+	channels, err := e.repo.Queries.ListAlertChannels(ctx, alert.ID)
+	if err != nil {
+		fmt.Println("Error getting alert channels", err)
+	}
+	return notification{eventId: eventId, alert: alert, channels: channels, msg: msg}
+}
+
+// enqueue adds a notification to the rule's outbox and starts sending unless a
+// previous notification of the rule is still going out
+func (e *Evaluator) enqueue(ctx context.Context, rule *ruleState, n notification) {
+	rule.outboxMu.Lock()
+	defer rule.outboxMu.Unlock()
+	rule.outbox = append(rule.outbox, n)
+	if !rule.sending {
+		rule.sending = true
+		go e.drain(ctx, rule)
+	}
+}
+
+// drain sends the rule's notifications in order until its outbox is empty
+func (e *Evaluator) drain(ctx context.Context, rule *ruleState) {
+	for {
+		rule.outboxMu.Lock()
+		if len(rule.outbox) == 0 {
+			rule.sending = false
+			rule.outboxMu.Unlock()
+			return
+		}
+		n := rule.outbox[0]
+		rule.outbox = rule.outbox[1:]
+		rule.outboxMu.Unlock()
+		e.notify(ctx, n)
+	}
+}
+
+// notify sends the notifications of a transition and stores how each channel
+// took them on the event
+func (e *Evaluator) notify(ctx context.Context, n notification) {
+	deliveries := e.Notify(ctx, n.alert, n.channels, n.msg)
+	if n.eventId == 0 {
+		return
+	}
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		fmt.Println("Error encoding alert deliveries", err)
+		return
+	}
+	err = e.repo.Queries.SetAlertEventDeliveries(ctx, db.SetAlertEventDeliveriesParams{
+		Deliveries: sql.NullString{String: string(data), Valid: true},
+		ID:         n.eventId,
+	})
+	if err != nil {
+		fmt.Println("Error saving alert deliveries", err)
+	}
+}
+
+// evaluateAlert evaluates the rule with the shared evaluator
+func evaluateAlert(ctx context.Context, repo *db.Repo, alert db.GetActiveAlertsByNodeAndMetricRow, breached bool, pendingFor time.Duration, msg AlertMsg) {
+	if _, err := AlertEvaluator(repo).Evaluate(ctx, alert, breached, pendingFor, msg); err != nil {
+		fmt.Println("Error evaluating alert", alert.ID, err)
+	}
+}
@@ -20,6 +20,33 @@ type AlertMsg struct {
 	Threshold    string
 	CurrentValue string
 	Timestamp    time.Time
+	Resolved     bool          // the message announces the end of an incident
+	Lasted       time.Duration // how long the resolved incident lasted
+	AlertID      int64         // rule that produced the message, set by the evaluator
+	NodeID       int64
+}
+
+// LastedString formats how long a resolved incident lasted, to the second
+func (a AlertMsg) LastedString() string {
+	return a.Lasted.Round(time.Second).String()
+}
+
+// DedupKey identifies the incident of a rule on a node. Every message of the
+// incident, reminders and the resolve included, carries the same key so incident
+// tools group them. Messages not sent by a rule fall back to node and metric.
+func (a AlertMsg) DedupKey() string {
+	if a.AlertID == 0 {
+		return fmt.Sprintf("vps-pilot:%s:%s", a.NodeName, a.Metric)
+	}
+	return fmt.Sprintf("vps-pilot:alert-%d:node-%d", a.AlertID, a.NodeID)
+}
+
+// Status is firing, or resolved when the message ends an incident
+func (a AlertMsg) Status() string {
+	if a.Resolved {
+		return AlertStateResolved
+	}
+	return AlertStateFiring
 }
 
 func SendDiscordAlert(webhookURL string, alert AlertMsg) error {
@@ -28,6 +55,12 @@ func SendDiscordAlert(webhookURL string, alert AlertMsg) error {
 		"🚨 **ALERT** 🚨\nNode: `%s`\nIP: `%s`\nMetric: **%s**\nCurrent Value: `%s`\nThreshold: `%s`\nTimestamp: %s",
 		alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.Threshold, alert.Timestamp.Format(time.RFC1123),
 	)
+	if alert.Resolved {
+		message = fmt.Sprintf(
+			"✅ **RESOLVED** ✅\nNode: `%s`\nIP: `%s`\nMetric: **%s**\nCurrent Value: `%s`\nThreshold: `%s`\nLasted: %s\nTimestamp: %s",
+			alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.Threshold, alert.LastedString(), alert.Timestamp.Format(time.RFC1123),
+		)
+	}
 
 	// Prepare the payload
 	payload := map[string]string{
@@ -79,25 +112,31 @@ func SendEmailAlert(toEmail string, alert AlertMsg) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", fromAddress)
 	m.SetHeader("To", toEmail)
-	m.SetHeader("Subject", fmt.Sprintf("🚨 VPS Pilot Alert - %s", alert.Metric))
+	subject, heading, color, lasted := "🚨 VPS Pilot Alert", "🚨 ALERT 🚨", "#e74c3c", ""
+	if alert.Resolved {
+		subject, heading, color = "✅ VPS Pilot Resolved", "✅ RESOLVED ✅", "#27ae60"
+		lasted = fmt.Sprintf("<p><strong>Lasted:</strong> %s</p>", alert.LastedString())
+	}
+	m.SetHeader("Subject", fmt.Sprintf("%s - %s", subject, alert.Metric))
 
 	// HTML email body
 	body := fmt.Sprintf(`
 	<html>
 	<body>
-		<h2 style="color: #e74c3c;">🚨 ALERT 🚨</h2>
-		<div style="background-color: #f8f9fa; padding: 20px; border-left: 4px solid #e74c3c;">
+		<h2 style="color: %s;">%s</h2>
+		<div style="background-color: #f8f9fa; padding: 20px; border-left: 4px solid %s;">
 			<p><strong>Node:</strong> %s</p>
 			<p><strong>IP:</strong> %s</p>
 			<p><strong>Metric:</strong> %s</p>
 			<p><strong>Current Value:</strong> %s</p>
 			<p><strong>Threshold:</strong> %s</p>
+			%s
 			<p><strong>Timestamp:</strong> %s</p>
 		</div>
 		<p style="color: #6c757d; font-size: 12px;">This alert was generated by VPS Pilot monitoring system.</p>
 	</body>
 	</html>
-	`, alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.Threshold, alert.Timestamp.Format(time.RFC1123))
+	`, color, heading, color, alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.Threshold, lasted, alert.Timestamp.Format(time.RFC1123))
 
 	m.SetBody("text/html", body)
 
@@ -121,13 +160,21 @@ func SendSlackAlert(webhookURL string, alert AlertMsg) error {
 		":rotating_light: *ALERT* :rotating_light:\n*Node:* `%s`\n*IP:* `%s`\n*Metric:* *%s*\n*Current Value:* `%s`\n*Threshold:* `%s`\n*Timestamp:* %s",
 		alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.Threshold, alert.Timestamp.Format(time.RFC1123),
 	)
+	color := "danger"
+	if alert.Resolved {
+		message = fmt.Sprintf(
+			":white_check_mark: *RESOLVED* :white_check_mark:\n*Node:* `%s`\n*IP:* `%s`\n*Metric:* *%s*\n*Current Value:* `%s`\n*Lasted:* %s\n*Timestamp:* %s",
+			alert.NodeName, alert.NodeIp, alert.Metric, alert.CurrentValue, alert.LastedString(), alert.Timestamp.Format(time.RFC1123),
+		)
+		color = "good"
+	}
 
 	// Prepare the Slack payload
 	payload := map[string]interface{}{
 		"text": message,
 		"attachments": []map[string]interface{}{
 			{
-				"color": "danger",
+				"color": color,
 				"fields": []map[string]interface{}{
 					{
 						"title": "Node",
@@ -186,3 +233,12 @@ func SendSlackAlert(webhookURL string, alert AlertMsg) error {
 
 	return nil
 }
+
+// NotificationDelivery is the outcome of sending a message to one channel
+type NotificationDelivery struct {
+	Channel   string `json:"channel"`              // channel type
+	ChannelID int64  `json:"channel_id,omitempty"` // notification channel
+	Name      string `json:"name,omitempty"`
+	Status    string `json:"status"` // sent or failed
+	Error     string `json:"error,omitempty"`
+}
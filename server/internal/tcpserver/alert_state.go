@@ -0,0 +1,58 @@
+package tcpserver
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+const (
+	AlertStateOk       = "ok"
+	AlertStatePending  = "pending"
+	AlertStateFiring   = "firing"
+	AlertStateResolved = "resolved"
+)
+
+// NextAlertState moves a rule's state on for one evaluation. A breach is pending
+// until it has lasted pendingFor, then the rule fires. The first evaluation
+// without a breach resolves a firing rule and clears a pending one.
+func NextAlertState(state db.AlertState, breached bool, now time.Time, pendingFor time.Duration) db.AlertState {
+	next := state
+	at := sql.NullInt64{Int64: now.Unix(), Valid: true}
+	switch {
+	case breached && (state.State == AlertStateOk || state.State == AlertStateResolved || state.State == ""):
+		next.BreachedAt = at
+		next.FiredAt = sql.NullInt64{}
+		next.ResolvedAt = sql.NullInt64{}
+		next.State = AlertStatePending
+		if pendingFor <= 0 {
+			next.State = AlertStateFiring
+			next.FiredAt = at
+		}
+	case breached && state.State == AlertStatePending:
+		if now.Sub(time.Unix(state.BreachedAt.Int64, 0)) >= pendingFor {
+			next.State = AlertStateFiring
+			next.FiredAt = at
+		}
+	case !breached && state.State == AlertStatePending:
+		next.State = AlertStateOk
+		next.BreachedAt = sql.NullInt64{}
+	case !breached && state.State == AlertStateFiring:
+		next.State = AlertStateResolved
+		next.ResolvedAt = at
+	}
+	return next
+}
+
+// resendDue reports whether a firing rule is due for a reminder
+func resendDue(alert db.GetActiveAlertsByNodeAndMetricRow, state db.AlertState, now time.Time) bool {
+	if alert.ResendInterval <= 0 {
+		return false
+	}
+	last := state.NotifiedAt.Int64
+	if !state.NotifiedAt.Valid {
+		last = state.FiredAt.Int64
+	}
+	return now.Sub(time.Unix(last, 0)) >= time.Duration(alert.ResendInterval)*time.Minute
+}
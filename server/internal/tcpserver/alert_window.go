@@ -0,0 +1,182 @@
+package tcpserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// Aggregations reduce the samples in an alert's window to the value compared against the threshold
+const (
+	AggregationAll = "all" // every sample must breach
+	AggregationAvg = "avg"
+	AggregationMax = "max"
+	AggregationP95 = "p95"
+)
+
+// windowCoverageSlack is how far after the start of the window the oldest sample may be
+// for the window to count as covered, samples do not arrive on exact boundaries
+const windowCoverageSlack = 30 * time.Second
+
+// WindowSample is one value of a metric inside an alert's window
+type WindowSample struct {
+	Timestamp int64
+	Value     float64
+}
+
+// AggregateWindow reduces the values with the given aggregation. For "all" it is the
+// lowest value, which is over the threshold only when every value is.
+func AggregateWindow(values []float64, aggregation string) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	switch aggregation {
+	case AggregationAvg:
+		return average(values)
+	case AggregationMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			max = math.Max(max, v)
+		}
+		return max
+	case AggregationP95:
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+		// nearest rank
+		rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+		return sorted[rank]
+	default:
+		min := values[0]
+		for _, v := range values[1:] {
+			min = math.Min(min, v)
+		}
+		return min
+	}
+}
+
+// EvaluateWindow aggregates the samples of the window ending at now and compares the
+// result against threshold. covered reports whether the samples reach back to the
+// start of the window, a breach seen over less history than that is not yet sustained.
+func EvaluateWindow(samples []WindowSample, aggregation string, threshold float64, window time.Duration, now time.Time) (value float64, breached bool, covered bool) {
+	if len(samples) == 0 {
+		return 0, false, false
+	}
+	values := make([]float64, len(samples))
+	oldest := samples[0].Timestamp
+	for i, sample := range samples {
+		values[i] = sample.Value
+		if sample.Timestamp < oldest {
+			oldest = sample.Timestamp
+		}
+	}
+	value = AggregateWindow(values, aggregation)
+
+	slack := windowCoverageSlack
+	if slack > window/2 {
+		slack = window / 2
+	}
+	start := now.Add(-window)
+	covered = !time.Unix(oldest, 0).After(start.Add(slack))
+	return value, value > threshold, covered
+}
+
+// alertWindow is how long a breach must be sustained before the rule fires
+func alertWindow(alert db.GetActiveAlertsByNodeAndMetricRow) time.Duration {
+	return time.Duration(alert.Duration) * time.Minute
+}
+
+// windowBreach evaluates the rule over its window and returns the value to report,
+// whether it breaches and how much longer the breach must stay pending. A covered
+// window already holds the sustained breach, otherwise the breach waits out the window.
+func windowBreach(alert db.GetActiveAlertsByNodeAndMetricRow, samples []WindowSample, threshold float64, now time.Time) (float64, bool, time.Duration) {
+	window := alertWindow(alert)
+	if window <= 0 {
+		current := samples[len(samples)-1].Value
+		return current, current > threshold, 0
+	}
+	value, breached, covered := EvaluateWindow(samples, alert.Aggregation, threshold, window, now)
+	if covered {
+		return value, breached, 0
+	}
+	return value, breached, window
+}
+
+// alertWindowLabel describes the window of the rule for notifications
+func alertWindowLabel(alert db.GetActiveAlertsByNodeAndMetricRow) string {
+	if alert.Duration <= 0 {
+		return ""
+	}
+	if alert.Aggregation == AggregationAll || alert.Aggregation == "" {
+		return fmt.Sprintf(" for %dm", alert.Duration)
+	}
+	return fmt.Sprintf(" (%s over %dm)", alert.Aggregation, alert.Duration)
+}
+
+// systemStatWindow returns the cpu or mem samples stored for the rule's window, followed
+// by the sample being evaluated. Cpu samples are averaged over the cores.
+func systemStatWindow(ctx context.Context, repo *db.Repo, alert db.GetActiveAlertsByNodeAndMetricRow, statType string, current float64, now time.Time) []WindowSample {
+	var samples []WindowSample
+	if window := alertWindow(alert); window > 0 {
+		rows, err := repo.TimeseriesQueries.GetSystemStatWindow(ctx, db.GetSystemStatWindowParams{
+			NodeID:      alert.NodeID,
+			StatType:    statType,
+			Timestamp:   now.Add(-window).Unix(),
+			Timestamp_2: now.Unix(),
+		})
+		if err != nil {
+			fmt.Println("Error getting system stats", err)
+		}
+		for _, row := range rows {
+			samples = append(samples, WindowSample{Timestamp: row.Timestamp, Value: row.Value})
+		}
+	}
+	return append(samples, WindowSample{Timestamp: now.Unix(), Value: current})
+}
+
+// netStatWindow returns the sent and received samples stored for the rule's window,
+// followed by the sample being evaluated
+func netStatWindow(ctx context.Context, repo *db.Repo, alert db.GetActiveAlertsByNodeAndMetricRow, netSend float64, netRecv float64, now time.Time) ([]WindowSample, []WindowSample) {
+	var sent, recv []WindowSample
+	if window := alertWindow(alert); window > 0 {
+		rows, err := repo.TimeseriesQueries.GetNetStatWindow(ctx, db.GetNetStatWindowParams{
+			NodeID:      alert.NodeID,
+			Timestamp:   now.Add(-window).Unix(),
+			Timestamp_2: now.Unix(),
+		})
+		if err != nil {
+			fmt.Println("Error getting net stats", err)
+		}
+		for _, row := range rows {
+			sent = append(sent, WindowSample{Timestamp: row.Timestamp, Value: float64(row.Sent)})
+			recv = append(recv, WindowSample{Timestamp: row.Timestamp, Value: float64(row.Recv)})
+		}
+	}
+	sent = append(sent, WindowSample{Timestamp: now.Unix(), Value: netSend})
+	recv = append(recv, WindowSample{Timestamp: now.Unix(), Value: netRecv})
+	return sent, recv
+}
+
+// diskStatWindow returns the used percent samples of a mount stored for the rule's
+// window, followed by the sample being evaluated
+func diskStatWindow(ctx context.Context, repo *db.Repo, alert db.GetActiveAlertsByNodeAndMetricRow, mountpoint string, current float64, now time.Time) []WindowSample {
+	var samples []WindowSample
+	if window := alertWindow(alert); window > 0 {
+		rows, err := repo.TimeseriesQueries.GetMountDiskStatWindow(ctx, db.GetMountDiskStatWindowParams{
+			NodeID:      alert.NodeID,
+			Mountpoint:  mountpoint,
+			Timestamp:   now.Add(-window).Unix(),
+			Timestamp_2: now.Unix(),
+		})
+		if err != nil {
+			fmt.Println("Error getting disk stats", err)
+		}
+		for _, row := range rows {
+			samples = append(samples, WindowSample{Timestamp: row.Timestamp, Value: row.UsedPercent})
+		}
+	}
+	return append(samples, WindowSample{Timestamp: now.Unix(), Value: current})
+}
@@ -0,0 +1,85 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+var (
+	ErrInvalidAgentToken = errors.New("invalid agent token")
+	ErrRevokedAgentToken = errors.New("agent token has been revoked")
+	ErrExpiredAgentToken = errors.New("agent token has expired")
+	// ErrAlreadyAuthenticated rejects a second "connected" handshake on a connection
+	ErrAlreadyAuthenticated = errors.New("connection is already authenticated")
+	// ErrNodeAlreadyEnrolled rejects an unbound token presented by a machine that is
+	// already a node, it needs a token created for that node
+	ErrNodeAlreadyEnrolled = errors.New("machine is already registered as a node, use a token bound to that node")
+)
+
+// AuthenticateAgent validates the enrollment token presented in the agent handshake
+func AuthenticateAgent(ctx context.Context, repo *db.Repo, token string) (*db.AgentToken, error) {
+	if token == "" {
+		return nil, ErrInvalidAgentToken
+	}
+
+	agentToken, err := repo.Queries.GetAgentTokenByHash(ctx, utils.HashAgentToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAgentToken
+		}
+		return nil, err
+	}
+
+	if agentToken.RevokedAt.Valid {
+		return nil, ErrRevokedAgentToken
+	}
+	if agentToken.ExpiresAt.Valid && time.Now().Unix() >= agentToken.ExpiresAt.Int64 {
+		return nil, ErrExpiredAgentToken
+	}
+
+	return &agentToken, nil
+}
+
+// RegisterAgent resolves the node an authenticated agent belongs to.
+// A token that is not yet bound to a node enrolls a new node on first use.
+func RegisterAgent(ctx context.Context, repo *db.Repo, agentToken *db.AgentToken, ip string, data []byte) (*db.Node, error) {
+	sysInfo := SystemInfo{}
+	if err := sysInfo.FromBytes(data); err != nil {
+		fmt.Println("Error unmarshalling system info", err)
+	}
+
+	var node *db.Node
+	if agentToken.NodeID.Valid {
+		existing, err := repo.Queries.GetNode(ctx, agentToken.NodeID.Int64)
+		if err != nil {
+			return nil, fmt.Errorf("node bound to token not found: %w", err)
+		}
+		node = &existing
+	} else {
+		created, err := CreateNode(ctx, repo, sysInfo.MachineID, ip)
+		if err != nil {
+			return nil, err
+		}
+		node = created
+	}
+
+	if err := BindNodeIdentity(ctx, repo, node, sysInfo.MachineID, ip); err != nil {
+		return nil, err
+	}
+
+	err := repo.Queries.BindAgentToken(ctx, db.BindAgentTokenParams{
+		NodeID: sql.NullInt64{Int64: node.ID, Valid: true},
+		ID:     agentToken.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind token to node: %w", err)
+	}
+
+	return node, nil
+}
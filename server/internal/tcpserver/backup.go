@@ -0,0 +1,170 @@
+package tcpserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	restoreChunkSize  = 256 << 10
+	backupIdleTimeout = 10 * time.Minute // longest wait for the next message from the agent, dumps can be slow to start
+)
+
+var ErrBackupChecksum = errors.New("backup archive does not match the checksum reported by the agent")
+
+// BackupRequest is the Data of a "backup" message. The agent replies with the zip as
+// raw "backup_chunk" messages, then a "backup_result".
+type BackupRequest struct {
+	Dir    string       `json:"dir"` // project directory, paths in Config are relative to it
+	Config BackupConfig `json:"config"`
+}
+
+// BackupResult is the Data of the "backup_result" reply
+type BackupResult struct {
+	Ok       bool     `json:"ok"`
+	Size     int64    `json:"size"`
+	Sha256   string   `json:"sha256"`
+	Contents []string `json:"contents"` // entries in the archive, e.g. ".env", "storage/app", "database.sql"
+	Error    string   `json:"error,omitempty"`
+}
+
+// RestoreRequest is the Data of a "restore" message. The server follows it with the zip
+// as raw "restore_chunk" messages and an empty "restore_end", the agent then replies "restore_result".
+type RestoreRequest struct {
+	Dir             string       `json:"dir"`
+	Config          BackupConfig `json:"config"`
+	Size            int64        `json:"size"`
+	Sha256          string       `json:"sha256"`
+	RestoreDatabase bool         `json:"restore_database"` // import the dump into the database named in the env file
+}
+
+// RestoreResult is the Data of the "restore_result" reply
+type RestoreResult struct {
+	Ok       bool     `json:"ok"`
+	Restored []string `json:"restored"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// RunBackup asks the node's agent for a backup archive and writes it to w as it arrives
+func RunBackup(ctx context.Context, nodeId int32, req BackupRequest, w io.Writer) (*BackupResult, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := DispatchMsg(nodeId, "backup", data)
+	if err != nil {
+		return nil, err
+	}
+	defer pending.Close()
+
+	hash := sha256.New()
+	var size int64
+	idle := time.NewTimer(backupIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-idle.C:
+			return nil, ErrCommandTimeout
+		case reply := <-pending.Replies:
+			idle.Reset(backupIdleTimeout)
+			switch reply.Msg {
+			case "disconnected", replyOverflow:
+				return nil, replyError(reply)
+			case "backup_chunk":
+				if _, err := w.Write(reply.Data); err != nil {
+					return nil, fmt.Errorf("failed to store backup: %w", err)
+				}
+				hash.Write(reply.Data)
+				size += int64(len(reply.Data))
+			case "backup_result":
+				result := BackupResult{}
+				if err := json.Unmarshal(reply.Data, &result); err != nil {
+					return nil, fmt.Errorf("invalid reply from agent: %w", err)
+				}
+				if !result.Ok {
+					return &result, errors.New(result.Error)
+				}
+				if result.Size != size || (result.Sha256 != "" && result.Sha256 != hex.EncodeToString(hash.Sum(nil))) {
+					return &result, ErrBackupChecksum
+				}
+				result.Sha256 = hex.EncodeToString(hash.Sum(nil))
+				return &result, nil
+			}
+		}
+	}
+}
+
+// RunRestore streams a backup archive read from r to the node's agent and waits for it to be restored
+func RunRestore(ctx context.Context, nodeId int32, req RestoreRequest, r io.Reader) (*RestoreResult, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := DispatchMsg(nodeId, "restore", data)
+	if err != nil {
+		return nil, err
+	}
+	defer pending.Close()
+
+	buf := make([]byte, restoreChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			err := SendToNode(nodeId, Msg{Msg: "restore_chunk", CorrelationId: pending.CorrelationId, Data: buf[:n]})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", readErr)
+		}
+		// the agent gives up early when it cannot restore, e.g. the directory is not writable
+		select {
+		case reply := <-pending.Replies:
+			return restoreReply(reply)
+		default:
+		}
+	}
+	if err := SendToNode(nodeId, Msg{Msg: "restore_end", CorrelationId: pending.CorrelationId}); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(backupIdleTimeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, ErrCommandTimeout
+	case reply := <-pending.Replies:
+		return restoreReply(reply)
+	}
+}
+
+func restoreReply(reply Msg) (*RestoreResult, error) {
+	if err := replyError(reply); err != nil {
+		return nil, err
+	}
+	if reply.Msg != "restore_result" {
+		return nil, fmt.Errorf("unexpected %q reply from agent", reply.Msg)
+	}
+	result := RestoreResult{}
+	if err := json.Unmarshal(reply.Data, &result); err != nil {
+		return nil, fmt.Errorf("invalid reply from agent: %w", err)
+	}
+	if !result.Ok {
+		return &result, errors.New(result.Error)
+	}
+	return &result, nil
+}
@@ -0,0 +1,312 @@
+package tcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTelegramBaseURL = "https://api.telegram.org"
+	defaultNtfyBaseURL     = "https://ntfy.sh"
+)
+
+// TelegramConfig is the config of a telegram notification channel. Messages go
+// through the Bot API sendMessage method of BaseURL.
+type TelegramConfig struct {
+	BaseURL  string `json:"base_url"` // https://api.telegram.org by default
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"` // numeric ID or @channelusername
+	Silent   bool   `json:"silent"`  // deliver without a sound
+}
+
+// TeamsConfig is the config of a teams notification channel, an incoming webhook
+// or workflow URL that accepts Adaptive Cards
+type TeamsConfig struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+// NtfyConfig is the config of an ntfy notification channel
+type NtfyConfig struct {
+	BaseURL  string `json:"base_url"` // https://ntfy.sh by default
+	Topic    string `json:"topic"`
+	Token    string `json:"token"`    // access token, for protected topics
+	Priority int    `json:"priority"` // 1 to 5, 4 for alerts and 3 for resolved by default
+}
+
+// GotifyConfig is the config of a gotify notification channel
+type GotifyConfig struct {
+	BaseURL  string `json:"base_url"`
+	Token    string `json:"token"`    // application token
+	Priority int    `json:"priority"` // 1 to 10, 8 for alerts and 4 for resolved by default
+}
+
+// alertFact is one labelled field of an alert message
+type alertFact struct {
+	Name  string
+	Value string
+}
+
+// alertHeadline is the plain headline of a message, "ALERT: cpu on web-1"
+func alertHeadline(alert AlertMsg) string {
+	if alert.Resolved {
+		return fmt.Sprintf("RESOLVED: %s on %s", alert.Metric, alert.NodeName)
+	}
+	return fmt.Sprintf("ALERT: %s on %s", alert.Metric, alert.NodeName)
+}
+
+// alertTitle is the headline with an emoji, for channels that show no icon of their own
+func alertTitle(alert AlertMsg) string {
+	if alert.Resolved {
+		return "✅ " + alertHeadline(alert)
+	}
+	return "🚨 " + alertHeadline(alert)
+}
+
+// alertFacts are the fields of a message in display order
+func alertFacts(alert AlertMsg) []alertFact {
+	facts := []alertFact{
+		{"Node", alert.NodeName},
+		{"IP", alert.NodeIp},
+		{"Metric", alert.Metric},
+		{"Current Value", alert.CurrentValue},
+		{"Threshold", alert.Threshold},
+	}
+	if alert.Resolved {
+		facts = append(facts, alertFact{"Lasted", alert.LastedString()})
+	}
+	return append(facts, alertFact{"Timestamp", alert.Timestamp.Format(time.RFC1123)})
+}
+
+// alertMarkdown formats the fields of a message as markdown lines
+func alertMarkdown(alert AlertMsg) string {
+	lines := []string{}
+	for _, fact := range alertFacts(alert) {
+		lines = append(lines, fmt.Sprintf("**%s:** %s", fact.Name, fact.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// postJSON posts payload as JSON with the default retry policy of webhooks
+func postJSON(url string, headers map[string]string, payload interface{}) ([]WebhookAttempt, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: defaultWebhookTimeout}
+	retry := WebhookRetry{MaxAttempts: defaultWebhookMaxAttempts, Backoff: defaultWebhookBackoff}
+	return sendWithRetry(client, retry, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "VPS-Pilot")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req, nil
+	})
+}
+
+func (c *TelegramConfig) validate() error {
+	if c.BaseURL == "" {
+		c.BaseURL = defaultTelegramBaseURL
+	}
+	if err := checkHTTPURL("base_url", c.BaseURL); err != nil {
+		return err
+	}
+	if c.BotToken == "" {
+		return fmt.Errorf("bot_token is required")
+	}
+	if c.ChatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	return nil
+}
+
+// SendTelegramAlert sends alert through a Telegram bot as an HTML formatted message
+func SendTelegramAlert(config TelegramConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	lines := []string{fmt.Sprintf("<b>%s</b>", html.EscapeString(alertTitle(alert)))}
+	for _, fact := range alertFacts(alert) {
+		lines = append(lines, fmt.Sprintf("<b>%s:</b> <code>%s</code>", fact.Name, html.EscapeString(fact.Value)))
+	}
+	payload := map[string]interface{}{
+		"chat_id":              config.ChatID,
+		"text":                 strings.Join(lines, "\n"),
+		"parse_mode":           "HTML",
+		"disable_notification": config.Silent,
+	}
+
+	url := strings.TrimRight(config.BaseURL, "/") + "/bot" + config.BotToken + "/sendMessage"
+	attempts, err := postJSON(url, nil, payload)
+	// the bot token is part of the URL, keep it out of the delivery log
+	for i := range attempts {
+		attempts[i].URL = strings.ReplaceAll(attempts[i].URL, config.BotToken, "<token>")
+		attempts[i].Error = strings.ReplaceAll(attempts[i].Error, config.BotToken, "<token>")
+	}
+	if err != nil {
+		return attempts, errors.New(strings.ReplaceAll(err.Error(), config.BotToken, "<token>"))
+	}
+
+	fmt.Println("Alert sent to Telegram!")
+	return attempts, nil
+}
+
+func (c *TeamsConfig) validate() error {
+	return checkHTTPURL("webhook_url", c.WebhookURL)
+}
+
+// SendTeamsAlert sends alert to a Microsoft Teams incoming webhook as an Adaptive Card
+func SendTeamsAlert(config TeamsConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	color := "Attention"
+	if alert.Resolved {
+		color = "Good"
+	}
+	facts := []map[string]string{}
+	for _, fact := range alertFacts(alert) {
+		facts = append(facts, map[string]string{"title": fact.Name, "value": fact.Value})
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []map[string]interface{}{
+			{
+				"type":   "TextBlock",
+				"text":   alertTitle(alert),
+				"size":   "Medium",
+				"weight": "Bolder",
+				"color":  color,
+				"wrap":   true,
+			},
+			{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+
+	attempts, err := postJSON(config.WebhookURL, nil, payload)
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to Teams!")
+	return attempts, nil
+}
+
+func (c *NtfyConfig) validate() error {
+	if c.BaseURL == "" {
+		c.BaseURL = defaultNtfyBaseURL
+	}
+	if err := checkHTTPURL("base_url", c.BaseURL); err != nil {
+		return err
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if c.Priority < 0 || c.Priority > 5 {
+		return fmt.Errorf("priority must be between 1 and 5")
+	}
+	return nil
+}
+
+// SendNtfyAlert publishes alert to an ntfy topic as a markdown message
+func SendNtfyAlert(config NtfyConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	priority, tag := 4, "rotating_light"
+	if alert.Resolved {
+		priority, tag = 3, "white_check_mark"
+	}
+	if config.Priority > 0 {
+		priority = config.Priority
+	}
+	payload := map[string]interface{}{
+		"topic":    config.Topic,
+		"title":    alertHeadline(alert), // the tag shows the emoji
+		"message":  alertMarkdown(alert),
+		"markdown": true,
+		"priority": priority,
+		"tags":     []string{tag},
+	}
+	headers := map[string]string{}
+	if config.Token != "" {
+		headers["Authorization"] = "Bearer " + config.Token
+	}
+
+	// JSON messages are published to the root URL, the topic is in the body
+	attempts, err := postJSON(strings.TrimRight(config.BaseURL, "/")+"/", headers, payload)
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to ntfy!")
+	return attempts, nil
+}
+
+func (c *GotifyConfig) validate() error {
+	if err := checkHTTPURL("base_url", c.BaseURL); err != nil {
+		return err
+	}
+	if c.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	if c.Priority < 0 || c.Priority > 10 {
+		return fmt.Errorf("priority must be between 1 and 10")
+	}
+	return nil
+}
+
+// SendGotifyAlert sends alert to a Gotify server as a markdown message
+func SendGotifyAlert(config GotifyConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	priority := 8
+	if alert.Resolved {
+		priority = 4
+	}
+	if config.Priority > 0 {
+		priority = config.Priority
+	}
+	payload := map[string]interface{}{
+		"title":    alertTitle(alert),
+		"message":  alertMarkdown(alert),
+		"priority": priority,
+		"extras": map[string]interface{}{
+			"client::display": map[string]string{"contentType": "text/markdown"},
+		},
+	}
+	headers := map[string]string{"X-Gotify-Key": config.Token}
+
+	attempts, err := postJSON(strings.TrimRight(config.BaseURL, "/")+"/message", headers, payload)
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to Gotify!")
+	return attempts, nil
+}
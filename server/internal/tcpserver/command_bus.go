@@ -0,0 +1,395 @@
+package tcpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	CommandPing           = "ping"
+	CommandRestartService = "restart_service"
+)
+
+// KnownCommands lists the command types agents understand
+var KnownCommands = map[string]bool{
+	CommandPing:           true,
+	CommandRestartService: true,
+}
+
+const DefaultCommandTimeout = 30 * time.Second
+
+var (
+	ErrNodeNotConnected = errors.New("node is not connected")
+	ErrCommandTimeout   = errors.New("timed out waiting for agent reply")
+	ErrUnknownCommand   = errors.New("unknown command type")
+	ErrReplyOverflow    = errors.New("agent sent more replies than the reply window allows")
+)
+
+// replyOverflow is the last message of a command whose agent ignored the reply window, see routeReply
+const replyOverflow = "reply_overflow"
+
+// ReplyWindow is how many replies to one command an agent may have in flight. A reply
+// is in flight from when the agent sends it until the server acknowledges it with a
+// "reply_ack", which it does as the caller reads them. This is how a slow reader, such
+// as a backup writing to disk or a followed log, slows the agent down instead of
+// holding up its connection.
+const ReplyWindow = 64
+
+// ReplyAck is the Data of a "reply_ack" message, sent with the correlation id of the command
+type ReplyAck struct {
+	Read int `json:"read"` // replies the caller has read so far
+}
+
+// Command is sent to an agent in the Data of a "command" message
+type Command struct {
+	Type string            `json:"type"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// CommandResult is the Data of the "command_result" reply from an agent
+type CommandResult struct {
+	Ok     bool            `json:"ok"`
+	Output string          `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+func (r *CommandResult) FromBytes(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// AgentConn is an authenticated agent connection. Writes are serialized because the
+// connection handler and the command bus share the gob encoder.
+type AgentConn struct {
+	NodeId     int32
+	conn       net.Conn
+	encoder    *gob.Encoder
+	credential agentCredential
+	mu         sync.Mutex
+}
+
+// agentCredential is the token or client certificate a connection authenticated with,
+// revoking it closes the connection
+type agentCredential struct {
+	tokenId int64
+	certId  int64
+}
+
+func (a *AgentConn) Send(msg Msg) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	defer a.conn.SetWriteDeadline(time.Time{})
+	return a.encoder.Encode(msg)
+}
+
+// PendingCommand receives every message an agent sends back with its correlation id.
+// Long running commands may reply more than once, Close must be called when done.
+type PendingCommand struct {
+	CorrelationId string
+	NodeId        int32
+	Replies       chan Msg
+	agentConn     *AgentConn // the connection the command was sent on
+	done          chan struct{}
+	closeOnce     sync.Once
+
+	mu       sync.Mutex
+	queue    []Msg // replies routed but not yet read from Replies
+	failed   bool  // a terminal message is queued, later replies are dropped
+	received chan struct{}
+}
+
+func (p *PendingCommand) Close() {
+	p.closeOnce.Do(func() {
+		pendingCommandsMu.Lock()
+		delete(pendingCommands, p.CorrelationId)
+		pendingCommandsMu.Unlock()
+		close(p.done)
+	})
+}
+
+var (
+	AgentConnections   = make(map[int32]*AgentConn)
+	agentConnectionsMu sync.RWMutex
+
+	pendingCommands   = make(map[string]*PendingCommand)
+	pendingCommandsMu sync.Mutex
+)
+
+func registerAgentConn(nodeId int32, conn net.Conn, encoder *gob.Encoder, credential agentCredential) *AgentConn {
+	agentConn := &AgentConn{
+		NodeId:     nodeId,
+		conn:       conn,
+		encoder:    encoder,
+		credential: credential,
+	}
+	agentConnectionsMu.Lock()
+	if previous, ok := AgentConnections[nodeId]; ok {
+		// the node reconnected, the old socket is stale
+		previous.conn.Close()
+	}
+	AgentConnections[nodeId] = agentConn
+	agentConnectionsMu.Unlock()
+	return agentConn
+}
+
+func unregisterAgentConn(agentConn *AgentConn) {
+	agentConnectionsMu.Lock()
+	defer agentConnectionsMu.Unlock()
+	if AgentConnections[agentConn.NodeId] == agentConn {
+		delete(AgentConnections, agentConn.NodeId)
+	}
+}
+
+// DisconnectAgentToken closes the connections that authenticated with the agent token
+func DisconnectAgentToken(tokenId int64) {
+	disconnectAgents(func(credential agentCredential) bool { return credential.tokenId == tokenId })
+}
+
+// DisconnectAgentCertificate closes the connections that authenticated with the client certificate
+func DisconnectAgentCertificate(certId int64) {
+	disconnectAgents(func(credential agentCredential) bool { return credential.certId == certId })
+}
+
+func disconnectAgents(match func(agentCredential) bool) {
+	agentConnectionsMu.RLock()
+	defer agentConnectionsMu.RUnlock()
+	for nodeId, agentConn := range AgentConnections {
+		if match(agentConn.credential) {
+			fmt.Println("Closing the connection of node", nodeId, "its credential was revoked")
+			agentConn.conn.Close()
+		}
+	}
+}
+
+// IsNodeConnected reports whether an agent for the node is currently connected
+func IsNodeConnected(nodeId int32) bool {
+	agentConnectionsMu.RLock()
+	defer agentConnectionsMu.RUnlock()
+	_, ok := AgentConnections[nodeId]
+	return ok
+}
+
+// SendToNode writes a message to the node's agent as is
+func SendToNode(nodeId int32, msg Msg) error {
+	agentConnectionsMu.RLock()
+	agentConn, ok := AgentConnections[nodeId]
+	agentConnectionsMu.RUnlock()
+	if !ok {
+		return ErrNodeNotConnected
+	}
+	msg.NodeId = nodeId
+	return agentConn.Send(msg)
+}
+
+// DispatchMsg sends a message of the given type to the node's agent under a new correlation id.
+func DispatchMsg(nodeId int32, msgType string, data []byte) (*PendingCommand, error) {
+	agentConnectionsMu.RLock()
+	agentConn, ok := AgentConnections[nodeId]
+	agentConnectionsMu.RUnlock()
+	if !ok {
+		return nil, ErrNodeNotConnected
+	}
+
+	correlationId, err := newCorrelationId()
+	if err != nil {
+		return nil, err
+	}
+	pending := &PendingCommand{
+		CorrelationId: correlationId,
+		NodeId:        nodeId,
+		Replies:       make(chan Msg),
+		agentConn:     agentConn,
+		done:          make(chan struct{}),
+		received:      make(chan struct{}, 1),
+	}
+	pendingCommandsMu.Lock()
+	pendingCommands[correlationId] = pending
+	pendingCommandsMu.Unlock()
+	go pending.deliver()
+
+	err = agentConn.Send(Msg{
+		Msg:           msgType,
+		NodeId:        nodeId,
+		CorrelationId: correlationId,
+		Data:          data,
+	})
+	if err != nil {
+		pending.Close()
+		return nil, fmt.Errorf("failed to send to agent: %w", err)
+	}
+	return pending, nil
+}
+
+// DispatchCommand sends a command to the node's agent without waiting for the reply
+func DispatchCommand(nodeId int32, command Command) (*PendingCommand, error) {
+	if !KnownCommands[command.Type] {
+		return nil, ErrUnknownCommand
+	}
+	data, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+	return DispatchMsg(nodeId, "command", data)
+}
+
+// SendCommand sends a command to the node's agent and waits for its "command_result" reply.
+func SendCommand(ctx context.Context, nodeId int32, command Command, timeout time.Duration) (string, *CommandResult, error) {
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	pending, err := DispatchCommand(nodeId, command)
+	if err != nil {
+		return "", nil, err
+	}
+	defer pending.Close()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return pending.CorrelationId, nil, ctx.Err()
+		case <-timer.C:
+			return pending.CorrelationId, nil, ErrCommandTimeout
+		case reply := <-pending.Replies:
+			if err := replyError(reply); err != nil {
+				return pending.CorrelationId, nil, err
+			}
+			if reply.Msg != "command_result" {
+				continue
+			}
+			result := CommandResult{}
+			if err := result.FromBytes(reply.Data); err != nil {
+				return pending.CorrelationId, nil, fmt.Errorf("invalid reply from agent: %w", err)
+			}
+			return pending.CorrelationId, &result, nil
+		}
+	}
+}
+
+// routeReply queues a correlated message from an agent for the waiting caller.
+// It returns false when no command sent on agentConn has the correlation id. It
+// never blocks, the agent's reader also handles its heartbeats. Agents keep at most
+// ReplyWindow replies in flight, so the queue stays small however slowly the caller
+// reads; an agent that sends more fails the command with a "reply_overflow" message.
+func routeReply(agentConn *AgentConn, msg Msg) bool {
+	pendingCommandsMu.Lock()
+	pending, ok := pendingCommands[msg.CorrelationId]
+	pendingCommandsMu.Unlock()
+	if !ok || pending.agentConn != agentConn {
+		return false
+	}
+	pending.mu.Lock()
+	if len(pending.queue) >= ReplyWindow {
+		fmt.Println("Agent exceeded the reply window of command", pending.CorrelationId)
+		msg = Msg{Msg: replyOverflow, NodeId: pending.NodeId, CorrelationId: pending.CorrelationId}
+	}
+	pending.mu.Unlock()
+	pending.push(msg)
+	return true
+}
+
+// push queues a reply behind the ones the caller has not read yet. After a terminal
+// message ("disconnected" or replyOverflow) nothing else is queued.
+func (p *PendingCommand) push(msg Msg) {
+	p.mu.Lock()
+	if p.failed {
+		p.mu.Unlock()
+		return
+	}
+	p.failed = replyError(msg) != nil
+	p.queue = append(p.queue, msg)
+	p.mu.Unlock()
+	select {
+	case p.received <- struct{}{}:
+	default:
+	}
+}
+
+// deliver hands queued replies to Replies in order until the command is closed, and
+// acknowledges them to the agent as they are read so it can keep sending
+func (p *PendingCommand) deliver() {
+	read := 0
+	for {
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			select {
+			case <-p.received:
+				continue
+			case <-p.done:
+				return
+			}
+		}
+		msg := p.queue[0]
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+
+		select {
+		case p.Replies <- msg:
+		case <-p.done:
+			return
+		}
+		read++
+		if read%(ReplyWindow/2) == 0 {
+			p.ack(read)
+		}
+	}
+}
+
+func (p *PendingCommand) ack(read int) {
+	data, err := json.Marshal(ReplyAck{Read: read})
+	if err != nil {
+		return
+	}
+	err = p.agentConn.Send(Msg{Msg: "reply_ack", NodeId: p.NodeId, CorrelationId: p.CorrelationId, Data: data})
+	if err != nil {
+		fmt.Println("Error acknowledging replies of command", p.CorrelationId, err)
+	}
+}
+
+// replyError is the error of a message that ends a command early, nil for other messages
+func replyError(reply Msg) error {
+	switch reply.Msg {
+	case "disconnected":
+		return ErrNodeNotConnected
+	case replyOverflow:
+		return ErrReplyOverflow
+	}
+	return nil
+}
+
+// failPendingCommands tells callers waiting on commands sent over agentConn that the
+// connection went away. Commands already sent on a newer connection of the node are
+// left alone. The notice is queued behind unread replies, so it is always delivered.
+func failPendingCommands(agentConn *AgentConn) {
+	pendingCommandsMu.Lock()
+	var waiting []*PendingCommand
+	for _, pending := range pendingCommands {
+		if pending.agentConn == agentConn {
+			waiting = append(waiting, pending)
+		}
+	}
+	pendingCommandsMu.Unlock()
+
+	for _, pending := range waiting {
+		pending.push(Msg{Msg: "disconnected", NodeId: pending.NodeId, CorrelationId: pending.CorrelationId})
+	}
+}
+
+func newCorrelationId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate correlation id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
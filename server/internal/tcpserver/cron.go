@@ -0,0 +1,263 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+const (
+	cronCheckInterval = time.Minute
+	// seconds a run may be late before the schedule counts as missed
+	defaultCronMissedGrace = 300
+	// the end of the output is kept, that is where errors are
+	maxCronOutputBytes = 64 * 1024
+)
+
+// CronJob is a job as synced to the agent. Schedules are five field cron
+// expressions evaluated in UTC.
+type CronJob struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Schedule   string `json:"schedule"`
+	Command    string `json:"command"`
+	WorkingDir string `json:"working_dir,omitempty"`
+	User       string `json:"user,omitempty"`
+}
+
+// CronSync is the Data of a "cron_sync" message. It replaces every job the agent
+// runs for the node, an empty list removes them all.
+type CronSync struct {
+	Jobs []CronJob `json:"jobs"`
+}
+
+// CronRunReport is the Data of a "cron_run" message sent by the agent after each run
+type CronRunReport struct {
+	JobID       int64  `json:"job_id"`
+	ScheduledAt int64  `json:"scheduled_at"` // unix seconds of the schedule time the run belongs to
+	StartedAt   int64  `json:"started_at"`
+	FinishedAt  int64  `json:"finished_at"`
+	ExitCode    int    `json:"exit_code"`
+	Output      string `json:"output"`
+	Error       string `json:"error,omitempty"` // set when the command could not be started
+}
+
+func (r *CronRunReport) FromBytes(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// SyncCronJobs sends the node's enabled cron jobs to its agent. Nodes that are not
+// connected are skipped, they receive their jobs when the agent connects.
+func SyncCronJobs(ctx context.Context, repo *db.Repo, nodeId int32) error {
+	if !IsNodeConnected(nodeId) {
+		return nil
+	}
+	jobs, err := repo.Queries.ListEnabledCronJobsByNode(ctx, int64(nodeId))
+	if err != nil {
+		return fmt.Errorf("failed to list cron jobs: %w", err)
+	}
+
+	sync := CronSync{Jobs: make([]CronJob, len(jobs))}
+	for i, job := range jobs {
+		sync.Jobs[i] = CronJob{
+			ID:         job.ID,
+			Name:       job.Name,
+			Schedule:   job.Schedule,
+			Command:    job.Command,
+			WorkingDir: job.WorkingDir.String,
+			User:       job.RunAsUser.String,
+		}
+	}
+	data, err := json.Marshal(sync)
+	if err != nil {
+		return err
+	}
+	return SendToNode(nodeId, Msg{Msg: "cron_sync", NodeId: nodeId, Data: data})
+}
+
+func handleCronRun(ctx context.Context, repo *db.Repo, nodeId int32, data []byte) {
+	report := CronRunReport{}
+	if err := report.FromBytes(data); err != nil {
+		fmt.Println("Error unmarshalling cron run", err)
+		return
+	}
+	if err := RecordCronRun(ctx, repo, nodeId, report); err != nil {
+		fmt.Println("Error recording cron run", err)
+	}
+}
+
+// RecordCronRun stores a run reported by the node's agent and notifies the job's
+// channels when it failed
+func RecordCronRun(ctx context.Context, repo *db.Repo, nodeId int32, report CronRunReport) error {
+	job, err := repo.Queries.GetCronJob(ctx, report.JobID)
+	if err != nil {
+		return fmt.Errorf("cron job %d not found: %w", report.JobID, err)
+	}
+	if job.NodeID != int64(nodeId) {
+		return fmt.Errorf("cron job %d does not belong to node %d", job.ID, nodeId)
+	}
+
+	status := "succeeded"
+	if report.ExitCode != 0 || report.Error != "" {
+		status = "failed"
+	}
+	output := report.Output
+	if len(output) > maxCronOutputBytes {
+		output = output[len(output)-maxCronOutputBytes:]
+	}
+	var durationMs int64
+	if report.FinishedAt >= report.StartedAt {
+		durationMs = (report.FinishedAt - report.StartedAt) * 1000
+	}
+
+	run, err := repo.Queries.CreateCronRun(ctx, db.CreateCronRunParams{
+		CronJobID:   job.ID,
+		NodeID:      job.NodeID,
+		Status:      status,
+		ExitCode:    sql.NullInt64{Int64: int64(report.ExitCode), Valid: report.Error == ""},
+		DurationMs:  durationMs,
+		Output:      sql.NullString{String: output, Valid: output != ""},
+		Error:       sql.NullString{String: report.Error, Valid: report.Error != ""},
+		ScheduledAt: report.ScheduledAt,
+		StartedAt:   sql.NullInt64{Int64: report.StartedAt, Valid: report.StartedAt > 0},
+		FinishedAt:  sql.NullInt64{Int64: report.FinishedAt, Valid: report.FinishedAt > 0},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store cron run: %w", err)
+	}
+
+	// runs reported late must not move the schedule back
+	scheduledAt := job.LastScheduledAt
+	if report.ScheduledAt > scheduledAt.Int64 {
+		scheduledAt = sql.NullInt64{Int64: report.ScheduledAt, Valid: true}
+	}
+	err = repo.Queries.SetCronJobLastRun(ctx, db.SetCronJobLastRunParams{
+		LastStatus:      sql.NullString{String: status, Valid: true},
+		LastRunAt:       run.FinishedAt,
+		LastScheduledAt: scheduledAt,
+		ID:              job.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cron job: %w", err)
+	}
+
+	if status == "failed" {
+		result := fmt.Sprintf("failed with exit code %d", report.ExitCode)
+		if report.Error != "" {
+			result = "failed to start: " + report.Error
+		}
+		notifyCronJob(ctx, repo, job, result)
+	}
+	return nil
+}
+
+// MonitorCronJobs periodically records the schedules of enabled jobs that no run was reported for
+func MonitorCronJobs(ctx context.Context, repo *db.Repo) {
+	grace := envSeconds("CRON_MISSED_GRACE", defaultCronMissedGrace)
+
+	ticker := time.NewTicker(cronCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			CheckMissedCronRuns(ctx, repo, time.Now(), grace)
+		}
+	}
+}
+
+// CheckMissedCronRuns records one missed run per job whose schedule passed more
+// than grace ago without a reported run. Jobs of disconnected nodes are left
+// alone until the node is back, the node status covers the outage.
+func CheckMissedCronRuns(ctx context.Context, repo *db.Repo, now time.Time, grace time.Duration) {
+	jobs, err := repo.Queries.ListEnabledCronJobs(ctx)
+	if err != nil {
+		fmt.Println("Error listing cron jobs", err)
+		return
+	}
+
+	deadline := now.Add(-grace).UTC()
+	for _, job := range jobs {
+		if !IsNodeConnected(int32(job.NodeID)) {
+			continue
+		}
+		schedule, err := utils.ParseCronSchedule(job.Schedule)
+		if err != nil {
+			fmt.Println("Skipping cron job", job.ID, err)
+			continue
+		}
+
+		since := job.CreatedAt
+		if job.LastScheduledAt.Valid {
+			since = job.LastScheduledAt.Int64
+		}
+		due := schedule.Next(time.Unix(since, 0).UTC())
+		if due.IsZero() || due.After(deadline) {
+			continue
+		}
+
+		// a long outage is recorded once, for the last schedule time that was missed
+		missed, count := due, 1
+		for next := schedule.Next(missed); !next.IsZero() && !next.After(deadline); next = schedule.Next(next) {
+			missed = next
+			count++
+		}
+		reason := "no run was reported for the scheduled time"
+		if count > 1 {
+			reason = fmt.Sprintf("no run was reported for %d scheduled times", count)
+		}
+
+		_, err = repo.Queries.CreateCronRun(ctx, db.CreateCronRunParams{
+			CronJobID:   job.ID,
+			NodeID:      job.NodeID,
+			Status:      "missed",
+			Error:       sql.NullString{String: reason, Valid: true},
+			ScheduledAt: missed.Unix(),
+		})
+		if err != nil {
+			fmt.Println("Error recording missed cron run", err)
+			continue
+		}
+		err = repo.Queries.SetCronJobLastRun(ctx, db.SetCronJobLastRunParams{
+			LastStatus:      sql.NullString{String: "missed", Valid: true},
+			LastRunAt:       job.LastRunAt,
+			LastScheduledAt: sql.NullInt64{Int64: missed.Unix(), Valid: true},
+			ID:              job.ID,
+		})
+		if err != nil {
+			fmt.Println("Error updating cron job", err)
+			continue
+		}
+		notifyCronJob(ctx, repo, job, "missed: "+reason)
+	}
+}
+
+// notifyCronJob sends a failed or missed run to the job's notification channels
+func notifyCronJob(ctx context.Context, repo *db.Repo, job db.CronJob, result string) {
+	channels, err := repo.Queries.ListCronJobChannels(ctx, job.ID)
+	if err != nil {
+		fmt.Println("Error getting cron job channels", err)
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	msg := AlertMsg{
+		Metric:       "Cron job " + job.Name,
+		Threshold:    job.Schedule,
+		CurrentValue: result,
+		Timestamp:    time.Now(),
+	}
+	if node, err := repo.Queries.GetNode(ctx, job.NodeID); err == nil {
+		msg.NodeName = node.Name.String
+		msg.NodeIp = node.Ip
+	}
+	go sendAlertNotifications(ctx, repo, channels, msg)
+}
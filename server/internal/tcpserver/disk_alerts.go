@@ -0,0 +1,189 @@
+package tcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+const (
+	diskFillWindow        = 6 * time.Hour    // history used to estimate the fill rate
+	diskFillMinSpan       = 30 * time.Minute // minimum history before predicting
+	diskFillMinSamples    = 10
+	diskFillCheckInterval = 5 * time.Minute // predictions are not re-run on every sys_stat
+)
+
+var (
+	lastDiskFillCheck   = make(map[int64]time.Time)
+	lastDiskFillCheckMu sync.Mutex
+)
+
+// diskUsageTargets returns the used percent of every mount an alert applies to.
+// An empty mountpoint means any mount, "*" is the aggregate usage reported by the agent.
+func diskUsageTargets(sysStat SystemStat, mountpoint string) map[string]float64 {
+	targets := make(map[string]float64)
+	if mountpoint == AllMountsDiskMountpoint || (mountpoint == "" && len(sysStat.Disks) == 0) {
+		targets[AllMountsDiskMountpoint] = sysStat.DiskUsage
+		return targets
+	}
+	for _, disk := range sysStat.Disks {
+		if mountpoint == "" || disk.Mountpoint == mountpoint {
+			targets[disk.Mountpoint] = disk.UsedPercent()
+		}
+	}
+	return targets
+}
+
+func checkDiskUsage(ctx context.Context, repo *db.Repo, nodeId int32, sysStat SystemStat, now time.Time) {
+	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
+		NodeID: int64(nodeId),
+		Metric: "disk",
+	})
+	if err != nil {
+		fmt.Println("Error getting active alerts", err)
+		return
+	}
+	if len(alerts) == 0 {
+		fmt.Println("No active alerts found")
+		return
+	}
+	fmt.Println("Active alerts found", len(alerts))
+	for _, alert := range alerts {
+		var usages, exceeded []string
+		var pendingFor time.Duration
+		for mountpoint, usage := range diskUsageTargets(sysStat, alert.Mountpoint.String) {
+			samples := diskStatWindow(ctx, repo, alert, mountpoint, usage, now)
+			value, mountBreached, mountPendingFor := windowBreach(alert, samples, alert.Threshold.Float64, now)
+			usages = append(usages, fmt.Sprintf("%s: %.2f%%", mountpoint, value))
+			if mountBreached {
+				exceeded = append(exceeded, fmt.Sprintf("%s: %.2f%%", mountpoint, value))
+				pendingFor = max(pendingFor, mountPendingFor)
+			}
+		}
+		sort.Strings(usages)
+		sort.Strings(exceeded)
+
+		breached := len(exceeded) > 0
+		currentValue := strings.Join(usages, ", ")
+		if breached {
+			fmt.Println("Disk usage exceeded threshold for alert", int32(alert.ID))
+			currentValue = strings.Join(exceeded, ", ")
+		}
+		evaluateAlert(ctx, repo, alert, breached, pendingFor, AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "Disk",
+			Threshold:    fmt.Sprintf("%.2f%%%s", alert.Threshold.Float64, alertWindowLabel(alert)),
+			CurrentValue: currentValue,
+			Timestamp:    now,
+		})
+	}
+}
+
+// checkDiskFill alerts when a mount is predicted to be full within threshold hours
+func checkDiskFill(ctx context.Context, repo *db.Repo, nodeId int32, sysStat SystemStat, now time.Time) {
+	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
+		NodeID: int64(nodeId),
+		Metric: "disk_fill",
+	})
+	if err != nil {
+		fmt.Println("Error getting active alerts", err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	for _, alert := range alerts {
+		if !dueForDiskFillCheck(alert.ID) {
+			continue
+		}
+
+		var predicted []string
+		since := now.Add(-diskFillWindow).Unix()
+		for mountpoint := range diskUsageTargets(sysStat, alert.Mountpoint.String) {
+			samples, err := repo.TimeseriesQueries.GetMountDiskStats(ctx, db.GetMountDiskStatsParams{
+				NodeID:     int64(nodeId),
+				Mountpoint: mountpoint,
+				Timestamp:  since,
+			})
+			if err != nil {
+				fmt.Println("Error getting disk stats", err)
+				continue
+			}
+			hours, ok := EstimateHoursUntilFull(samples)
+			if ok && hours <= alert.Threshold.Float64 {
+				predicted = append(predicted, fmt.Sprintf("%s: full in %.1fh", mountpoint, hours))
+			}
+		}
+		sort.Strings(predicted)
+
+		breached := len(predicted) > 0
+		currentValue := "No mount predicted to fill"
+		if breached {
+			fmt.Println("Disk predicted to fill for alert", int32(alert.ID))
+			currentValue = strings.Join(predicted, ", ")
+		}
+		evaluateAlert(ctx, repo, alert, breached, alertWindow(alert), AlertMsg{
+			NodeName:     alert.NodeName.String,
+			NodeIp:       alert.NodeIp,
+			Metric:       "Disk Fill",
+			Threshold:    fmt.Sprintf("Full within %.0f hours", alert.Threshold.Float64),
+			CurrentValue: currentValue,
+			Timestamp:    now,
+		})
+	}
+}
+
+func dueForDiskFillCheck(alertId int64) bool {
+	lastDiskFillCheckMu.Lock()
+	defer lastDiskFillCheckMu.Unlock()
+	if time.Since(lastDiskFillCheck[alertId]) < diskFillCheckInterval {
+		return false
+	}
+	lastDiskFillCheck[alertId] = time.Now()
+	return true
+}
+
+// EstimateHoursUntilFull fits a least-squares line through the used percent samples and
+// returns the hours until the mount reaches 100%. ok is false when there is not enough
+// history or the usage is not growing.
+func EstimateHoursUntilFull(samples []db.GetMountDiskStatsRow) (hours float64, ok bool) {
+	if len(samples) < diskFillMinSamples {
+		return 0, false
+	}
+	first := samples[0].Timestamp
+	last := samples[len(samples)-1]
+	if time.Duration(last.Timestamp-first)*time.Second < diskFillMinSpan {
+		return 0, false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := float64(sample.Timestamp - first)
+		y := sample.UsedPercent
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator // percent per second
+	if slope <= 0 {
+		return 0, false
+	}
+
+	remaining := 100 - last.UsedPercent
+	if remaining <= 0 {
+		return 0, true
+	}
+	return remaining / slope / 3600, true
+}
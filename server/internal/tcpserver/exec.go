@@ -0,0 +1,331 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+const (
+	ExecStatusRunning   = "running"
+	ExecStatusSucceeded = "succeeded"
+	ExecStatusFailed    = "failed"
+	ExecStatusTimeout   = "timeout"
+)
+
+const (
+	DefaultExecTimeout = 60 * time.Second
+	execTimeoutGrace   = 10 * time.Second // the agent enforces the timeout, the server waits a little longer
+	maxExecOutput      = 1 << 20          // bytes of output kept in command_executions
+	execEventBuffer    = 256
+)
+
+var ErrCommandNotAllowed = errors.New("command is not in EXEC_ALLOWED_COMMANDS and ad-hoc commands are disabled")
+
+// ExecRequest is the Data of an "exec" message sent to the agent
+type ExecRequest struct {
+	Command string `json:"command"`
+	Dir     string `json:"dir,omitempty"`
+	Timeout int    `json:"timeout"` // seconds
+}
+
+// ExecOutput is the Data of an "exec_output" chunk streamed by the agent
+type ExecOutput struct {
+	Stream string `json:"stream"` // stdout or stderr
+	Data   string `json:"data"`
+}
+
+// ExecResult is the Data of the final "exec_result" message from the agent
+type ExecResult struct {
+	ExitCode int    `json:"exit_code"`
+	TimedOut bool   `json:"timed_out"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExecEvent is what subscribers of an execution receive, as output arrives and once when it exits
+type ExecEvent struct {
+	Type       string `json:"type"` // output or exit
+	Stream     string `json:"stream,omitempty"`
+	Data       string `json:"data,omitempty"`
+	Status     string `json:"status,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExecOptions describes a command to run on a node
+type ExecOptions struct {
+	Command string
+	Dir     string
+	Timeout time.Duration
+	UserId  int32 // 0 when started by the server itself
+
+	// set when running a command declared in a project's config
+	ProjectID   string
+	CommandName string
+}
+
+// Execution is a command running on a node. Output is buffered so that late
+// subscribers get everything printed so far.
+type Execution struct {
+	ID      int64
+	NodeId  int32
+	Command string
+
+	mu          sync.Mutex
+	output      strings.Builder
+	truncated   bool
+	subscribers map[chan ExecEvent]struct{}
+	final       *ExecEvent
+	done        chan struct{}
+}
+
+var (
+	runningExecutions   = make(map[int64]*Execution)
+	runningExecutionsMu sync.Mutex
+)
+
+// ExecAllowed checks an ad-hoc command against EXEC_ALLOWED_COMMANDS (separated by ";").
+// Any command is allowed when EXEC_ALLOW_ADHOC is true.
+func ExecAllowed(command string) bool {
+	if os.Getenv("EXEC_ALLOW_ADHOC") == "true" {
+		return true
+	}
+	command = strings.TrimSpace(command)
+	for _, allowed := range strings.Split(os.Getenv("EXEC_ALLOWED_COMMANDS"), ";") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && allowed == command {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRunningExecution returns the execution if it is still running on this server
+func GetRunningExecution(id int64) (*Execution, bool) {
+	runningExecutionsMu.Lock()
+	defer runningExecutionsMu.Unlock()
+	execution, ok := runningExecutions[id]
+	return execution, ok
+}
+
+// StartExecution records a command execution and sends it to the node's agent.
+// Output is streamed to subscribers and stored when the command exits.
+func StartExecution(ctx context.Context, repo *db.Repo, nodeId int32, opts ExecOptions) (*Execution, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultExecTimeout
+	}
+	if !IsNodeConnected(nodeId) {
+		return nil, ErrNodeNotConnected
+	}
+
+	row, err := repo.Queries.CreateCommandExecution(ctx, db.CreateCommandExecutionParams{
+		NodeID:      int64(nodeId),
+		UserID:      sql.NullInt64{Int64: int64(opts.UserId), Valid: opts.UserId != 0},
+		Command:     opts.Command,
+		ProjectID:   sql.NullString{String: opts.ProjectID, Valid: opts.ProjectID != ""},
+		CommandName: sql.NullString{String: opts.CommandName, Valid: opts.CommandName != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record execution: %w", err)
+	}
+	execution := &Execution{
+		ID:          row.ID,
+		NodeId:      nodeId,
+		Command:     opts.Command,
+		subscribers: make(map[chan ExecEvent]struct{}),
+		done:        make(chan struct{}),
+	}
+
+	data, err := json.Marshal(ExecRequest{
+		Command: opts.Command,
+		Dir:     opts.Dir,
+		Timeout: int(opts.Timeout.Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pending, err := DispatchMsg(nodeId, "exec", data)
+	if err != nil {
+		execution.finish(ctx, repo, ExecStatusFailed, nil, err.Error(), time.Now())
+		return nil, err
+	}
+	err = repo.Queries.SetCommandExecutionCorrelationID(ctx, db.SetCommandExecutionCorrelationIDParams{
+		CorrelationID: sql.NullString{String: pending.CorrelationId, Valid: true},
+		ID:            execution.ID,
+	})
+	if err != nil {
+		fmt.Println("Error saving execution correlation id", err)
+	}
+
+	runningExecutionsMu.Lock()
+	runningExecutions[execution.ID] = execution
+	runningExecutionsMu.Unlock()
+
+	go execution.run(ctx, repo, pending, opts.Timeout)
+	return execution, nil
+}
+
+func (e *Execution) run(ctx context.Context, repo *db.Repo, pending *PendingCommand, timeout time.Duration) {
+	defer pending.Close()
+	startedAt := time.Now()
+	timer := time.NewTimer(timeout + execTimeoutGrace)
+	defer timer.Stop()
+
+	for {
+		select {
+		case reply := <-pending.Replies:
+			switch reply.Msg {
+			case "exec_output":
+				chunk := ExecOutput{}
+				if err := json.Unmarshal(reply.Data, &chunk); err != nil {
+					fmt.Println("Error decoding exec output", err)
+					continue
+				}
+				e.publish(ExecEvent{Type: "output", Stream: chunk.Stream, Data: chunk.Data})
+			case "exec_result":
+				result := ExecResult{}
+				if err := json.Unmarshal(reply.Data, &result); err != nil {
+					e.finish(ctx, repo, ExecStatusFailed, nil, "invalid result from agent", startedAt)
+					return
+				}
+				status := ExecStatusSucceeded
+				if result.TimedOut {
+					status = ExecStatusTimeout
+				} else if result.ExitCode != 0 || result.Error != "" {
+					status = ExecStatusFailed
+				}
+				e.finish(ctx, repo, status, &result.ExitCode, result.Error, startedAt)
+				return
+			case "disconnected":
+				e.finish(ctx, repo, ExecStatusFailed, nil, "agent disconnected", startedAt)
+				return
+			case replyOverflow:
+				if err := SendToNode(e.NodeId, Msg{Msg: "exec_cancel", CorrelationId: pending.CorrelationId}); err != nil {
+					fmt.Println("Error cancelling execution", e.ID, err)
+				}
+				e.finish(ctx, repo, ExecStatusFailed, nil, ErrReplyOverflow.Error(), startedAt)
+				return
+			}
+		case <-timer.C:
+			// ask the agent to kill the process, it may already be gone
+			if err := SendToNode(e.NodeId, Msg{Msg: "exec_cancel", CorrelationId: pending.CorrelationId}); err != nil {
+				fmt.Println("Error cancelling execution", e.ID, err)
+			}
+			e.finish(ctx, repo, ExecStatusTimeout, nil, "no result from agent within the timeout", startedAt)
+			return
+		}
+	}
+}
+
+// publish appends output to the buffer and fans it out to subscribers.
+// Subscribers that cannot keep up are dropped rather than blocking the agent.
+func (e *Execution) publish(event ExecEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if event.Type == "output" && !e.truncated {
+		if e.output.Len()+len(event.Data) > maxExecOutput {
+			e.output.WriteString(event.Data[:maxExecOutput-e.output.Len()])
+			e.output.WriteString("\n[output truncated]\n")
+			e.truncated = true
+		} else {
+			e.output.WriteString(event.Data)
+		}
+	}
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (e *Execution) finish(ctx context.Context, repo *db.Repo, status string, exitCode *int, errMsg string, startedAt time.Time) {
+	duration := time.Since(startedAt).Milliseconds()
+	final := ExecEvent{
+		Type:       "exit",
+		Status:     status,
+		ExitCode:   exitCode,
+		DurationMs: duration,
+		Error:      errMsg,
+	}
+
+	e.mu.Lock()
+	output := e.output.String()
+	e.mu.Unlock()
+
+	params := db.FinishCommandExecutionParams{
+		Status:     status,
+		Output:     output,
+		Error:      sql.NullString{String: errMsg, Valid: errMsg != ""},
+		DurationMs: sql.NullInt64{Int64: duration, Valid: true},
+		ID:         e.ID,
+	}
+	if exitCode != nil {
+		params.ExitCode = sql.NullInt64{Int64: int64(*exitCode), Valid: true}
+	}
+	if err := repo.Queries.FinishCommandExecution(ctx, params); err != nil {
+		fmt.Println("Error saving execution result", err)
+	}
+
+	e.publish(final)
+	e.mu.Lock()
+	e.final = &final
+	for ch := range e.subscribers {
+		close(ch)
+	}
+	e.subscribers = nil
+	e.mu.Unlock()
+	close(e.done)
+
+	runningExecutionsMu.Lock()
+	delete(runningExecutions, e.ID)
+	runningExecutionsMu.Unlock()
+}
+
+// Subscribe returns the output printed so far and a channel of further events.
+// The channel is closed after the exit event; cancel stops the subscription early.
+func (e *Execution) Subscribe() (string, <-chan ExecEvent, func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch := make(chan ExecEvent, execEventBuffer)
+	if e.final != nil {
+		ch <- *e.final
+		close(ch)
+		return e.output.String(), ch, func() {}
+	}
+	e.subscribers[ch] = struct{}{}
+	cancel := func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.subscribers[ch]; ok {
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+	return e.output.String(), ch, cancel
+}
+
+// Wait blocks until the command exits and returns the exit event
+func (e *Execution) Wait() ExecEvent {
+	<-e.done
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return *e.final
+}
+
+// Output returns the output buffered so far
+func (e *Execution) Output() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.output.String()
+}
@@ -0,0 +1,63 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+var ErrMachineIDMismatch = errors.New("machine id does not match the node bound to this agent")
+
+// BindNodeIdentity records the machine id and the observed address of a connected node.
+// A node without a machine id, e.g. one created before machine ids existed, adopts the
+// one its agent reports unless another node has it; a different machine id presented
+// for an already identified node is rejected. Callers only pass nodes the agent has
+// authenticated as, through a bound token or a client certificate.
+func BindNodeIdentity(ctx context.Context, repo *db.Repo, node *db.Node, machineId string, ip string) error {
+	if machineId != "" {
+		if node.MachineID.Valid && node.MachineID.String != machineId {
+			return ErrMachineIDMismatch
+		}
+		if !node.MachineID.Valid {
+			owner, err := repo.Queries.GetNodeByMachineID(ctx, sql.NullString{String: machineId, Valid: true})
+			if err == nil && owner.ID != node.ID {
+				return ErrNodeAlreadyEnrolled
+			}
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			err = repo.Queries.SetNodeMachineID(ctx, db.SetNodeMachineIDParams{
+				MachineID: sql.NullString{String: machineId, Valid: true},
+				ID:        node.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set machine id: %w", err)
+			}
+			node.MachineID = sql.NullString{String: machineId, Valid: true}
+		}
+	}
+
+	if node.Ip != ip {
+		err := repo.Queries.UpdateNodeIP(ctx, db.UpdateNodeIPParams{
+			Ip: ip,
+			ID: node.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update node ip: %w", err)
+		}
+		fmt.Println("Node", node.ID, "address changed from", node.Ip, "to", ip)
+		node.Ip = ip
+	}
+
+	err := repo.Queries.RecordNodeIP(ctx, db.RecordNodeIPParams{
+		NodeID: node.ID,
+		Ip:     ip,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record node ip: %w", err)
+	}
+	return nil
+}
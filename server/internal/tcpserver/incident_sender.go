@@ -0,0 +1,158 @@
+package tcpserver
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPagerDutyBaseURL = "https://events.pagerduty.com"
+	defaultOpsgenieBaseURL  = "https://api.opsgenie.com"
+)
+
+// PagerDutyConfig is the config of a pagerduty notification channel, sent to the
+// Events API v2
+type PagerDutyConfig struct {
+	BaseURL    string `json:"base_url"`    // https://events.pagerduty.com by default
+	RoutingKey string `json:"routing_key"` // integration key of the service
+	Severity   string `json:"severity"`    // critical by default, error, warning or info
+}
+
+// OpsgenieConfig is the config of an opsgenie notification channel, sent to the
+// Alert API
+type OpsgenieConfig struct {
+	BaseURL  string   `json:"base_url"` // https://api.opsgenie.com by default, https://api.eu.opsgenie.com for EU accounts
+	APIKey   string   `json:"api_key"`
+	Priority string   `json:"priority"` // P1 to P5, P1 by default
+	Tags     []string `json:"tags"`
+}
+
+// alertDetails are the fields of a message as a map, for the custom details of incidents
+func alertDetails(alert AlertMsg) map[string]string {
+	details := map[string]string{}
+	for _, fact := range alertFacts(alert) {
+		details[fact.Name] = fact.Value
+	}
+	return details
+}
+
+func (c *PagerDutyConfig) validate() error {
+	if c.BaseURL == "" {
+		c.BaseURL = defaultPagerDutyBaseURL
+	}
+	if err := checkHTTPURL("base_url", c.BaseURL); err != nil {
+		return err
+	}
+	if c.RoutingKey == "" {
+		return fmt.Errorf("routing_key is required")
+	}
+	switch c.Severity {
+	case "":
+		c.Severity = "critical"
+	case "critical", "error", "warning", "info":
+	default:
+		return fmt.Errorf("severity must be critical, error, warning or info")
+	}
+	return nil
+}
+
+// SendPagerDutyAlert sends a trigger event for a firing alert and a resolve event
+// for a resolved one. Both use the alert's dedup key, so reminders land on the open
+// incident and the resolve closes it.
+func SendPagerDutyAlert(config PagerDutyConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	event := map[string]interface{}{
+		"routing_key":  config.RoutingKey,
+		"event_action": "resolve",
+		"dedup_key":    alert.DedupKey(),
+	}
+	if !alert.Resolved {
+		event["event_action"] = "trigger"
+		event["client"] = "VPS Pilot"
+		event["payload"] = map[string]interface{}{
+			"summary":        alertHeadline(alert),
+			"source":         alert.NodeName,
+			"severity":       config.Severity,
+			"timestamp":      alert.Timestamp.Format(time.RFC3339),
+			"component":      alert.Metric,
+			"custom_details": alertDetails(alert),
+		}
+	}
+
+	attempts, err := postJSON(strings.TrimRight(config.BaseURL, "/")+"/v2/enqueue", nil, event)
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to PagerDuty!")
+	return attempts, nil
+}
+
+func (c *OpsgenieConfig) validate() error {
+	if c.BaseURL == "" {
+		c.BaseURL = defaultOpsgenieBaseURL
+	}
+	if err := checkHTTPURL("base_url", c.BaseURL); err != nil {
+		return err
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("api_key is required")
+	}
+	switch c.Priority {
+	case "":
+		c.Priority = "P1"
+	case "P1", "P2", "P3", "P4", "P5":
+	default:
+		return fmt.Errorf("priority must be P1, P2, P3, P4 or P5")
+	}
+	return nil
+}
+
+// SendOpsgenieAlert creates an alert for a firing alert and closes it when the
+// alert resolves. The dedup key is the Opsgenie alias, so reminders only raise the
+// count of the open alert.
+func SendOpsgenieAlert(config OpsgenieConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + config.APIKey}
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+
+	var attempts []WebhookAttempt
+	var err error
+	if alert.Resolved {
+		closeURL := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", baseURL, url.PathEscape(alert.DedupKey()))
+		attempts, err = postJSON(closeURL, headers, map[string]interface{}{
+			"source": "VPS Pilot",
+			"note":   fmt.Sprintf("Resolved after %s, current value %s", alert.LastedString(), alert.CurrentValue),
+		})
+	} else {
+		lines := []string{}
+		for _, fact := range alertFacts(alert) {
+			lines = append(lines, fmt.Sprintf("%s: %s", fact.Name, fact.Value))
+		}
+		payload := map[string]interface{}{
+			"message":     alertHeadline(alert),
+			"alias":       alert.DedupKey(),
+			"description": strings.Join(lines, "\n"),
+			"priority":    config.Priority,
+			"source":      "VPS Pilot",
+			"entity":      alert.NodeName,
+			"details":     alertDetails(alert),
+		}
+		if len(config.Tags) > 0 {
+			payload["tags"] = config.Tags
+		}
+		attempts, err = postJSON(baseURL+"/v2/alerts", headers, payload)
+	}
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to Opsgenie!")
+	return attempts, nil
+}
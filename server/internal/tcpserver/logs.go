@@ -0,0 +1,83 @@
+package tcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// LogTailRequest is the Data of a "log_tail" message. The agent replies with
+// "log_lines" chunks, starting with the last Lines lines of the file. Without
+// Follow it sends "log_end" after them, otherwise it keeps sending new lines
+// until it receives "log_cancel" with the same correlation id.
+type LogTailRequest struct {
+	Path   string `json:"path"`
+	Lines  int    `json:"lines"`
+	Follow bool   `json:"follow"`
+}
+
+// LogLines is the Data of a "log_lines" message
+type LogLines struct {
+	Lines []string `json:"lines"`
+}
+
+// LogEnd is the Data of the "log_end" message, sent when the agent stops reading the file
+type LogEnd struct {
+	Error string `json:"error,omitempty"`
+}
+
+// TailLog asks the node's agent to read a log file and hands every chunk of lines
+// to onLines. It returns when the agent ends the tail, when onLines fails or when
+// ctx is done, which is how a followed log is stopped.
+func TailLog(ctx context.Context, nodeId int32, req LogTailRequest, onLines func([]string) error) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	pending, err := DispatchMsg(nodeId, "log_tail", data)
+	if err != nil {
+		return err
+	}
+	defer pending.Close()
+
+	cancel := func() {
+		if err := SendToNode(nodeId, Msg{Msg: "log_cancel", CorrelationId: pending.CorrelationId}); err != nil {
+			fmt.Println("Error cancelling log tail", err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			return ctx.Err()
+		case reply := <-pending.Replies:
+			switch reply.Msg {
+			case "disconnected":
+				return ErrNodeNotConnected
+			case replyOverflow:
+				cancel()
+				return ErrReplyOverflow
+			case "log_lines":
+				chunk := LogLines{}
+				if err := json.Unmarshal(reply.Data, &chunk); err != nil {
+					fmt.Println("Error decoding log lines", err)
+					continue
+				}
+				if err := onLines(chunk.Lines); err != nil {
+					cancel()
+					return err
+				}
+			case "log_end":
+				end := LogEnd{}
+				if err := json.Unmarshal(reply.Data, &end); err != nil {
+					return fmt.Errorf("invalid reply from agent: %w", err)
+				}
+				if end.Error != "" {
+					return errors.New(end.Error)
+				}
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+const (
+	NodeStatusOnline  = "online"
+	NodeStatusStale   = "stale"
+	NodeStatusOffline = "offline"
+)
+
+const (
+	defaultNodeStaleTimeout   = 30  // seconds without a sys_stat before a node is stale
+	defaultNodeOfflineTimeout = 120 // seconds without a sys_stat before a node is offline
+	nodeStatusCheckInterval   = 10 * time.Second
+)
+
+// nodeState is the status of a node as the server sees it. The DB copy is written
+// when the status changes, and last_seen_at at most every nodeStatusCheckInterval.
+type nodeState struct {
+	status   string
+	lastSeen int64
+	written  int64 // last_seen_at as stored in the DB
+}
+
+var (
+	// nodeStatusMu guards nodeStates and nodeStatusChanges. It is never held across
+	// a DB write or a channel send, heartbeats of every node go through it.
+	nodeStatusMu sync.Mutex
+	nodeStates   = make(map[int32]*nodeState)
+	// nodeStatusChanges are persisted and emitted in order by publishNodeStatus
+	nodeStatusChanges []NodeStatusEvent
+	nodeStatusChanged = make(chan struct{}, 1)
+)
+
+// NodeStatusEvent is emitted on the monitor channel whenever a node changes state
+type NodeStatusEvent struct {
+	NodeID   int32  `json:"node_id"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+	LastSeen int64  `json:"last_seen"`
+}
+
+func (e *NodeStatusEvent) ToBytes() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *NodeStatusEvent) FromBytes(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+// NodeStatusTimeouts reads NODE_STALE_TIMEOUT and NODE_OFFLINE_TIMEOUT (seconds) from the environment
+func NodeStatusTimeouts() (time.Duration, time.Duration) {
+	stale := envSeconds("NODE_STALE_TIMEOUT", defaultNodeStaleTimeout)
+	offline := envSeconds("NODE_OFFLINE_TIMEOUT", defaultNodeOfflineTimeout)
+	if offline < stale {
+		fmt.Println("Warning: NODE_OFFLINE_TIMEOUT is lower than NODE_STALE_TIMEOUT, using", stale)
+		offline = stale
+	}
+	return stale, offline
+}
+
+func envSeconds(name string, def int) time.Duration {
+	seconds := def
+	if value := os.Getenv(name); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			seconds = parsed
+		} else {
+			fmt.Printf("Warning: Invalid %s value '%s', using default %d seconds\n", name, value, def)
+		}
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// DeriveNodeStatus maps the time since the last heartbeat onto a node status
+func DeriveNodeStatus(lastSeen sql.NullInt64, now time.Time, staleTimeout time.Duration, offlineTimeout time.Duration) string {
+	if !lastSeen.Valid {
+		return NodeStatusOffline
+	}
+	silence := now.Sub(time.Unix(lastSeen.Int64, 0))
+	if silence >= offlineTimeout {
+		return NodeStatusOffline
+	}
+	if silence >= staleTimeout {
+		return NodeStatusStale
+	}
+	return NodeStatusOnline
+}
+
+// MarkNodeSeen records a heartbeat for the node and brings it back online right away
+func MarkNodeSeen(ctx context.Context, repo *db.Repo, nodeId int32) {
+	if err := loadNodeState(ctx, repo, nodeId); err != nil {
+		fmt.Println("Error getting node", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	nodeStatusMu.Lock()
+	state := nodeStates[nodeId]
+	state.lastSeen = now
+	write := now-state.written >= int64(nodeStatusCheckInterval/time.Second)
+	if write {
+		state.written = now
+	}
+	if state.status != NodeStatusOnline {
+		queueNodeStatus(nodeId, state, NodeStatusOnline)
+	}
+	nodeStatusMu.Unlock()
+
+	if write {
+		touchNode(ctx, repo, nodeId, now)
+	}
+}
+
+// loadNodeState reads the status of a node the server has not tracked yet from the DB
+func loadNodeState(ctx context.Context, repo *db.Repo, nodeId int32) error {
+	nodeStatusMu.Lock()
+	_, ok := nodeStates[nodeId]
+	nodeStatusMu.Unlock()
+	if ok {
+		return nil
+	}
+
+	node, err := repo.Queries.GetNode(ctx, int64(nodeId))
+	if err != nil {
+		return err
+	}
+	nodeStatusMu.Lock()
+	defer nodeStatusMu.Unlock()
+	if _, ok := nodeStates[nodeId]; !ok {
+		nodeStates[nodeId] = &nodeState{status: node.Status, lastSeen: node.LastSeenAt.Int64, written: node.LastSeenAt.Int64}
+	}
+	return nil
+}
+
+func touchNode(ctx context.Context, repo *db.Repo, nodeId int32, lastSeen int64) {
+	err := repo.Queries.TouchNode(ctx, db.TouchNodeParams{
+		LastSeenAt: sql.NullInt64{Int64: lastSeen, Valid: lastSeen != 0},
+		ID:         int64(nodeId),
+	})
+	if err != nil {
+		fmt.Println("Error updating node last seen", err)
+	}
+}
+
+// MonitorNodeStatus periodically moves silent nodes to stale and offline
+func MonitorNodeStatus(ctx context.Context, repo *db.Repo, monitorChan chan Msg) {
+	staleTimeout, offlineTimeout := NodeStatusTimeouts()
+	fmt.Println("Monitoring node status, stale after", staleTimeout, "offline after", offlineTimeout)
+
+	// nodes that were online before a restart still have to go offline if they stay silent
+	nodes, err := repo.Queries.ListNodes(ctx)
+	if err != nil {
+		fmt.Println("Error listing nodes", err)
+	}
+	for _, node := range nodes {
+		if err := loadNodeState(ctx, repo, int32(node.ID)); err != nil {
+			fmt.Println("Error getting node", err)
+		}
+	}
+	go publishNodeStatus(ctx, repo, monitorChan)
+
+	ticker := time.NewTicker(nodeStatusCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkNodeStatuses(staleTimeout, offlineTimeout)
+		}
+	}
+}
+
+func checkNodeStatuses(staleTimeout time.Duration, offlineTimeout time.Duration) {
+	nodeStatusMu.Lock()
+	defer nodeStatusMu.Unlock()
+
+	now := time.Now()
+	for nodeId, state := range nodeStates {
+		lastSeen := sql.NullInt64{Int64: state.lastSeen, Valid: state.lastSeen != 0}
+		status := DeriveNodeStatus(lastSeen, now, staleTimeout, offlineTimeout)
+		if status != state.status {
+			queueNodeStatus(nodeId, state, status)
+		}
+	}
+}
+
+// queueNodeStatus moves a node to a new status and queues the change for
+// publishNodeStatus; callers hold nodeStatusMu
+func queueNodeStatus(nodeId int32, state *nodeState, status string) {
+	nodeStatusChanges = append(nodeStatusChanges, NodeStatusEvent{
+		NodeID:   nodeId,
+		Previous: state.status,
+		Current:  status,
+		LastSeen: state.lastSeen,
+	})
+	state.status = status
+	state.written = state.lastSeen
+	select {
+	case nodeStatusChanged <- struct{}{}:
+	default:
+	}
+}
+
+// publishNodeStatus persists status changes and emits them as node_status events, in
+// the order they happened. A slow monitorChan consumer only holds up this goroutine.
+func publishNodeStatus(ctx context.Context, repo *db.Repo, monitorChan chan Msg) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-nodeStatusChanged:
+		}
+		nodeStatusMu.Lock()
+		events := nodeStatusChanges
+		nodeStatusChanges = nil
+		nodeStatusMu.Unlock()
+
+		for _, event := range events {
+			err := repo.Queries.SetNodeStatus(ctx, db.SetNodeStatusParams{
+				Status: event.Current,
+				ID:     int64(event.NodeID),
+			})
+			if err != nil {
+				fmt.Println("Error updating node status", err)
+				continue
+			}
+			touchNode(ctx, repo, event.NodeID, event.LastSeen)
+			fmt.Println("Node", event.NodeID, "status changed from", event.Previous, "to", event.Current)
+
+			data, err := event.ToBytes()
+			if err != nil {
+				fmt.Println("Error encoding node status event", err)
+				continue
+			}
+			monitorChan <- Msg{
+				Msg:    "node_status",
+				NodeId: event.NodeID,
+				Data:   data,
+			}
+		}
+	}
+}
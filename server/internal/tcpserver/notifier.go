@@ -0,0 +1,233 @@
+package tcpserver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// Notifier sends alert messages to one notification channel
+type Notifier interface {
+	Send(msg AlertMsg) error
+}
+
+// NotifierFactory builds a Notifier from the JSON config of a notification channel,
+// failing when the config is incomplete
+type NotifierFactory func(config json.RawMessage) (Notifier, error)
+
+// notifierTypes are the channel types a notification channel can have. A new type
+// only needs an entry here.
+var notifierTypes = map[string]NotifierFactory{
+	"discord":   newDiscordNotifier,
+	"slack":     newSlackNotifier,
+	"email":     newEmailNotifier,
+	"webhook":   newConfigNotifier[WebhookConfig](SendWebhookAlert),
+	"telegram":  newConfigNotifier[TelegramConfig](SendTelegramAlert),
+	"teams":     newConfigNotifier[TeamsConfig](SendTeamsAlert),
+	"ntfy":      newConfigNotifier[NtfyConfig](SendNtfyAlert),
+	"gotify":    newConfigNotifier[GotifyConfig](SendGotifyAlert),
+	"pagerduty": newConfigNotifier[PagerDutyConfig](SendPagerDutyAlert),
+	"opsgenie":  newConfigNotifier[OpsgenieConfig](SendOpsgenieAlert),
+}
+
+// NewNotifier builds the notifier of a channel type from its config
+func NewNotifier(channelType string, config json.RawMessage) (Notifier, error) {
+	factory, ok := notifierTypes[channelType]
+	if !ok {
+		return nil, fmt.Errorf("unknown channel type %q", channelType)
+	}
+	return factory(config)
+}
+
+// NotifierTypes returns the supported channel types
+func NotifierTypes() []string {
+	types := make([]string, 0, len(notifierTypes))
+	for channelType := range notifierTypes {
+		types = append(types, channelType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// decodeNotifierConfig unmarshals a channel config, rejecting unknown fields
+func decodeNotifierConfig(config json.RawMessage, v interface{}) error {
+	if len(config) == 0 {
+		config = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid channel config: %w", err)
+	}
+	return nil
+}
+
+type discordNotifier struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+func newDiscordNotifier(config json.RawMessage) (Notifier, error) {
+	n := &discordNotifier{}
+	if err := decodeNotifierConfig(config, n); err != nil {
+		return nil, err
+	}
+	if n.WebhookURL == "" {
+		return nil, fmt.Errorf("webhook_url is required")
+	}
+	return n, nil
+}
+
+func (n *discordNotifier) Send(msg AlertMsg) error {
+	return SendDiscordAlert(n.WebhookURL, msg)
+}
+
+type slackNotifier struct {
+	WebhookURL string `json:"webhook_url"`
+}
+
+func newSlackNotifier(config json.RawMessage) (Notifier, error) {
+	n := &slackNotifier{}
+	if err := decodeNotifierConfig(config, n); err != nil {
+		return nil, err
+	}
+	if n.WebhookURL == "" {
+		return nil, fmt.Errorf("webhook_url is required")
+	}
+	return n, nil
+}
+
+func (n *slackNotifier) Send(msg AlertMsg) error {
+	return SendSlackAlert(n.WebhookURL, msg)
+}
+
+type emailNotifier struct {
+	To string `json:"to"`
+}
+
+func newEmailNotifier(config json.RawMessage) (Notifier, error) {
+	n := &emailNotifier{}
+	if err := decodeNotifierConfig(config, n); err != nil {
+		return nil, err
+	}
+	if n.To == "" {
+		return nil, fmt.Errorf("to is required")
+	}
+	return n, nil
+}
+
+func (n *emailNotifier) Send(msg AlertMsg) error {
+	return SendEmailAlert(n.To, msg)
+}
+
+// attemptLog keeps the HTTP attempts of a notifier for the delivery log
+type attemptLog struct {
+	attempts []WebhookAttempt
+}
+
+func (l *attemptLog) record(attempts []WebhookAttempt) {
+	l.attempts = append(l.attempts, attempts...)
+}
+
+func (l *attemptLog) Attempts() []WebhookAttempt {
+	return l.attempts
+}
+
+// configNotifier is a notifier whose config validates itself and whose sender
+// reports every HTTP attempt it made
+type configNotifier[C any] struct {
+	attemptLog
+	config C
+	send   func(C, AlertMsg) ([]WebhookAttempt, error)
+}
+
+// newConfigNotifier returns the factory of a channel type that decodes and
+// validates a C, then hands it to send for every message
+func newConfigNotifier[C any, P interface {
+	*C
+	validate() error
+}](send func(C, AlertMsg) ([]WebhookAttempt, error)) NotifierFactory {
+	return func(config json.RawMessage) (Notifier, error) {
+		n := &configNotifier[C]{send: send}
+		if err := decodeNotifierConfig(config, &n.config); err != nil {
+			return nil, err
+		}
+		if err := P(&n.config).validate(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+}
+
+func (n *configNotifier[C]) Send(msg AlertMsg) error {
+	attempts, err := n.send(n.config, msg)
+	n.record(attempts)
+	return err
+}
+
+// attemptLogger is implemented by notifiers that keep their HTTP attempts,
+// SendToChannel stores them in the webhook delivery log
+type attemptLogger interface {
+	Attempts() []WebhookAttempt
+}
+
+// SendToChannel sends the message to a stored notification channel. HTTP attempts
+// of the channel are written to the webhook delivery log.
+func SendToChannel(ctx context.Context, repo *db.Repo, channel db.NotificationChannel, msg AlertMsg) NotificationDelivery {
+	delivery := NotificationDelivery{Channel: channel.Type, ChannelID: channel.ID, Name: channel.Name}
+	notifier, err := NewNotifier(channel.Type, json.RawMessage(channel.Config))
+	if err == nil {
+		err = notifier.Send(msg)
+		if logger, ok := notifier.(attemptLogger); ok {
+			logWebhookAttempts(ctx, repo, channel.ID, logger.Attempts())
+		}
+	}
+	if err != nil {
+		fmt.Printf("Failed to send alert to channel %s: %v\n", channel.Name, err)
+		delivery.Status, delivery.Error = "failed", err.Error()
+		return delivery
+	}
+	delivery.Status = "sent"
+	return delivery
+}
+
+// logWebhookAttempts writes the attempts of one delivery to the webhook delivery log
+func logWebhookAttempts(ctx context.Context, repo *db.Repo, channelId int64, attempts []WebhookAttempt) {
+	for _, attempt := range attempts {
+		err := repo.Queries.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			ChannelID:  channelId,
+			Attempt:    int64(attempt.Attempt),
+			Url:        attempt.URL,
+			StatusCode: sql.NullInt64{Int64: int64(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+			Error:      sql.NullString{String: attempt.Error, Valid: attempt.Error != ""},
+			Response:   sql.NullString{String: attempt.Response, Valid: attempt.Response != ""},
+			DurationMs: attempt.Duration.Milliseconds(),
+			CreatedAt:  attempt.SentAt.Unix(),
+		})
+		if err != nil {
+			fmt.Println("Error logging webhook delivery", err)
+		}
+	}
+}
+
+// sendAlertNotifications sends alert to the notification channels of a rule, each
+// in its own goroutine so a channel that retries does not hold up the others. The
+// deliveries are in the order of the channels.
+func sendAlertNotifications(ctx context.Context, repo *db.Repo, channels []db.NotificationChannel, alertMsg AlertMsg) []NotificationDelivery {
+	deliveries := make([]NotificationDelivery, len(channels))
+	var wg sync.WaitGroup
+	for i, channel := range channels {
+		wg.Add(1)
+		go func(i int, channel db.NotificationChannel) {
+			defer wg.Done()
+			deliveries[i] = SendToChannel(ctx, repo, channel, alertMsg)
+		}(i, channel)
+	}
+	wg.Wait()
+	return deliveries
+}
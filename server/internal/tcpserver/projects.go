@@ -0,0 +1,228 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+// ProjectConfigFile is the file agents look for when scanning for projects
+const ProjectConfigFile = "config.vpspilot.json"
+
+// RequestProjectScan asks the node's agent to scan for project config files now
+func RequestProjectScan(nodeId int32) error {
+	return SendToNode(nodeId, Msg{Msg: "scan_projects", NodeId: nodeId})
+}
+
+// ReconcileDiscoveredProjects creates or updates the node's projects from the configs
+// reported by its agent. A config in a project's deploy_path, its current, repo or
+// release directories belongs to that project, the one in current wins. Any other
+// directory is a project of its own. Projects whose config is no longer reported
+// are flagged with config_missing rather than removed.
+func ReconcileDiscoveredProjects(ctx context.Context, repo *db.Repo, nodeId int32, discovered []DiscoveredProject) error {
+	projects, err := repo.Queries.ListNodeProjects(ctx, int64(nodeId))
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	// parents first, so a new project exists before the configs of its releases
+	sort.Slice(discovered, func(i, j int) bool {
+		return path.Clean(discovered[i].Path) < path.Clean(discovered[j].Path)
+	})
+
+	best := make(map[string]DiscoveredProject) // config each project is updated from
+	fresh := make(map[string]bool)             // projects created from this report
+	for _, found := range discovered {
+		dir := path.Clean(found.Path)
+		if !utils.ValidDeployPath(dir) {
+			continue
+		}
+		found.Path = dir
+
+		if project, ok := projectForPath(projects, dir); ok {
+			if current, ok := best[project.ID]; !ok || betterConfig(project, dir, current.Path) {
+				best[project.ID] = found
+			}
+			continue
+		}
+
+		created, err := createDiscoveredProject(ctx, repo, nodeId, found)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Project discovered", created.Name, "on node", nodeId)
+		projects = append(projects, created)
+		best[created.ID] = found
+		fresh[created.ID] = true
+	}
+
+	for _, project := range projects {
+		found, ok := best[project.ID]
+		switch {
+		case ok && fresh[project.ID] && found.Path == project.DeployPath:
+			// created from this config
+		case ok:
+			if err := updateDiscoveredProject(ctx, repo, project, found); err != nil {
+				return err
+			}
+		case project.ConfigSeenAt.Valid && project.ConfigMissing == 0:
+			if err := repo.Queries.SetProjectConfigMissing(ctx, project.ID); err != nil {
+				return fmt.Errorf("failed to flag project %s: %w", project.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func createDiscoveredProject(ctx context.Context, repo *db.Repo, nodeId int32, found DiscoveredProject) (db.Project, error) {
+	config := found.Config
+	name := config.Name
+	if name == "" {
+		name = path.Base(found.Path)
+	}
+	branch := found.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return db.Project{}, err
+	}
+	created, err := repo.Queries.CreateDiscoveredProject(ctx, db.CreateDiscoveredProjectParams{
+		Name:          name,
+		NodeID:        int64(nodeId),
+		RepoUrl:       sql.NullString{String: found.RepoURL, Valid: found.RepoURL != ""},
+		Branch:        sql.NullString{String: branch, Valid: true},
+		DeployPath:    found.Path,
+		WebhookSecret: sql.NullString{String: secret, Valid: true},
+		Tech:          encodeConfigJSON(config.Tech),
+		Commands:      encodeConfigJSON(config.Commands),
+		LogPaths:      encodeConfigJSON(config.Logs),
+		BackupConfig:  encodeConfigJSON(config.Backups),
+		ConfigPath:    sql.NullString{String: path.Join(found.Path, ProjectConfigFile), Valid: true},
+	})
+	if err != nil {
+		return db.Project{}, fmt.Errorf("failed to create project for %s: %w", found.Path, err)
+	}
+	return created, nil
+}
+
+func updateDiscoveredProject(ctx context.Context, repo *db.Repo, project db.Project, found DiscoveredProject) error {
+	config := found.Config
+	params := db.UpdateProjectConfigParams{
+		Name:         project.Name,
+		RepoUrl:      project.RepoUrl,
+		Branch:       project.Branch,
+		Tech:         encodeConfigJSON(config.Tech),
+		Commands:     encodeConfigJSON(config.Commands),
+		LogPaths:     encodeConfigJSON(config.Logs),
+		BackupConfig: encodeConfigJSON(config.Backups),
+		ConfigPath:   sql.NullString{String: path.Join(found.Path, ProjectConfigFile), Valid: true},
+		ID:           project.ID,
+	}
+	// projects added by hand keep what the user entered
+	if project.Source == "discovered" {
+		if config.Name != "" {
+			params.Name = config.Name
+		}
+		if found.RepoURL != "" {
+			params.RepoUrl = sql.NullString{String: found.RepoURL, Valid: true}
+		}
+		if found.Branch != "" {
+			params.Branch = sql.NullString{String: found.Branch, Valid: true}
+		}
+	}
+	if err := repo.Queries.UpdateProjectConfig(ctx, params); err != nil {
+		return fmt.Errorf("failed to update project %s: %w", project.ID, err)
+	}
+	return nil
+}
+
+// projectForPath returns the project dir belongs to: the one deployed to dir, or
+// else the one whose current, repo or releases/<id> directory dir is
+func projectForPath(projects []db.Project, dir string) (db.Project, bool) {
+	var match db.Project
+	matched := false
+	for _, project := range projects {
+		switch configRank(project, dir) {
+		case rankDeployPath:
+			return project, true
+		case -1:
+		default:
+			if !matched {
+				match, matched = project, true
+			}
+		}
+	}
+	return match, matched
+}
+
+// ranks of the directories of a project, see configRank
+const (
+	rankRelease = iota
+	rankRepo
+	rankDeployPath
+	rankCurrent
+)
+
+// configRank orders the directories of a project by how well their config describes
+// it: current, then deploy_path, then the repo checkout, then the releases. It is -1
+// when dir is not a directory of the project.
+func configRank(project db.Project, dir string) int {
+	deployPath := path.Clean(project.DeployPath)
+	switch dir {
+	case path.Join(deployPath, "current"):
+		return rankCurrent
+	case deployPath:
+		return rankDeployPath
+	case path.Join(deployPath, "repo"):
+		return rankRepo
+	}
+	if path.Dir(dir) == path.Join(deployPath, "releases") {
+		return rankRelease
+	}
+	return -1
+}
+
+// betterConfig reports whether the config in dir describes the project better than
+// the one in than. Of two releases the newest wins, release directories are named
+// after their increasing deployment ids.
+func betterConfig(project db.Project, dir, than string) bool {
+	rank, thanRank := configRank(project, dir), configRank(project, than)
+	if rank != thanRank {
+		return rank > thanRank
+	}
+	if rank != rankRelease {
+		return false
+	}
+	release, thanRelease := path.Base(dir), path.Base(than)
+	if len(release) != len(thanRelease) {
+		return len(release) > len(thanRelease)
+	}
+	return release > thanRelease
+}
+
+func encodeConfigJSON(v interface{}) sql.NullString {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+func handleProjectsDiscovered(ctx context.Context, repo *db.Repo, nodeId int32, data []byte) {
+	discovered := ProjectsDiscovered{}
+	if err := discovered.FromBytes(data); err != nil {
+		fmt.Println("Error unmarshalling discovered projects", err)
+		return
+	}
+	if err := ReconcileDiscoveredProjects(ctx, repo, nodeId, discovered.Projects); err != nil {
+		fmt.Println("Error reconciling discovered projects", err)
+	}
+}
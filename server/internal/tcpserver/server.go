@@ -2,77 +2,216 @@ package tcpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"net"
-	"strings"
+	"time"
 
 	"github.com/sanda0/vps_pilot/internal/db"
 )
 
-var AgentConnections map[string]net.Conn
+// agentAuthTimeout is how long a new connection has to finish the TLS handshake and
+// the "connected" handshake before it is dropped
+const agentAuthTimeout = 30 * time.Second
 
 func StartTcpServer(ctx context.Context, repo *db.Repo, port string) {
 
 	var statChan = make(chan Msg, 100)
 	var monitorChan = make(chan Msg, 100)
 
-	AgentConnections = make(map[string]net.Conn)
-	listener, err := net.Listen("tcp", ":"+port)
+	tlsConfig, tlsMode, err := NewTLSConfig(ctx, repo)
 	if err != nil {
+		fmt.Println("Error configuring TLS:", err)
+		return
+	}
+
+	var listener net.Listener
+	if tlsConfig != nil {
+		listener, err = tls.Listen("tcp", ":"+port, tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", ":"+port)
+	}
+	if err != nil {
+		fmt.Println("Error starting TCP server:", err)
 		return
 	}
 	defer listener.Close()
-	fmt.Println("TCP server Listening on port", port)
+	fmt.Println("TCP server Listening on port", port, "tls mode:", tlsMode)
+
+	// executions cannot survive a restart, their agents lost the correlation
+	if err := repo.Queries.FailRunningCommandExecutions(ctx); err != nil {
+		fmt.Println("Error closing stale executions:", err)
+	}
 
 	go StoreSystemStats(ctx, repo, statChan)
 	go MontiorAlerts(ctx, repo, monitorChan)
+	go MonitorNodeStatus(ctx, repo, monitorChan)
+	go MonitorCronJobs(ctx, repo)
 
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
 			return
 		}
-		AgentConnections[conn.RemoteAddr().String()] = conn
-		go handleRequest(ctx, repo, conn, statChan, monitorChan)
+		go handleRequest(ctx, repo, conn, tlsMode, statChan, monitorChan)
 	}
 }
 
-func handleRequest(ctx context.Context, repo *db.Repo, conn net.Conn, statChan chan Msg, monitorChan chan Msg) {
+func handleRequest(ctx context.Context, repo *db.Repo, conn net.Conn, tlsMode string, statChan chan Msg, monitorChan chan Msg) {
 	defer conn.Close()
 	fmt.Println("New connection from", conn.RemoteAddr())
+	// a peer that never authenticates must not hold the connection open, the
+	// deadline is cleared once the agent is registered
+	if err := conn.SetDeadline(time.Now().Add(agentAuthTimeout)); err != nil {
+		fmt.Println("Error setting connection deadline", err)
+		return
+	}
 
 	decoder := gob.NewDecoder(conn)
 	encoder := gob.NewEncoder(conn)
-	var msg Msg
+
+	// nodeId is only set once the agent has presented a valid token or client certificate
+	var nodeId int32
+	var credential agentCredential
+	// agentConn is registered on the command bus once the handshake completes
+	var agentConn *AgentConn
+	defer func() {
+		if agentConn != nil {
+			unregisterAgentConn(agentConn)
+			failPendingCommands(agentConn)
+		}
+	}()
+	if tlsConn, ok := conn.(*tls.Conn); ok && tlsMode == TLSModeMTLS {
+		// an agent without a certificate enrolls with a token and is issued one below
+		node, cert, err := AuthenticateCertificate(ctx, repo, tlsConn)
+		if err != nil && !errors.Is(err, ErrNoClientCertificate) {
+			fmt.Println("Agent certificate rejected for", conn.RemoteAddr(), err)
+			rejectAgent(encoder, err)
+			return
+		}
+		if err == nil {
+			nodeId = int32(node.ID)
+			credential.certId = cert.ID
+		}
+	}
+
 	for {
+		var msg Msg
 		err := decoder.Decode(&msg)
 		if err != nil {
 			break
 		}
 		if msg.Msg == "connected" {
-			ip := strings.Split(conn.RemoteAddr().String(), ":")[0]
-			node, err := CreateNode(ctx, repo, ip, msg.Data)
-			if err != nil {
-				fmt.Println("Error creating node", err)
+			// the connection stays bound to the node it first authenticated as
+			if agentConn != nil {
+				fmt.Println("Rejecting a second handshake from node", nodeId, conn.RemoteAddr())
+				rejectAgent(encoder, ErrAlreadyAuthenticated)
+				return
+			}
+			// a client certificate already identified the node
+			if credential.certId == 0 {
+				agentToken, err := AuthenticateAgent(ctx, repo, msg.Token)
+				if err != nil {
+					fmt.Println("Agent authentication failed for", conn.RemoteAddr(), err)
+					rejectAgent(encoder, err)
+					return
+				}
+				node, err := RegisterAgent(ctx, repo, agentToken, remoteIP(conn), msg.Data)
+				if err != nil {
+					fmt.Println("Error registering node", err)
+					rejectAgent(encoder, err)
+					return
+				}
+				nodeId = int32(node.ID)
+				credential.tokenId = agentToken.ID
+			} else {
+				node, err := repo.Queries.GetNode(ctx, int64(nodeId))
+				if err == nil {
+					sysInfo := SystemInfo{}
+					sysInfo.FromBytes(msg.Data)
+					err = BindNodeIdentity(ctx, repo, &node, sysInfo.MachineID, remoteIP(conn))
+				}
+				if err != nil {
+					fmt.Println("Error binding node identity", err)
+					rejectAgent(encoder, err)
+					return
+				}
+			}
+			fmt.Println("Node connected", nodeId)
+			refreshSysInfo(ctx, repo, nodeId, msg.Data)
+			if err := conn.SetDeadline(time.Time{}); err != nil {
+				fmt.Println("Error clearing connection deadline", err)
+				return
 			}
-			fmt.Println("Node connected", node)
-			err = encoder.Encode(Msg{
+			agentConn = registerAgentConn(nodeId, conn, encoder, credential)
+			if tlsMode == TLSModeMTLS && credential.tokenId != 0 {
+				if err := sendAgentCertificate(ctx, repo, agentConn); err != nil {
+					fmt.Println("Error issuing agent certificate", err)
+				}
+			}
+			err = agentConn.Send(Msg{
 				Msg:    "sys_stat",
-				NodeId: int32(node.ID),
+				NodeId: nodeId,
 			})
 			if err != nil {
 				fmt.Println("Error encoding message:", err)
 			}
+			if err := SyncCronJobs(ctx, repo, nodeId); err != nil {
+				fmt.Println("Error syncing cron jobs", err)
+			}
+			continue
 		}
-		if msg.Msg == "sys_info" {
+		if nodeId == 0 {
+			fmt.Println("Rejecting message from unauthenticated agent", conn.RemoteAddr())
+			rejectAgent(encoder, ErrInvalidAgentToken)
+			return
+		}
+		// never trust the node id sent by the agent
+		msg.NodeId = nodeId
 
+		if msg.CorrelationId != "" {
+			if !routeReply(agentConn, msg) {
+				fmt.Println("Dropping reply for unknown correlation id", msg.CorrelationId)
+			}
+			continue
+		}
+
+		if msg.Msg == "sys_info" {
 			fmt.Println("Sys info received", string(msg.Data))
+			refreshSysInfo(ctx, repo, nodeId, msg.Data)
+		}
+		if msg.Msg == "projects_discovered" {
+			handleProjectsDiscovered(ctx, repo, nodeId, msg.Data)
+		}
+		if msg.Msg == "cron_run" {
+			handleCronRun(ctx, repo, nodeId, msg.Data)
 		}
 		if msg.Msg == "sys_stat" {
+			MarkNodeSeen(ctx, repo, nodeId)
 			statChan <- msg
 			monitorChan <- msg
 		}
 	}
 
 }
+
+// remoteIP returns the address of the agent without the port
+func remoteIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func rejectAgent(encoder *gob.Encoder, reason error) {
+	err := encoder.Encode(Msg{
+		Msg:  "unauthorized",
+		Data: []byte(reason.Error()),
+	})
+	if err != nil {
+		fmt.Println("Error encoding message:", err)
+	}
+}
@@ -0,0 +1,95 @@
+package tcpserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// UpsertNodeSysInfo stores the sys info reported by the agent and appends a history
+// entry whenever it differs from what is stored, e.g. after a kernel upgrade or RAM resize.
+func UpsertNodeSysInfo(ctx context.Context, repo *db.Repo, nodeId int64, sysInfo SystemInfo) error {
+	tx, err := repo.OperationalDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	queries := repo.Queries.WithTx(tx)
+
+	os := sql.NullString{String: sysInfo.OS, Valid: true}
+	platform := sql.NullString{String: sysInfo.Platform, Valid: true}
+	platformVersion := sql.NullString{String: sysInfo.PlatformVersion, Valid: true}
+	kernelVersion := sql.NullString{String: sysInfo.KernelVersion, Valid: true}
+	cpus := sql.NullInt64{Int64: int64(sysInfo.CPUs), Valid: true}
+	totalMemory := sql.NullFloat64{Float64: float64(sysInfo.TotalMemory), Valid: true}
+
+	existing, err := queries.GetNodeSysInfoByNodeID(ctx, nodeId)
+	if err == sql.ErrNoRows {
+		_, err = queries.AddNodeSysInfo(ctx, db.AddNodeSysInfoParams{
+			NodeID:          nodeId,
+			Os:              os,
+			Platform:        platform,
+			PlatformVersion: platformVersion,
+			KernelVersion:   kernelVersion,
+			Cpus:            cpus,
+			TotalMemory:     totalMemory,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add sys info: %w", err)
+		}
+		fmt.Println("Node sys info added for node", nodeId)
+	} else if err != nil {
+		return fmt.Errorf("failed to get sys info: %w", err)
+	} else {
+		if existing.Os == os &&
+			existing.Platform == platform &&
+			existing.PlatformVersion == platformVersion &&
+			existing.KernelVersion == kernelVersion &&
+			existing.Cpus == cpus &&
+			existing.TotalMemory == totalMemory {
+			return nil
+		}
+		_, err = queries.UpdateNodeSysInfo(ctx, db.UpdateNodeSysInfoParams{
+			Os:              os,
+			Platform:        platform,
+			PlatformVersion: platformVersion,
+			KernelVersion:   kernelVersion,
+			Cpus:            cpus,
+			TotalMemory:     totalMemory,
+			NodeID:          nodeId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update sys info: %w", err)
+		}
+		fmt.Println("Node sys info changed for node", nodeId)
+	}
+
+	err = queries.AddNodeSysInfoHistory(ctx, db.AddNodeSysInfoHistoryParams{
+		NodeID:          nodeId,
+		Os:              os,
+		Platform:        platform,
+		PlatformVersion: platformVersion,
+		KernelVersion:   kernelVersion,
+		Cpus:            cpus,
+		TotalMemory:     totalMemory,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record sys info history: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// refreshSysInfo decodes a sys info payload from the agent and upserts it for the node
+func refreshSysInfo(ctx context.Context, repo *db.Repo, nodeId int32, data []byte) {
+	sysInfo := SystemInfo{}
+	if err := sysInfo.FromBytes(data); err != nil {
+		fmt.Println("Error unmarshalling system info", err)
+		return
+	}
+	if err := UpsertNodeSysInfo(ctx, repo, int64(nodeId), sysInfo); err != nil {
+		fmt.Println("Error updating node sys info", err)
+	}
+}
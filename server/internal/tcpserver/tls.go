@@ -0,0 +1,232 @@
+package tcpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+const (
+	TLSModeOff  = "off"
+	TLSModeTLS  = "tls"
+	TLSModeMTLS = "mtls"
+
+	caValidity         = 10 * 365 * 24 * time.Hour
+	serverCertValidity = 365 * 24 * time.Hour
+	// enrolledCertValidity is the validity of a certificate issued in exchange for an enrollment token
+	enrolledCertValidity = 365 * 24 * time.Hour
+)
+
+var (
+	ErrUnknownCertificate  = errors.New("client certificate is not registered")
+	ErrRevokedCertificate  = errors.New("client certificate has been revoked")
+	ErrNoClientCertificate = errors.New("no client certificate presented")
+)
+
+// AgentCertificate is the Data of the "agent_certificate" message. In mtls mode an agent
+// that enrolled with a token is issued a client certificate, which it presents on its
+// next connections instead of the token.
+type AgentCertificate struct {
+	CertPEM string `json:"cert_pem"`
+	KeyPEM  string `json:"key_pem"`
+	CaPEM   string `json:"ca_pem"`
+}
+
+var caMutex sync.Mutex
+
+// LoadCertificateAuthority returns the built-in CA, creating it on first use
+func LoadCertificateAuthority(ctx context.Context, repo *db.Repo) (*db.CertificateAuthority, error) {
+	caMutex.Lock()
+	defer caMutex.Unlock()
+
+	ca, err := repo.Queries.GetCertificateAuthority(ctx)
+	if err == nil {
+		return &ca, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	issued, err := utils.GenerateCA("VPS Pilot Agent CA", caValidity)
+	if err != nil {
+		return nil, err
+	}
+	ca, err = repo.Queries.CreateCertificateAuthority(ctx, db.CreateCertificateAuthorityParams{
+		CertPem: string(issued.CertPEM),
+		KeyPem:  string(issued.KeyPEM),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store certificate authority: %w", err)
+	}
+	fmt.Println("Created built-in certificate authority")
+	return &ca, nil
+}
+
+// IssueAgentCertificate signs a client certificate that identifies the given node
+func IssueAgentCertificate(ctx context.Context, repo *db.Repo, nodeId int64, validity time.Duration) (*utils.IssuedCertificate, *db.AgentCertificate, error) {
+	ca, err := LoadCertificateAuthority(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issued, err := utils.IssueCertificate([]byte(ca.CertPem), []byte(ca.KeyPem), fmt.Sprintf("node-%d", nodeId), nil, true, validity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := repo.Queries.CreateAgentCertificate(ctx, db.CreateAgentCertificateParams{
+		NodeID:      nodeId,
+		Serial:      issued.Serial,
+		Fingerprint: issued.Fingerprint,
+		NotAfter:    issued.NotAfter.Unix(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to store agent certificate: %w", err)
+	}
+	return issued, &cert, nil
+}
+
+// NewTLSConfig builds the listener TLS configuration from the environment.
+// It returns a nil config when TLS is disabled.
+//
+//	TCP_TLS_MODE      off (default), tls or mtls
+//	TCP_TLS_CERT_FILE server certificate (required for tls, optional for mtls)
+//	TCP_TLS_KEY_FILE  server private key
+//	TCP_TLS_HOSTS     comma separated hostnames/IPs for the CA issued server certificate
+func NewTLSConfig(ctx context.Context, repo *db.Repo) (*tls.Config, string, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("TCP_TLS_MODE")))
+	if mode == "" {
+		mode = TLSModeOff
+	}
+
+	certFile := os.Getenv("TCP_TLS_CERT_FILE")
+	keyFile := os.Getenv("TCP_TLS_KEY_FILE")
+
+	switch mode {
+	case TLSModeOff:
+		return nil, mode, nil
+	case TLSModeTLS:
+		if certFile == "" || keyFile == "" {
+			return nil, mode, fmt.Errorf("TCP_TLS_CERT_FILE and TCP_TLS_KEY_FILE are required in tls mode")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, mode, fmt.Errorf("failed to load server certificate: %w", err)
+		}
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}, mode, nil
+	case TLSModeMTLS:
+		ca, err := LoadCertificateAuthority(ctx, repo)
+		if err != nil {
+			return nil, mode, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca.CertPem)) {
+			return nil, mode, fmt.Errorf("failed to load certificate authority")
+		}
+
+		var cert tls.Certificate
+		if certFile != "" && keyFile != "" {
+			cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+		} else {
+			cert, err = issueServerCertificate(ca)
+		}
+		if err != nil {
+			return nil, mode, fmt.Errorf("failed to load server certificate: %w", err)
+		}
+
+		// an agent without a certificate yet enrolls with a token and is issued one
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientCAs:    pool,
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			MinVersion:   tls.VersionTLS12,
+		}, mode, nil
+	default:
+		return nil, mode, fmt.Errorf("unknown TCP_TLS_MODE %q", mode)
+	}
+}
+
+// AuthenticateCertificate maps the verified client certificate of an mTLS connection to
+// its node. It returns ErrNoClientCertificate when the agent presented none.
+func AuthenticateCertificate(ctx context.Context, repo *db.Repo, conn *tls.Conn) (*db.Node, *db.AgentCertificate, error) {
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, nil, fmt.Errorf("tls handshake failed: %w", err)
+	}
+
+	peerCerts := conn.ConnectionState().PeerCertificates
+	if len(peerCerts) == 0 {
+		return nil, nil, ErrNoClientCertificate
+	}
+
+	cert, err := repo.Queries.GetAgentCertificateBySerial(ctx, utils.CertificateSerial(peerCerts[0]))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrUnknownCertificate
+		}
+		return nil, nil, err
+	}
+	if cert.RevokedAt.Valid {
+		return nil, nil, ErrRevokedCertificate
+	}
+	if cert.Fingerprint != utils.CertificateFingerprint(peerCerts[0]) {
+		return nil, nil, ErrUnknownCertificate
+	}
+
+	node, err := repo.Queries.GetNode(ctx, cert.NodeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &node, &cert, nil
+}
+
+// sendAgentCertificate issues a client certificate for the node of an agent that
+// enrolled with a token and hands it to the agent
+func sendAgentCertificate(ctx context.Context, repo *db.Repo, agentConn *AgentConn) error {
+	issued, _, err := IssueAgentCertificate(ctx, repo, int64(agentConn.NodeId), enrolledCertValidity)
+	if err != nil {
+		return err
+	}
+	ca, err := LoadCertificateAuthority(ctx, repo)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(AgentCertificate{
+		CertPEM: string(issued.CertPEM),
+		KeyPEM:  string(issued.KeyPEM),
+		CaPEM:   ca.CertPem,
+	})
+	if err != nil {
+		return err
+	}
+	return agentConn.Send(Msg{Msg: "agent_certificate", NodeId: agentConn.NodeId, Data: data})
+}
+
+func issueServerCertificate(ca *db.CertificateAuthority) (tls.Certificate, error) {
+	hosts := []string{"localhost"}
+	if hostsEnv := os.Getenv("TCP_TLS_HOSTS"); hostsEnv != "" {
+		hosts = strings.Split(hostsEnv, ",")
+		for i := range hosts {
+			hosts[i] = strings.TrimSpace(hosts[i])
+		}
+	}
+
+	issued, err := utils.IssueCertificate([]byte(ca.CertPem), []byte(ca.KeyPem), hosts[0], hosts, false, serverCertValidity)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(issued.CertPEM, issued.KeyPEM)
+}
@@ -3,6 +3,7 @@ package tcpserver
 import "encoding/json"
 
 type SystemInfo struct {
+	MachineID       string `json:"machine_id"`       // persistent machine UUID generated by the agent
 	OS              string `json:"os"`               // e.g. linux, windows
 	Platform        string `json:"platform"`         // e.g. ubuntu, centos
 	PlatformVersion string `json:"platform_version"` // e.g. 20.04, 8
@@ -15,6 +16,9 @@ func (s *SystemInfo) FromBytes(data []byte) error {
 	return json.Unmarshal(data, s)
 }
 
+// AllMountsDiskMountpoint is the disk_stat mountpoint under which the aggregate disk usage is stored
+const AllMountsDiskMountpoint = "*"
+
 type Disk struct {
 	Device     string `json:"device"`     // e.g. /dev/sda1
 	Mountpoint string `json:"mountpoint"` // e.g. /
@@ -24,12 +28,21 @@ type Disk struct {
 	Used       uint64 `json:"used"`       // used disk space in bytes
 }
 
+// UsedPercent returns the used space of the mount as a percentage
+func (d *Disk) UsedPercent() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.Used) / float64(d.Total) * 100
+}
+
 type SystemStat struct {
 	CPUUsage  []float64 `json:"cpu_usage"`
 	MemUsage  float64   `json:"mem_usage"`
-	DiskUsage float64   `json:"disk_usage"`
+	DiskUsage float64   `json:"disk_usage"` // aggregate used percent across all mounts
 	NetSentPS int64     `json:"net_sent_ps"`
 	NetRecvPS int64     `json:"net_recv_ps"`
+	Disks     []Disk    `json:"disks"` // per-mount usage, empty for older agents
 }
 
 func (s *SystemStat) FromBytes(data []byte) error {
@@ -37,8 +50,59 @@ func (s *SystemStat) FromBytes(data []byte) error {
 }
 
 type Msg struct {
-	Msg    string
-	NodeId int32
-	Token  string
-	Data   []byte
+	Msg           string
+	NodeId        int32
+	Token         string
+	CorrelationId string // set on server commands and echoed back on the agent's replies
+	Data          []byte
+}
+
+// ProjectConfig is the content of a config.vpspilot.json file
+type ProjectConfig struct {
+	Name     string           `json:"name"`
+	Tech     []string         `json:"tech"`
+	Logs     []string         `json:"logs"` // log files, relative to the project directory or absolute
+	Commands []ProjectCommand `json:"commands"`
+	Backups  *BackupConfig    `json:"backups,omitempty"`
+}
+
+type ProjectCommand struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// BackupConfig describes what a project backup contains. The database fields
+// name the variables in env_file that hold the connection settings.
+type BackupConfig struct {
+	EnvFile     string          `json:"env_file"`
+	ZipFileName string          `json:"zip_file_name"`
+	Database    *BackupDatabase `json:"database,omitempty"`
+	Dir         []string        `json:"dir"`
+}
+
+type BackupDatabase struct {
+	Connection   string `json:"connection"`
+	Host         string `json:"host"`
+	Port         string `json:"port"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	DatabaseName string `json:"database_name"`
+}
+
+// DiscoveredProject is a config.vpspilot.json found by the agent
+type DiscoveredProject struct {
+	Path    string        `json:"path"`               // directory holding the config file
+	RepoURL string        `json:"repo_url,omitempty"` // origin remote, when the directory is a git checkout
+	Branch  string        `json:"branch,omitempty"`
+	Config  ProjectConfig `json:"config"`
+}
+
+// ProjectsDiscovered is the Data of a "projects_discovered" message. It lists every
+// project the agent found, so projects missing from it no longer have a config file.
+type ProjectsDiscovered struct {
+	Projects []DiscoveredProject `json:"projects"`
+}
+
+func (p *ProjectsDiscovered) FromBytes(data []byte) error {
+	return json.Unmarshal(data, p)
 }
@@ -0,0 +1,273 @@
+package tcpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultWebhookSignatureHeader = "X-VPS-Pilot-Signature-256"
+	defaultWebhookMaxAttempts     = 3
+	defaultWebhookBackoff         = time.Second
+	defaultWebhookTimeout         = 10 * time.Second
+	maxWebhookBackoff             = 5 * time.Minute
+	maxWebhookDeliveryTime        = 2 * time.Minute // all attempts of one delivery, waits included
+	webhookResponseLimit          = 1024            // bytes of the response body kept in the delivery log
+)
+
+// WebhookConfig is the config of a webhook notification channel
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"` // POST by default, PUT or PATCH
+	Headers map[string]string `json:"headers"`
+	// Body is a Go template over AlertMsg that must render JSON, for example
+	// {"text": {{json .NodeName}}, "status": "{{.Status}}"}. When empty the message
+	// is sent as a JSON document.
+	Body string `json:"body"`
+	// Secret signs the body with HMAC-SHA256, sent as "sha256=<hex>" in SignatureHeader
+	Secret          string `json:"secret"`
+	SignatureHeader string `json:"signature_header"`
+	MaxAttempts     int    `json:"max_attempts"`    // 3 by default, at most 10
+	BackoffMs       int    `json:"backoff_ms"`      // wait before the first retry, doubled for each further one
+	TimeoutSeconds  int    `json:"timeout_seconds"` // per attempt, 10 by default
+}
+
+// WebhookAttempt is one HTTP request made to deliver a notification
+type WebhookAttempt struct {
+	Attempt    int
+	URL        string
+	StatusCode int // 0 when no response came back
+	Error      string
+	Response   string // start of the response body
+	Duration   time.Duration
+	SentAt     time.Time
+}
+
+// WebhookRetry is how often a failed request is repeated and how long to wait in between
+type WebhookRetry struct {
+	MaxAttempts int
+	Backoff     time.Duration // wait before the second attempt, doubled for every further one
+	MaxElapsed  time.Duration // time all attempts may take together, 2 minutes by default
+}
+
+// webhookPayload is the body sent when a webhook has no body template
+type webhookPayload struct {
+	Status       string    `json:"status"`
+	Node         string    `json:"node"`
+	NodeIP       string    `json:"node_ip"`
+	Metric       string    `json:"metric"`
+	Threshold    string    `json:"threshold"`
+	CurrentValue string    `json:"current_value"`
+	Timestamp    time.Time `json:"timestamp"`
+	Lasted       string    `json:"lasted,omitempty"`
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json quotes a value for use inside the JSON body
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// validate applies the defaults and checks the config. The body template is
+// rendered with a sample message, so a template producing invalid JSON is
+// rejected before any alert uses it.
+func (c *WebhookConfig) validate() error {
+	if err := checkHTTPURL("url", c.URL); err != nil {
+		return err
+	}
+	c.Method = strings.ToUpper(c.Method)
+	switch c.Method {
+	case "":
+		c.Method = http.MethodPost
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("method must be POST, PUT or PATCH")
+	}
+	if c.SignatureHeader == "" {
+		c.SignatureHeader = defaultWebhookSignatureHeader
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if c.MaxAttempts < 1 || c.MaxAttempts > 10 {
+		return fmt.Errorf("max_attempts must be between 1 and 10")
+	}
+	if c.BackoffMs < 0 || c.BackoffMs > 60000 {
+		return fmt.Errorf("backoff_ms must be between 0 and 60000")
+	}
+	if c.TimeoutSeconds < 0 || c.TimeoutSeconds > 120 {
+		return fmt.Errorf("timeout_seconds must be between 0 and 120")
+	}
+	_, err := c.render(AlertMsg{
+		NodeName:     "node",
+		NodeIp:       "127.0.0.1",
+		Metric:       "cpu",
+		Threshold:    "80%",
+		CurrentValue: "90%",
+		Timestamp:    time.Now(),
+	})
+	return err
+}
+
+// checkHTTPURL fails unless value is an absolute http or https URL
+func checkHTTPURL(field, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL", field)
+	}
+	return nil
+}
+
+// render builds the request body of a message
+func (c *WebhookConfig) render(alert AlertMsg) ([]byte, error) {
+	if c.Body == "" {
+		payload := webhookPayload{
+			Status:       alert.Status(),
+			Node:         alert.NodeName,
+			NodeIP:       alert.NodeIp,
+			Metric:       alert.Metric,
+			Threshold:    alert.Threshold,
+			CurrentValue: alert.CurrentValue,
+			Timestamp:    alert.Timestamp,
+		}
+		if alert.Resolved {
+			payload.Lasted = alert.LastedString()
+		}
+		return json.Marshal(payload)
+	}
+
+	tmpl, err := template.New("body").Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(c.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, alert); err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("body template does not render valid JSON")
+	}
+	return body.Bytes(), nil
+}
+
+// SignWebhookBody returns the signature header value of a body, "sha256=<hex>"
+// as GitHub does it
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhookAlert sends alert to a generic webhook and returns every attempt made
+func SendWebhookAlert(config WebhookConfig, alert AlertMsg) ([]WebhookAttempt, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	body, err := config.render(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultWebhookTimeout
+	if config.TimeoutSeconds > 0 {
+		timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+	backoff := defaultWebhookBackoff
+	if config.BackoffMs > 0 {
+		backoff = time.Duration(config.BackoffMs) * time.Millisecond
+	}
+
+	client := &http.Client{Timeout: timeout}
+	retry := WebhookRetry{MaxAttempts: config.MaxAttempts, Backoff: backoff}
+	attempts, err := sendWithRetry(client, retry, func() (*http.Request, error) {
+		req, err := http.NewRequest(config.Method, config.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "VPS-Pilot")
+		for name, value := range config.Headers {
+			req.Header.Set(name, value)
+		}
+		if config.Secret != "" {
+			req.Header.Set(config.SignatureHeader, SignWebhookBody(config.Secret, body))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return attempts, err
+	}
+
+	fmt.Println("Alert sent to webhook!")
+	return attempts, nil
+}
+
+// sendWithRetry sends the request built by newRequest until it gets a 2xx
+// response, a client error other than 429 or runs out of attempts. Network
+// errors, 429 and 5xx responses are retried with exponential backoff. Retries
+// stop once the next one would end after retry.MaxElapsed, and a request still
+// running at that point is cancelled.
+func sendWithRetry(client *http.Client, retry WebhookRetry, newRequest func() (*http.Request, error)) ([]WebhookAttempt, error) {
+	if retry.MaxElapsed <= 0 {
+		retry.MaxElapsed = maxWebhookDeliveryTime
+	}
+	deadline := time.Now().Add(retry.MaxElapsed)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	attempts := []WebhookAttempt{}
+	wait := retry.Backoff
+	for i := 1; ; i++ {
+		req, err := newRequest()
+		if err != nil {
+			return attempts, err
+		}
+		req = req.WithContext(ctx)
+		attempt := WebhookAttempt{Attempt: i, URL: req.URL.String(), SentAt: time.Now()}
+		retryable := true
+		resp, err := client.Do(req)
+		attempt.Duration = time.Since(attempt.SentAt)
+		if err == nil {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+			resp.Body.Close()
+			attempt.StatusCode = resp.StatusCode
+			attempt.Response = string(data)
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				err = fmt.Errorf("failed to send webhook, status code: %d", resp.StatusCode)
+				retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+			}
+		}
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		attempts = append(attempts, attempt)
+
+		if err == nil || !retryable || i >= retry.MaxAttempts {
+			return attempts, err
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return attempts, fmt.Errorf("gave up after %s: %w", retry.MaxElapsed, err)
+		}
+		time.Sleep(wait)
+		wait *= 2
+		if wait > maxWebhookBackoff {
+			wait = maxWebhookBackoff
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const agentTokenPrefix = "vpa_"
+
+// GenerateAgentToken returns a new random enrollment token for an agent
+func GenerateAgentToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return agentTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashAgentToken returns the SHA-256 hash that is stored in place of the raw token
+func HashAgentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// IssuedCertificate holds a PEM encoded certificate together with its private key
+type IssuedCertificate struct {
+	CertPEM     []byte
+	KeyPEM      []byte
+	Serial      string
+	Fingerprint string
+	NotAfter    time.Time
+}
+
+// GenerateCA creates a self-signed certificate authority
+func GenerateCA(commonName string, validity time.Duration) (*IssuedCertificate, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"VPS Pilot"}},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return createCertificate(template, nil, nil, validity)
+}
+
+// IssueCertificate signs a new leaf certificate with the given CA.
+// Client certificates are used by agents, server certificates by the TCP listener.
+func IssueCertificate(caCertPEM, caKeyPEM []byte, commonName string, hosts []string, isClient bool, validity time.Duration) (*IssuedCertificate, error) {
+	caCert, err := ParseCertificatePEM(caCertPEM)
+	if err != nil {
+		return nil, err
+	}
+	caKey, err := parseECPrivateKeyPEM(caKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: commonName, Organization: []string{"VPS Pilot"}},
+		KeyUsage: x509.KeyUsageDigitalSignature,
+	}
+	if isClient {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		for _, host := range hosts {
+			if ip := net.ParseIP(host); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else if host != "" {
+				template.DNSNames = append(template.DNSNames, host)
+			}
+		}
+	}
+	return createCertificate(template, caCert, caKey, validity)
+}
+
+// ParseCertificatePEM decodes the first certificate in a PEM block
+func ParseCertificatePEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("invalid certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// CertificateSerial returns the serial number format stored in the database
+func CertificateSerial(cert *x509.Certificate) string {
+	return hex.EncodeToString(cert.SerialNumber.Bytes())
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of a certificate
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func createCertificate(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, validity time.Duration) (*IssuedCertificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-5 * time.Minute)
+	template.NotAfter = time.Now().Add(validity)
+
+	// self-signed when no parent is given
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuedCertificate{
+		CertPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		Serial:      CertificateSerial(cert),
+		Fingerprint: CertificateFingerprint(cert),
+		NotAfter:    cert.NotAfter,
+	}, nil
+}
+
+func parseECPrivateKeyPEM(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key PEM")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
@@ -0,0 +1,9 @@
+package utils
+
+import "path"
+
+// ValidDeployPath reports whether releases, the repo checkout and the current symlink
+// can live under p on the node, i.e. p is absolute and not the filesystem root
+func ValidDeployPath(p string) bool {
+	return path.IsAbs(p) && path.Clean(p) != "/"
+}
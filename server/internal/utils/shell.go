@@ -0,0 +1,8 @@
+package utils
+
+import "strings"
+
+// ShellQuote quotes s for use as a single argument in a POSIX shell command
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
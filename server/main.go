@@ -11,6 +11,7 @@ import (
 	"github.com/sanda0/vps_pilot/cmd/app"
 	"github.com/sanda0/vps_pilot/cmd/cli"
 	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/services"
 	"github.com/sanda0/vps_pilot/internal/tcpserver"
 )
 
@@ -26,6 +27,7 @@ func main() {
 	createSuperuser := flag.Bool("create-superuser", false, "create superuser")
 	createMakefile := flag.Bool("create-makefile", false, "create makefile")
 	migrate := flag.Bool("migrate", false, "run database migrations")
+	createAgentToken := flag.Bool("create-agent-token", false, "create an agent enrollment token")
 	flag.Parse()
 
 	// Get database directory from environment or use default
@@ -82,6 +84,18 @@ func main() {
 		return
 	}
 
+	if *createAgentToken {
+		cli.CreateAgentToken(ctx, repo)
+		return
+	}
+
+	//fail deployments and backups interrupted by a restart
+	services.RecoverInterruptedDeployments(ctx, repo)
+	services.RecoverInterruptedBackups(ctx, repo)
+
+	//start scheduled project backups
+	go services.RunBackupScheduler(ctx, repo)
+
 	//init tcp server
 	go tcpserver.StartTcpServer(ctx, repo, "55001")
 
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+// newAgentToken creates a token, bound to nodeId unless it is 0
+func newAgentToken(t *testing.T, repo *db.Repo, name string, nodeId int64) *db.AgentToken {
+	t.Helper()
+	token, err := repo.Queries.CreateAgentToken(context.Background(), db.CreateAgentTokenParams{
+		Name:      name,
+		TokenHash: name,
+		NodeID:    sql.NullInt64{Int64: nodeId, Valid: nodeId != 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &token
+}
+
+func TestEnrollmentCreatesNewNode(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	first, err := tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "a", 0), "10.0.0.1", []byte(`{"machine_id": "m-1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "b", 0), "10.0.0.2", []byte(`{"machine_id": "m-2"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("two unbound tokens enrolled the same node %d", first.ID)
+	}
+}
+
+func TestEnrollmentCannotTakeOverNode(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	victim, err := tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "victim", 0), "10.0.0.1", []byte(`{"machine_id": "m-1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ip      string
+		info    string
+		wantErr error
+	}{
+		{"spoofed machine id", "10.0.0.9", `{"machine_id": "m-1"}`, tcpserver.ErrNodeAlreadyEnrolled},
+		// agents behind one NAT share an address but not a machine id
+		{"same address without machine id", "10.0.0.1", `{}`, nil},
+		{"same address with a new machine id", "10.0.0.1", `{"machine_id": "m-9"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := newAgentToken(t, repo, tt.name, 0)
+			node, err := tcpserver.RegisterAgent(ctx, repo, token, tt.ip, []byte(tt.info))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got node %v and error %v, want %v", node, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && node.ID == victim.ID {
+				t.Fatalf("enrollment from %s took over node %d", tt.ip, victim.ID)
+			}
+		})
+	}
+
+	// the owner's own token still connects
+	bound := newAgentToken(t, repo, "bound", victim.ID)
+	node, err := tcpserver.RegisterAgent(ctx, repo, bound, "10.0.0.1", []byte(`{"machine_id": "m-1"}`))
+	if err != nil || node.ID != victim.ID {
+		t.Fatalf("bound token got node %v and error %v, want node %d", node, err, victim.ID)
+	}
+}
+
+func TestBoundTokenClaimsNodeWithoutMachineID(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	// a node created before agents reported a machine id
+	legacy, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{Name: sql.NullString{String: "legacy", Valid: true}, Ip: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unbound token from its address enrolls a new node instead of claiming it
+	other, err := tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "unbound", 0), "10.0.0.1", []byte(`{"machine_id": "m-2"}`))
+	if err != nil || other.ID == legacy.ID {
+		t.Fatalf("unbound token got node %v and error %v, want a new node", other, err)
+	}
+
+	// a token bound to another node cannot hand it a machine id that is taken
+	_, err = tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "taken", legacy.ID), "10.0.0.1", []byte(`{"machine_id": "m-2"}`))
+	if !errors.Is(err, tcpserver.ErrNodeAlreadyEnrolled) {
+		t.Fatalf("got error %v, want ErrNodeAlreadyEnrolled", err)
+	}
+
+	node, err := tcpserver.RegisterAgent(ctx, repo, newAgentToken(t, repo, "bound", legacy.ID), "10.0.0.5", []byte(`{"machine_id": "m-1"}`))
+	if err != nil || node.ID != legacy.ID {
+		t.Fatalf("bound token got node %v and error %v, want node %d", node, err, legacy.ID)
+	}
+	claimed, err := repo.Queries.GetNode(ctx, legacy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed.MachineID.String != "m-1" || claimed.Ip != "10.0.0.5" {
+		t.Fatalf("claimed node has machine id %q and ip %s, want m-1 and 10.0.0.5", claimed.MachineID.String, claimed.Ip)
+	}
+}
@@ -0,0 +1,342 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+// Run with go test -race ./test/
+
+// newTestRepo opens empty databases in a temporary directory
+func newTestRepo(t *testing.T) *db.Repo {
+	t.Helper()
+	mainDB, timeseriesDB, err := db.InitializeDatabases(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mainDB.Close()
+		timeseriesDB.Close()
+	})
+	return db.NewRepo(mainDB, timeseriesDB)
+}
+
+func newAlertRepo(t *testing.T, rules int) (*db.Repo, []db.GetActiveAlertsByNodeAndMetricRow) {
+	t.Helper()
+	repo := newTestRepo(t)
+
+	ctx := context.Background()
+	node, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{
+		Name: sql.NullString{String: "test", Valid: true},
+		Ip:   "10.0.0.1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < rules; i++ {
+		_, err := repo.Queries.CreateAlert(ctx, db.CreateAlertParams{
+			NodeID:      node.ID,
+			Metric:      "cpu",
+			Threshold:   sql.NullFloat64{Float64: 80, Valid: true},
+			IsActive:    sql.NullInt64{Int64: 1, Valid: true},
+			Aggregation: tcpserver.AggregationAll,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	alerts, err := repo.Queries.GetActiveAlertsByNodeAndMetric(ctx, db.GetActiveAlertsByNodeAndMetricParams{
+		NodeID: node.ID,
+		Metric: "cpu",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, alerts
+}
+
+// countingNotifier records the notifications an evaluator sends
+type countingNotifier struct {
+	firing   atomic.Int32
+	resolved atomic.Int32
+	sent     chan struct{}
+}
+
+func newCountingNotifier(evaluator *tcpserver.Evaluator) *countingNotifier {
+	n := &countingNotifier{sent: make(chan struct{}, 1024)}
+	evaluator.Notify = func(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, channels []db.NotificationChannel, msg tcpserver.AlertMsg) []tcpserver.NotificationDelivery {
+		if msg.Resolved {
+			n.resolved.Add(1)
+		} else {
+			n.firing.Add(1)
+		}
+		n.sent <- struct{}{}
+		return nil
+	}
+	return n
+}
+
+// wait blocks until count notifications went out
+func (n *countingNotifier) wait(t *testing.T, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		select {
+		case <-n.sent:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d notifications, want %d", i, count)
+		}
+	}
+}
+
+func TestEvaluatorConcurrentSamples(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 5)
+	evaluator := tcpserver.NewEvaluator(repo)
+	notifier := newCountingNotifier(evaluator)
+	ctx := context.Background()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for _, alert := range alerts {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(alert db.GetActiveAlertsByNodeAndMetricRow, i int) {
+				defer wg.Done()
+				msg := tcpserver.AlertMsg{CurrentValue: "95%", Timestamp: now.Add(time.Duration(i) * time.Millisecond)}
+				if _, err := evaluator.Evaluate(ctx, alert, true, 0, msg); err != nil {
+					t.Error(err)
+				}
+			}(alert, i)
+		}
+	}
+	wg.Wait()
+	notifier.wait(t, len(alerts))
+
+	if got := notifier.firing.Load(); got != int32(len(alerts)) {
+		t.Fatalf("got %d firing notifications, want one per rule (%d)", got, len(alerts))
+	}
+	for _, alert := range alerts {
+		state, err := evaluator.State(ctx, alert.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.State != tcpserver.AlertStateFiring {
+			t.Fatalf("alert %d is %s, want firing", alert.ID, state.State)
+		}
+	}
+}
+
+func TestEvaluatorConcurrentResetAndState(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	alert := alerts[0]
+	evaluator := tcpserver.NewEvaluator(repo)
+	newCountingNotifier(evaluator)
+	ctx := context.Background()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			msg := tcpserver.AlertMsg{CurrentValue: fmt.Sprintf("%d%%", i), Timestamp: now.Add(time.Duration(i) * time.Second)}
+			if _, err := evaluator.Evaluate(ctx, alert, i%2 == 0, 0, msg); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := evaluator.State(ctx, alert.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := evaluator.Reset(ctx, alert.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEvaluatorDropsOutOfOrderSamples(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	alert := alerts[0]
+	evaluator := tcpserver.NewEvaluator(repo)
+	notifier := newCountingNotifier(evaluator)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := evaluator.Evaluate(ctx, alert, true, 0, tcpserver.AlertMsg{Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+	notifier.wait(t, 1)
+	// a sample taken before the breach must not resolve it
+	state, err := evaluator.Evaluate(ctx, alert, false, 0, tcpserver.AlertMsg{Timestamp: now.Add(-time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.State != tcpserver.AlertStateFiring {
+		t.Fatalf("alert is %s, want firing", state.State)
+	}
+}
+
+func TestEvaluatorStateSurvivesRestart(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	alert := alerts[0]
+	ctx := context.Background()
+	now := time.Now()
+
+	first := tcpserver.NewEvaluator(repo)
+	notifier := newCountingNotifier(first)
+	if _, err := first.Evaluate(ctx, alert, true, time.Minute, tcpserver.AlertMsg{Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Evaluate(ctx, alert, true, time.Minute, tcpserver.AlertMsg{Timestamp: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	notifier.wait(t, 1)
+
+	// a new evaluator on the same database is a restarted server
+	restarted := tcpserver.NewEvaluator(repo)
+	notifier = newCountingNotifier(restarted)
+	state, err := restarted.Evaluate(ctx, alert, true, time.Minute, tcpserver.AlertMsg{Timestamp: now.Add(2 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.State != tcpserver.AlertStateFiring {
+		t.Fatalf("alert is %s after restart, want firing", state.State)
+	}
+	if state.BreachedAt.Int64 != now.Unix() {
+		t.Fatalf("breach started at %d after restart, want %d", state.BreachedAt.Int64, now.Unix())
+	}
+
+	if _, err := restarted.Evaluate(ctx, alert, false, time.Minute, tcpserver.AlertMsg{Timestamp: now.Add(3 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	notifier.wait(t, 1)
+	if notifier.firing.Load() != 0 || notifier.resolved.Load() != 1 {
+		t.Fatalf("got %d firing and %d resolved notifications after restart, want 0 and 1", notifier.firing.Load(), notifier.resolved.Load())
+	}
+}
+
+func TestCheckSystemStatKeepsNewestSample(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	alert := alerts[0]
+	ctx := context.Background()
+	now := time.Now()
+
+	// samples arrive in order but are checked concurrently, the newest one is below the threshold
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cpu := 10.0
+			if i%2 == 0 {
+				cpu = 95
+			}
+			stat := tcpserver.SystemStat{CPUUsage: []float64{cpu}}
+			tcpserver.CheckSystemStat(ctx, repo, int32(alert.NodeID), stat, now.Add(time.Duration(i)*time.Second))
+		}(i)
+	}
+	wg.Wait()
+
+	state, err := tcpserver.AlertEvaluator(repo).State(ctx, alert.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.State == tcpserver.AlertStateFiring {
+		t.Fatal("an older breached sample left the alert firing after the newest sample recovered")
+	}
+}
+
+func TestEvaluatorNotifiesInOrder(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	alert := alerts[0]
+	evaluator := tcpserver.NewEvaluator(repo)
+	ctx := context.Background()
+	now := time.Now()
+
+	var mu sync.Mutex
+	var order []bool
+	done := make(chan struct{}, 2)
+	evaluator.Notify = func(ctx context.Context, alert db.GetActiveAlertsByNodeAndMetricRow, channels []db.NotificationChannel, msg tcpserver.AlertMsg) []tcpserver.NotificationDelivery {
+		// a slow trigger, as when a channel retries, must still go out before the resolve
+		if !msg.Resolved {
+			time.Sleep(100 * time.Millisecond)
+		}
+		mu.Lock()
+		order = append(order, msg.Resolved)
+		mu.Unlock()
+		done <- struct{}{}
+		return nil
+	}
+
+	if _, err := evaluator.Evaluate(ctx, alert, true, 0, tcpserver.AlertMsg{Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := evaluator.Evaluate(ctx, alert, false, 0, tcpserver.AlertMsg{Timestamp: now.Add(time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("notifications were not sent")
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if order[0] || !order[1] {
+		t.Fatalf("got notifications in order %v (resolved), want the trigger first", order)
+	}
+}
+
+func TestEvaluatorResolveSendsRecovery(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	evaluator := tcpserver.NewEvaluator(repo)
+	notifier := newCountingNotifier(evaluator)
+	ctx := context.Background()
+
+	if _, err := evaluator.Evaluate(ctx, alerts[0], true, 0, tcpserver.AlertMsg{Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	notifier.wait(t, 1)
+
+	alert, err := repo.Queries.GetAlert(ctx, alerts[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := evaluator.Resolve(ctx, alert, "Rule disabled"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.wait(t, 1)
+	if notifier.resolved.Load() != 1 {
+		t.Fatalf("got %d recovery messages, want 1", notifier.resolved.Load())
+	}
+
+	// a rule that is not firing resolves silently
+	if err := evaluator.Resolve(ctx, alert, "Rule disabled"); err != nil {
+		t.Fatal(err)
+	}
+	state, err := evaluator.State(ctx, alert.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.State == tcpserver.AlertStateFiring {
+		t.Fatal("the rule is still firing after it was resolved")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if notifier.resolved.Load() != 1 {
+		t.Fatalf("got %d recovery messages, want 1", notifier.resolved.Load())
+	}
+}
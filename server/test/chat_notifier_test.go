@@ -0,0 +1,129 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+// attemptLogger is implemented by notifiers that keep their HTTP attempts
+type attemptLogger interface {
+	Attempts() []tcpserver.WebhookAttempt
+}
+
+func TestChatNotifiers(t *testing.T) {
+	msg := tcpserver.AlertMsg{
+		NodeName:     "web-1",
+		NodeIp:       "10.0.0.1",
+		Metric:       "cpu",
+		Threshold:    "80%",
+		CurrentValue: "95%",
+		Timestamp:    time.Now(),
+	}
+
+	tests := []struct {
+		channelType string
+		config      func(url string) map[string]interface{}
+		check       func(t *testing.T, req recordedRequest)
+	}{
+		{
+			channelType: "telegram",
+			config: func(url string) map[string]interface{} {
+				return map[string]interface{}{"base_url": url, "bot_token": "123:abc", "chat_id": "-100"}
+			},
+			check: func(t *testing.T, req recordedRequest) {
+				if req.path != "/bot123:abc/sendMessage" || req.body["chat_id"] != "-100" || req.body["parse_mode"] != "HTML" {
+					t.Fatalf("unexpected telegram request %s %v", req.path, req.body)
+				}
+				if !strings.Contains(req.body["text"].(string), "<code>95%</code>") {
+					t.Fatalf("telegram text misses the current value: %v", req.body["text"])
+				}
+			},
+		},
+		{
+			channelType: "teams",
+			config: func(url string) map[string]interface{} {
+				return map[string]interface{}{"webhook_url": url + "/workflow"}
+			},
+			check: func(t *testing.T, req recordedRequest) {
+				attachments, _ := req.body["attachments"].([]interface{})
+				if req.path != "/workflow" || len(attachments) != 1 {
+					t.Fatalf("unexpected teams request %s %v", req.path, req.body)
+				}
+				attachment := attachments[0].(map[string]interface{})
+				card := attachment["content"].(map[string]interface{})
+				if attachment["contentType"] != "application/vnd.microsoft.card.adaptive" || card["type"] != "AdaptiveCard" {
+					t.Fatalf("teams attachment is not an Adaptive Card: %v", attachment)
+				}
+			},
+		},
+		{
+			channelType: "ntfy",
+			config: func(url string) map[string]interface{} {
+				return map[string]interface{}{"base_url": url, "topic": "alerts", "token": "tk"}
+			},
+			check: func(t *testing.T, req recordedRequest) {
+				if req.path != "/" || req.body["topic"] != "alerts" || req.body["priority"] != float64(4) {
+					t.Fatalf("unexpected ntfy request %s %v", req.path, req.body)
+				}
+				if req.header.Get("Authorization") != "Bearer tk" {
+					t.Fatalf("ntfy request has authorization %q", req.header.Get("Authorization"))
+				}
+			},
+		},
+		{
+			channelType: "gotify",
+			config: func(url string) map[string]interface{} {
+				return map[string]interface{}{"base_url": url + "/gotify/", "token": "app"}
+			},
+			check: func(t *testing.T, req recordedRequest) {
+				if req.path != "/gotify/message" || req.body["priority"] != float64(8) {
+					t.Fatalf("unexpected gotify request %s %v", req.path, req.body)
+				}
+				if req.header.Get("X-Gotify-Key") != "app" {
+					t.Fatalf("gotify request has key %q", req.header.Get("X-Gotify-Key"))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channelType, func(t *testing.T) {
+			server := newRecordingServer(t, nil)
+			notifier, err := tcpserver.NewNotifier(tt.channelType, webhookConfig(t, tt.config(server.URL)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := notifier.Send(msg); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, server.last(t))
+		})
+	}
+}
+
+func TestTelegramNotifierHidesToken(t *testing.T) {
+	server := newRecordingServer(t, nil)
+	notifier, err := tcpserver.NewNotifier("telegram", webhookConfig(t, map[string]interface{}{
+		"base_url":  server.URL,
+		"bot_token": "123:secret",
+		"chat_id":   "1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Send(tcpserver.AlertMsg{Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	logger, ok := notifier.(attemptLogger)
+	if !ok {
+		t.Fatal("telegram notifier keeps no attempts")
+	}
+	for _, attempt := range logger.Attempts() {
+		if strings.Contains(attempt.URL, "secret") {
+			t.Fatalf("delivery log URL %s contains the bot token", attempt.URL)
+		}
+	}
+}
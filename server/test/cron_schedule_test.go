@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// a Sunday
+	from := time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		year := 2026
+		if month < time.October {
+			year = 2027
+		}
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", at(time.October, 18, 10, 31)},
+		{"*/15 * * * *", at(time.October, 18, 10, 45)},
+		{"5/20 * * * *", at(time.October, 18, 10, 45)},
+		{"0 9-17/4 * * *", at(time.October, 18, 13, 0)},
+		{"0 0 1 */2 *", at(time.November, 1, 0, 0)},
+		{"0 0 1 jan-mar *", at(time.January, 1, 0, 0)},
+		{"0 0 * * 1-5", at(time.October, 19, 0, 0)},
+		// day of month and day of week are ORed when both are restricted
+		{"0 0 13 * *", at(time.November, 13, 0, 0)},
+		{"0 0 * * fri", at(time.October, 23, 0, 0)},
+		{"0 0 13 * fri", at(time.October, 23, 0, 0)},
+		{"0 0 1 * mon", at(time.October, 19, 0, 0)},
+		// a stepped star still counts as unrestricted, so both have to match
+		{"0 0 */10 * mon", at(time.December, 21, 0, 0)},
+		// 7 and sun are Sunday like 0
+		{"0 12 * * 0", at(time.October, 18, 12, 0)},
+		{"0 12 * * 7", at(time.October, 18, 12, 0)},
+		{"0 12 * * SUN", at(time.October, 18, 12, 0)},
+		{"0 0 * * 7", at(time.October, 25, 0, 0)},
+		{"@hourly", at(time.October, 18, 11, 0)},
+		{"@daily", at(time.October, 19, 0, 0)},
+		{"@weekly", at(time.October, 25, 0, 0)},
+		{"@monthly", at(time.November, 1, 0, 0)},
+		{"@yearly", at(time.January, 1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := utils.ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"0 0 * foo *",
+		"@reboot",
+		"0 0 30 2 *",
+	} {
+		if _, err := utils.ParseCronSchedule(expr); err == nil {
+			t.Errorf("%q was accepted", expr)
+		}
+	}
+}
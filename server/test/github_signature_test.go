@@ -0,0 +1,44 @@
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/sanda0/vps_pilot/internal/utils"
+)
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "s3cret", body, valid, true},
+		{"wrong secret", "other", body, valid, false},
+		{"empty secret", "", body, valid, false},
+		{"tampered body", "s3cret", []byte(`{"ref":"refs/heads/prod"}`), valid, false},
+		{"missing signature", "s3cret", body, "", false},
+		{"sha1 signature", "s3cret", body, strings.Replace(valid, "sha256=", "sha1=", 1), false},
+		{"no prefix", "s3cret", body, strings.TrimPrefix(valid, "sha256="), false},
+		{"not hex", "s3cret", body, "sha256=zz", false},
+		{"truncated", "s3cret", body, valid[:len(valid)-2], false},
+		{"uppercase hex", "s3cret", body, "sha256=" + strings.ToUpper(strings.TrimPrefix(valid, "sha256=")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utils.VerifyGitHubSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Fatalf("VerifyGitHubSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
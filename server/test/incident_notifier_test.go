@@ -0,0 +1,109 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+// sendIncident sends a firing message, a reminder and the resolve of one rule on one node
+func sendIncident(t *testing.T, notifier tcpserver.Notifier) tcpserver.AlertMsg {
+	t.Helper()
+	msg := tcpserver.AlertMsg{
+		NodeName:     "web-1",
+		Metric:       "cpu",
+		Threshold:    "80%",
+		CurrentValue: "95%",
+		Timestamp:    time.Now(),
+		AlertID:      7,
+		NodeID:       3,
+	}
+	for i := 0; i < 2; i++ {
+		if err := notifier.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolved := msg
+	resolved.Resolved, resolved.Lasted = true, 5*time.Minute
+	if err := notifier.Send(resolved); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestPagerDutyNotifierTriggersAndResolves(t *testing.T) {
+	server := newRecordingServer(t, func(w http.ResponseWriter, n int) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	notifier, err := tcpserver.NewNotifier("pagerduty", webhookConfig(t, map[string]interface{}{
+		"base_url":    server.URL,
+		"routing_key": "rk",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := sendIncident(t, notifier)
+
+	got := server.requests()
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3", len(got))
+	}
+	actions := []string{"trigger", "trigger", "resolve"}
+	for i, req := range got {
+		if req.path != "/v2/enqueue" || req.body["routing_key"] != "rk" {
+			t.Fatalf("unexpected pagerduty request %s %v", req.path, req.body)
+		}
+		if req.body["event_action"] != actions[i] || req.body["dedup_key"] != msg.DedupKey() {
+			t.Fatalf("event %d is %v with key %v, want %s with %s", i, req.body["event_action"], req.body["dedup_key"], actions[i], msg.DedupKey())
+		}
+	}
+	payload, _ := got[0].body["payload"].(map[string]interface{})
+	if payload["severity"] != "critical" || payload["source"] != "web-1" {
+		t.Fatalf("unexpected trigger payload %v", payload)
+	}
+}
+
+func TestOpsgenieNotifierCreatesAndCloses(t *testing.T) {
+	server := newRecordingServer(t, func(w http.ResponseWriter, n int) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	notifier, err := tcpserver.NewNotifier("opsgenie", webhookConfig(t, map[string]interface{}{
+		"base_url": server.URL,
+		"api_key":  "key",
+		"priority": "P2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := sendIncident(t, notifier)
+
+	got := server.requests()
+	if len(got) != 3 {
+		t.Fatalf("got %d requests, want 3", len(got))
+	}
+	for _, req := range got[:2] {
+		if req.path != "/v2/alerts" || req.body["alias"] != msg.DedupKey() || req.body["priority"] != "P2" {
+			t.Fatalf("unexpected opsgenie create %s %v", req.path, req.body)
+		}
+		if req.header.Get("Authorization") != "GenieKey key" {
+			t.Fatalf("opsgenie request has authorization %q", req.header.Get("Authorization"))
+		}
+	}
+	if got[2].path != "/v2/alerts/"+msg.DedupKey()+"/close" || got[2].query != "identifierType=alias" {
+		t.Fatalf("unexpected opsgenie close %s?%s", got[2].path, got[2].query)
+	}
+}
+
+func TestDedupKeyPerRuleAndNode(t *testing.T) {
+	a := tcpserver.AlertMsg{AlertID: 1, NodeID: 1, CurrentValue: "90%"}
+	b := tcpserver.AlertMsg{AlertID: 1, NodeID: 1, CurrentValue: "95%", Resolved: true}
+	c := tcpserver.AlertMsg{AlertID: 1, NodeID: 2}
+	if a.DedupKey() != b.DedupKey() {
+		t.Fatalf("messages of one incident have keys %s and %s", a.DedupKey(), b.DedupKey())
+	}
+	if a.DedupKey() == c.DedupKey() {
+		t.Fatalf("different nodes share the key %s", a.DedupKey())
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+)
+
+// TestLegacyTargetsBecomeChannels runs the legacy channel migration down and up
+// again with targets on alerts and cron jobs in between
+func TestLegacyTargetsBecomeChannels(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	migration := "../internal/db/sql/migrations/20261018000023_legacy_channels"
+	down, err := os.ReadFile(migration + ".down.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, err := os.ReadFile(migration + ".up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.OperationalDB.Exec(string(down)); err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{Ip: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slack, err := repo.Queries.CreateNotificationChannel(ctx, db.CreateNotificationChannelParams{
+		Name:   "ops slack",
+		Type:   "slack",
+		Config: `{"webhook_url":"https://hooks.slack.com/services/ops"}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := []string{
+		`INSERT INTO alerts (id, node_id, metric, duration, email, discord_webhook, slack_webhook) VALUES (1, ?, 'cpu', 0, 'ops@example.com', 'https://discord.com/api/webhooks/1', '')`,
+		`INSERT INTO alerts (id, node_id, metric, duration, email) VALUES (2, ?, 'mem', 0, 'ops@example.com')`,
+		`INSERT INTO cron_jobs (id, node_id, name, schedule, command, email, slack_webhook) VALUES (1, ?, 'backup', '@daily', 'true', 'ops@example.com', 'https://hooks.slack.com/services/ops')`,
+	}
+	for _, query := range legacy {
+		if _, err := repo.OperationalDB.Exec(query, node.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := repo.OperationalDB.Exec(string(up)); err != nil {
+		t.Fatal(err)
+	}
+
+	channels, err := repo.Queries.ListNotificationChannels(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 3 {
+		t.Fatalf("got %d channels, want the existing slack channel plus one email and one discord channel", len(channels))
+	}
+	types := func(channels []db.NotificationChannel) map[string]int64 {
+		ids := map[string]int64{}
+		for _, channel := range channels {
+			ids[channel.Type] = channel.ID
+		}
+		return ids
+	}
+
+	first, err := repo.Queries.ListAlertChannels(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.Queries.ListAlertChannels(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := repo.Queries.ListCronJobChannels(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstIds, secondIds, jobIds := types(first), types(second), types(job)
+	if len(first) != 2 || firstIds["email"] == 0 || firstIds["discord"] == 0 {
+		t.Fatalf("alert 1 got channels %v, want email and discord", firstIds)
+	}
+	if len(second) != 1 || secondIds["email"] != firstIds["email"] {
+		t.Fatalf("alert 2 got channels %v, want the email channel of alert 1", secondIds)
+	}
+	if len(job) != 2 || jobIds["email"] != firstIds["email"] || jobIds["slack"] != slack.ID {
+		t.Fatalf("cron job got channels %v, want the shared email channel and the existing slack channel", jobIds)
+	}
+}
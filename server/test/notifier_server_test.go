@@ -0,0 +1,66 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordedRequest is one request a fake notification API received
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	raw    []byte
+	body   map[string]interface{}
+}
+
+// recordingServer is a fake notification API that keeps every request it receives
+type recordingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	received []recordedRequest
+}
+
+// newRecordingServer starts a recordingServer. respond, when set, writes the
+// response to the nth request (counting from 1), otherwise it is a 200.
+func newRecordingServer(t *testing.T, respond func(w http.ResponseWriter, n int)) *recordingServer {
+	t.Helper()
+	s := &recordingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := recordedRequest{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, header: r.Header, raw: data}
+		if err := json.Unmarshal(data, &req.body); err != nil {
+			t.Errorf("body is not JSON: %s", data)
+		}
+		s.mu.Lock()
+		s.received = append(s.received, req)
+		n := len(s.received)
+		s.mu.Unlock()
+		if respond != nil {
+			respond(w, n)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *recordingServer) requests() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest{}, s.received...)
+}
+
+// last returns the latest request, failing the test when there was none
+func (s *recordingServer) last(t *testing.T) recordedRequest {
+	t.Helper()
+	requests := s.requests()
+	if len(requests) == 0 {
+		t.Fatal("the server received no request")
+	}
+	return requests[len(requests)-1]
+}
@@ -0,0 +1,36 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/dto"
+	"github.com/sanda0/vps_pilot/internal/services"
+)
+
+func TestProjectDeployPathIsValidated(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	node, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{Ip: "10.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects := services.NewProjectService(repo, ctx)
+	project, err := projects.CreateProject(&dto.CreateProjectRequest{Name: "app", NodeID: int32(node.ID), DeployPath: "/var/www/app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, deployPath := range []string{"", "/", "//", "/.", "/var/..", "var/www/app", "./app"} {
+		_, err := projects.CreateProject(&dto.CreateProjectRequest{Name: "bad", NodeID: int32(node.ID), DeployPath: deployPath})
+		if !errors.Is(err, services.ErrInvalidDeployPath) {
+			t.Errorf("creating with deploy path %q got error %v, want ErrInvalidDeployPath", deployPath, err)
+		}
+		_, err = projects.UpdateProject(project.ID, &dto.UpdateProjectRequest{Name: "app", Branch: "main", DeployPath: deployPath})
+		if !errors.Is(err, services.ErrInvalidDeployPath) {
+			t.Errorf("updating to deploy path %q got error %v, want ErrInvalidDeployPath", deployPath, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+func discovered(paths ...string) []tcpserver.DiscoveredProject {
+	projects := make([]tcpserver.DiscoveredProject, len(paths))
+	for i, path := range paths {
+		projects[i] = tcpserver.DiscoveredProject{Path: path, Config: tcpserver.ProjectConfig{Tech: []string{path}}}
+	}
+	return projects
+}
+
+func TestDiscoveredConfigsOfADeployedProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		paths      []string
+		wantConfig string   // directory the deployed project is updated from
+		wantNew    []string // deploy paths of the projects created next to it
+	}{
+		{
+			name:       "current wins over the releases",
+			paths:      []string{"/var/www/app/releases/10", "/var/www/app/current", "/var/www/app/releases/9", "/var/www/app/repo"},
+			wantConfig: "/var/www/app/current",
+		},
+		{
+			name:       "newest release without current",
+			paths:      []string{"/var/www/app/releases/9", "/var/www/app/releases/10"},
+			wantConfig: "/var/www/app/releases/10",
+		},
+		{
+			name:       "other directories are projects of their own",
+			paths:      []string{"/var/www/app", "/var/www/app/packages/api", "/var/www/app/releases/10/packages/web"},
+			wantConfig: "/var/www/app",
+			wantNew:    []string{"/var/www/app/packages/api", "/var/www/app/releases/10/packages/web"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+			node, err := repo.Queries.CreateNode(ctx, db.CreateNodeParams{Ip: "10.0.0.1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			app, err := repo.Queries.CreateDiscoveredProject(ctx, db.CreateDiscoveredProjectParams{
+				Name:       "app",
+				NodeID:     node.ID,
+				DeployPath: "/var/www/app",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tcpserver.ReconcileDiscoveredProjects(ctx, repo, int32(node.ID), discovered(tt.paths...)); err != nil {
+				t.Fatal(err)
+			}
+
+			projects, err := repo.Queries.ListNodeProjects(ctx, node.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			created := map[string]bool{}
+			for _, project := range projects {
+				if project.ID == app.ID {
+					if want := tt.wantConfig + "/config.vpspilot.json"; project.ConfigPath.String != want {
+						t.Fatalf("app config is %s, want %s", project.ConfigPath.String, want)
+					}
+					continue
+				}
+				created[project.DeployPath] = true
+			}
+			if len(created) != len(tt.wantNew) {
+				t.Fatalf("created projects at %v, want %v", created, tt.wantNew)
+			}
+			for _, path := range tt.wantNew {
+				if !created[path] {
+					t.Fatalf("created projects at %v, want one at %s", created, path)
+				}
+			}
+		})
+	}
+}
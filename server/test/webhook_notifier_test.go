@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sanda0/vps_pilot/internal/db"
+	"github.com/sanda0/vps_pilot/internal/tcpserver"
+)
+
+func webhookConfig(t *testing.T, config map[string]interface{}) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestWebhookNotifierTemplateAndSignature(t *testing.T) {
+	server := newRecordingServer(t, nil)
+	notifier, err := tcpserver.NewNotifier("webhook", webhookConfig(t, map[string]interface{}{
+		"url":              server.URL,
+		"method":           "put",
+		"headers":          map[string]string{"Authorization": "Bearer token"},
+		"body":             `{"text": {{json .NodeName}}, "value": "{{.CurrentValue}}", "status": "{{.Status}}"}`,
+		"secret":           "s3cret",
+		"signature_header": "X-Signature",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = notifier.Send(tcpserver.AlertMsg{NodeName: `web "1"`, CurrentValue: "95%", Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.last(t)
+	if auth := req.header.Get("Authorization"); req.method != http.MethodPut || auth != "Bearer token" {
+		t.Fatalf("got method %s and authorization %q, want PUT and the configured header", req.method, auth)
+	}
+	want := `{"text": "web \"1\"", "value": "95%", "status": "firing"}`
+	if string(req.raw) != want {
+		t.Fatalf("got body %s, want %s", req.raw, want)
+	}
+	if signature := req.header.Get("X-Signature"); signature != tcpserver.SignWebhookBody("s3cret", req.raw) {
+		t.Fatalf("got signature %s, want the HMAC-SHA256 of the body", signature)
+	}
+}
+
+func TestWebhookNotifierRejectsInvalidTemplate(t *testing.T) {
+	_, err := tcpserver.NewNotifier("webhook", webhookConfig(t, map[string]interface{}{
+		"url":  "http://localhost",
+		"body": `{"text": {{.NodeName}}}`,
+	}))
+	if err == nil {
+		t.Fatal("a template rendering invalid JSON was accepted")
+	}
+}
+
+func TestWebhookNotifierRetriesAndLogsDeliveries(t *testing.T) {
+	server := newRecordingServer(t, func(w http.ResponseWriter, n int) {
+		if n < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	})
+
+	repo, _ := newAlertRepo(t, 0)
+	ctx := context.Background()
+	channel, err := repo.Queries.CreateNotificationChannel(ctx, db.CreateNotificationChannelParams{
+		Name:   "hook",
+		Type:   "webhook",
+		Config: string(webhookConfig(t, map[string]interface{}{"url": server.URL, "backoff_ms": 1})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivery := tcpserver.SendToChannel(ctx, repo, channel, tcpserver.AlertMsg{Timestamp: time.Now()})
+	if delivery.Status != "sent" {
+		t.Fatalf("delivery %s: %s, want sent after retries", delivery.Status, delivery.Error)
+	}
+	if got := len(server.requests()); got != 3 {
+		t.Fatalf("got %d requests, want 3", got)
+	}
+	log, err := repo.Queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{ChannelID: channel.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 3 || log[0].StatusCode.Int64 != 200 || log[2].StatusCode.Int64 != 503 {
+		t.Fatalf("got %d logged attempts, want 3 ending with a 200", len(log))
+	}
+}
+
+func TestWebhookNotifierDoesNotRetryClientErrors(t *testing.T) {
+	server := newRecordingServer(t, func(w http.ResponseWriter, n int) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	})
+
+	notifier, err := tcpserver.NewNotifier("webhook", webhookConfig(t, map[string]interface{}{
+		"url":        server.URL,
+		"backoff_ms": 1,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Send(tcpserver.AlertMsg{Timestamp: time.Now()}); err == nil {
+		t.Fatal("a 400 response was treated as delivered")
+	}
+	if got := len(server.requests()); got != 1 {
+		t.Fatalf("got %d requests, want 1", got)
+	}
+}
+
+func TestAlertChannelsAreSentConcurrently(t *testing.T) {
+	repo, alerts := newAlertRepo(t, 1)
+	ctx := context.Background()
+
+	// each channel holds its request until the other one arrived, which only
+	// happens when they are sent at the same time
+	arrived := make(chan struct{}, 2)
+	release := make(chan struct{})
+	for _, name := range []string{"first", "second"} {
+		server := newRecordingServer(t, func(w http.ResponseWriter, n int) {
+			arrived <- struct{}{}
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+			}
+		})
+		channel, err := repo.Queries.CreateNotificationChannel(ctx, db.CreateNotificationChannelParams{
+			Name:   name,
+			Type:   "webhook",
+			Config: string(webhookConfig(t, map[string]interface{}{"url": server.URL, "max_attempts": 1})),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = repo.Queries.AddAlertChannel(ctx, db.AddAlertChannelParams{AlertID: alerts[0].ID, ChannelID: channel.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evaluator := tcpserver.NewEvaluator(repo)
+	if _, err := evaluator.Evaluate(ctx, alerts[0], true, 0, tcpserver.AlertMsg{Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	defer close(release)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-arrived:
+		case <-time.After(2 * time.Second):
+			t.Fatal("the channels of a rule were sent one after the other")
+		}
+	}
+}